@@ -1,18 +1,42 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"reflect"
+	"strings"
+	"time"
+	// Event timezones must resolve in images without system zoneinfo
+	_ "time/tzdata"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"snapShare/config"
+	"snapShare/graph"
+	"snapShare/grpcapi"
 	"snapShare/handlers"
+	"snapShare/infra/auth"
+	"snapShare/infra/azure"
+	"snapShare/infra/cache"
+	"snapShare/infra/captcha"
 	"snapShare/infra/database"
+	"snapShare/infra/gcs"
+	"snapShare/infra/localfs"
+	"snapShare/infra/logging"
+	"snapShare/infra/mail"
 	"snapShare/infra/r2"
+	"snapShare/infra/storage"
+	"snapShare/infra/tracing"
+	"snapShare/routes"
 	"snapShare/services"
+	"snapShare/utils"
+	"snapShare/version"
 )
 
 // CustomValidator wraps the validator
@@ -24,81 +48,333 @@ func (cv *CustomValidator) Validate(i any) error {
 	return cv.validator.Struct(i)
 }
 
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// runBackupCommand takes a backup, lists backups or restores one
+func runBackupCommand(ctx context.Context, backupService *services.BackupService, cfg *config.Config, args []string) error {
+	if len(cfg.BackupEncryptionKey) == 0 {
+		return fmt.Errorf("BACKUP_ENCRYPTION_KEY is required")
+	}
+
+	switch {
+	case args[0] == "backup" && len(args) == 1:
+		key, err := backupService.CreateBackup(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+	case args[0] == "backups" && len(args) == 1:
+		keys, err := backupService.ListBackups(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			fmt.Println(key)
+		}
+	case args[0] == "restore" && len(args) == 2:
+		if err := backupService.Restore(ctx, args[1]); err != nil {
+			return err
+		}
+		slog.Info("database restored", "key", args[1])
+	default:
+		return fmt.Errorf("usage: backup | backups | restore <key>")
+	}
+
+	return nil
+}
+
 func main() {
+	logging.Setup()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load configuration:", err)
+		fatal("Failed to load configuration", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    cfg.OTLPTracesEndpoint,
+		Headers:     cfg.OTLPHeaders,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
+
+	// Owner access tokens are signed with JWT_SECRET
+	utils.InitJWT(cfg.JWTSecret)
+
+	// Initialize object storage
+	var objectStorage storage.Storage
+	var localStorage *localfs.LocalStorage
+	switch cfg.StorageProvider {
+	case "gcs":
+		objectStorage, err = gcs.NewGCSService(context.Background(), cfg.GCSBucketName, cfg.GCSPublicDomain)
+	case "azure":
+		objectStorage, err = azure.NewBlobService(cfg.AzureStorageAccount, cfg.AzureStorageKey, cfg.AzureStorageContainer,
+			cfg.AzureStorageEndpoint, cfg.AzurePublicDomain)
+	case "local":
+		localStorage, err = localfs.NewLocalStorage(cfg.LocalStorageDir, cfg.APIBaseURL+"/storage", []byte(cfg.JWTSecret))
+		objectStorage = localStorage
+	default:
+		objectStorage, err = r2.NewR2Service(cfg.R2AccountID, cfg.R2AccessKey, cfg.R2SecretAccessKey, cfg.R2BucketName, cfg.R2PublicDomain,
+			r2.RetryPolicy{
+				MaxAttempts: cfg.StorageRetryMaxAttempts,
+				MaxBackoff:  time.Duration(cfg.StorageRetryMaxBackoffMS) * time.Millisecond,
+			})
+	}
+	if err != nil {
+		fatal("Failed to initialize storage", err)
+	}
+
+	backupService := services.NewBackupService(objectStorage, cfg.DatabaseURL, cfg.BackupEncryptionKey,
+		time.Duration(cfg.BackupIntervalHours)*time.Hour, cfg.BackupRetentionCount)
+
+	// Backup commands run instead of the server: backup, backups, restore <key>
+	if len(os.Args) > 1 {
+		if err := runBackupCommand(context.Background(), backupService, cfg, os.Args[1:]); err != nil {
+			fatal("Backup command failed", err)
+		}
+		return
 	}
 
 	// Initialize database
-	db, err := database.Connect(cfg.DatabaseURL)
+	db, err := database.Connect(database.Config{
+		URL:                cfg.DatabaseURL,
+		Driver:             cfg.DBDriver,
+		MaxIdleConns:       cfg.DBMaxIdleConns,
+		MaxOpenConns:       cfg.DBMaxOpenConns,
+		ConnMaxLifetime:    time.Duration(cfg.DBConnMaxLifetimeMinutes) * time.Minute,
+		StatementTimeout:   time.Duration(cfg.DBStatementTimeoutMS) * time.Millisecond,
+		StatementCacheSize: cfg.DBStatementCacheSize,
+		LogLevel:           cfg.DBLogLevel,
+		CreateBatchSize:    cfg.DBCreateBatchSize,
+	})
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("Failed to connect to database", err)
 	}
 
 	// Run database migrations
 	if err := database.Migrate(db); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		fatal("Failed to migrate database", err)
 	}
 
 	// Seed database with sample data (skip in production)
 	if os.Getenv("SKIP_SEED") != "true" {
 		if err := database.Seed(db); err != nil {
-			log.Fatal("Failed to seed database:", err)
+			fatal("Failed to seed database", err)
 		}
 	} else {
-		log.Println("Skipping database seeding (SKIP_SEED=true)")
+		slog.Info("Skipping database seeding (SKIP_SEED=true)")
+	}
+
+	// Let the bucket expire old archives and abort abandoned multipart uploads
+	if r2Service, ok := objectStorage.(*r2.R2Service); ok {
+		err := r2Service.PutLifecycleRules(context.Background(), r2.LifecycleRules{
+			ArchivePrefix:      services.ArchivePrefix,
+			ArchiveExpiryDays:  int32(cfg.ArchiveRetentionDays),
+			AbortMultipartDays: int32(cfg.MultipartAbortDays),
+		})
+		if err != nil {
+			slog.Warn("failed to apply bucket lifecycle rules", "error", err)
+		}
 	}
 
-	// Initialize R2 service
-	r2Service, err := r2.NewR2Service(cfg.R2AccountID, cfg.R2AccessKey, cfg.R2SecretAccessKey, cfg.R2BucketName, cfg.R2PublicDomain)
+	// Fail fast while storage is down rather than tying up request handlers
+	objectStorage = storage.NewBreaker(objectStorage, cfg.StorageBreakerThreshold,
+		time.Duration(cfg.StorageBreakerCooldownSeconds)*time.Second)
+
+	mailer := mail.NewMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	captchaVerifier := captcha.NewVerifier(cfg.TurnstileSecretKey)
+
+	lookupCache, err := cache.NewCache(cfg.RedisURL)
 	if err != nil {
-		log.Fatal("Failed to initialize R2 service:", err)
+		fatal("Failed to initialize cache", err)
 	}
 
 	// Initialize services
-	sessionService := services.NewSessionService(db)
-	eventService := services.NewEventService(db)
-	photoService := services.NewPhotoService(db, r2Service)
+	webhookService := services.NewWebhookService(db)
+	inboundWebhookService := services.NewInboundWebhookService(db, cfg.InboundWebhookSecrets)
+	sessionService := services.NewSessionService(db, lookupCache, webhookService, time.Duration(cfg.SessionTTLHours)*time.Hour, cfg.SessionSlidingExpiry)
+	eventService := services.NewEventService(db, objectStorage, lookupCache, webhookService, cfg.AppBaseURL, cfg.EventCodeLength, cfg.EventCodeCharset)
+	galleryFeed := services.NewGalleryFeed(lookupCache)
+	mediaPool := services.NewMediaPool(services.MediaPoolConfig{
+		MaxConcurrent: cfg.MediaMaxConcurrent,
+		QueueDepth:    cfg.MediaQueueDepth,
+		MaxJobBytes:   int64(cfg.MediaMaxJobMB) << 20,
+	})
+	photoService := services.NewPhotoService(db, objectStorage, galleryFeed, webhookService, mediaPool)
+	archiveService := services.NewArchiveService(db, objectStorage, mailer, cfg.ArchiveRetentionDays)
+	metricsService := services.NewMetricsService(db)
+	auditService := services.NewAuditService(db)
+	adminService := services.NewAdminService(db, photoService, auditService)
+	wrapUpService := services.NewWrapUpService(db, eventService, archiveService, mailer, cfg.WrapUpDelayDays)
+	collaboratorService := services.NewCollaboratorService(db, eventService, mailer)
+	objectDeletionService := services.NewObjectDeletionService(db, objectStorage)
+	retentionService := services.NewRetentionService(db, objectStorage, mailer, cfg.DeletionGraceDays)
+	statsService := services.NewStatsService(db, photoService)
+	analyticsService := services.NewAnalyticsService(db, lookupCache)
+	themeService := services.NewThemeService(db, objectStorage)
+	guestbookService := services.NewGuestbookService(db)
+	banService := services.NewBanService(db, sessionService)
+	alertService := services.NewAlertService(db, mailer)
+	authService := services.NewAuthService(db, eventService, mailer)
+	consentService := services.NewConsentService(db)
+	healthService := services.NewHealthService(db, objectStorage)
+
+	// OAuth providers, enabled when configured. Redirect URLs stay unversioned
+	// so the ones registered with the providers keep working.
+	oauthProviders := auth.Providers{}
+	if cfg.GoogleClientID != "" {
+		oauthProviders["google"] = auth.NewGoogleProvider(cfg.GoogleClientID, cfg.GoogleClientSecret,
+			cfg.APIBaseURL+"/api/auth/oauth/google/callback")
+	}
+	if cfg.AppleClientID != "" {
+		appleProvider, err := auth.NewAppleProvider(cfg.AppleClientID, cfg.AppleTeamID, cfg.AppleKeyID, cfg.ApplePrivateKey,
+			cfg.APIBaseURL+"/api/auth/oauth/apple/callback")
+		if err != nil {
+			fatal("Failed to initialize Apple sign-in", err)
+		}
+		oauthProviders["apple"] = appleProvider
+	}
 
 	// Initialize handlers
-	sessionHandler := handlers.NewSessionHandler(sessionService, eventService)
-	eventHandler := handlers.NewEventHandler(eventService)
-	photoHandler := handlers.NewPhotoHandler(photoService)
+	sessionHandler := handlers.NewSessionHandler(sessionService, eventService, auditService, captchaVerifier)
+	eventHandler := handlers.NewEventHandler(eventService, archiveService, themeService, auditService)
+	photoHandler := handlers.NewPhotoHandler(photoService, eventService, auditService)
+	galleryStreamHandler := handlers.NewGalleryStreamHandler(galleryFeed, photoService)
+	archiveHandler := handlers.NewArchiveHandler(archiveService)
+	metricsHandler := handlers.NewMetricsHandler(metricsService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	wrapUpHandler := handlers.NewWrapUpHandler(wrapUpService)
+	collaboratorHandler := handlers.NewCollaboratorHandler(collaboratorService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	themeHandler := handlers.NewThemeHandler(themeService)
+	guestbookHandler := handlers.NewGuestbookHandler(guestbookService, auditService)
+	banHandler := handlers.NewBanHandler(banService, auditService)
+	alertHandler := handlers.NewAlertHandler(alertService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, auditService)
+	inboundWebhookHandler := handlers.NewInboundWebhookHandler(inboundWebhookService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	graphQLHandler := handlers.NewGraphQLHandler(graph.NewResolver(eventService, photoService, statsService, collaboratorService))
+	consentHandler := handlers.NewConsentHandler(consentService)
+	signOutHandler := handlers.NewSignOutHandler(authService, sessionService, auditService)
+	publicHandler := handlers.NewPublicHandler(photoService)
+	authHandler := handlers.NewAuthHandler(authService, oauthProviders)
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	// Background jobs
+	go eventService.WatchInvalidations(context.Background())
+	go sessionService.WatchInvalidations(context.Background())
+	go galleryFeed.Watch(context.Background())
+	go wrapUpService.Run(context.Background(), 10*time.Minute)
+	go archiveService.Run(context.Background(), time.Minute)
+	go retentionService.Run(context.Background(), time.Hour)
+	go objectDeletionService.Run(context.Background(), time.Minute)
+	go alertService.Run(context.Background(), 5*time.Minute)
+	go webhookService.Run(context.Background(), 15*time.Second)
+	go inboundWebhookService.Run(context.Background(), time.Hour)
+	if len(cfg.BackupEncryptionKey) > 0 {
+		go backupService.Run(context.Background(), db, 10*time.Minute)
+	}
 
 	// Initialize Echo
 	e := echo.New()
 
-	// Set validator
-	e.Validator = &CustomValidator{validator: validator.New()}
+	// Client IPs drive rate limits, sessions and the audit log, so forwarding
+	// headers are only believed from the configured proxies
+	if len(cfg.TrustedProxies) == 0 {
+		e.IPExtractor = echo.ExtractIPDirect()
+	} else {
+		trust := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+		for _, ipNet := range cfg.TrustedProxies {
+			trust = append(trust, echo.TrustIPRange(ipNet))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(trust...)
+	}
 
-	// Middleware
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	// Set validator. Fields are reported by their JSON name in error responses.
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "query", "param", "form"} {
+			if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	e.Validator = &CustomValidator{validator: validate}
 
-	// Routes
-	api := e.Group("/api")
+	// Errors are returned as application/problem+json with a stable code
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
 
-	// Session routes
-	api.POST("/sessions", sessionHandler.CreateSession)
-	api.POST("/sessions/refresh", sessionHandler.RefreshSession)
-	api.DELETE("/sessions", sessionHandler.RevokeSession)
+	// Middleware
+	e.Use(otelecho.Middleware(cfg.TracingServiceName, otelecho.WithSkipper(func(c echo.Context) bool {
+		return c.Path() == "/healthz" || c.Path() == "/readyz" || c.Path() == "/metrics" || c.Path() == "/version"
+	})))
+	e.Use(handlers.RequestID())
+	e.Use(handlers.Language())
+	e.Use(handlers.RequestLogger())
+	e.Use(middleware.Recover())
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		// Lets the frontend show the ID of a failed request
+		ExposeHeaders: []string{echo.HeaderXRequestID},
+	}))
+	// Large gallery listings are mostly repetitive JSON
+	e.Use(handlers.Compress())
 
-	// Event routes
-	api.POST("/events", eventHandler.CreateEvent)
-	api.GET("/events/:code", eventHandler.GetEventByCode)
+	// Unversioned /api paths keep working as deprecated aliases of the current
+	// version. New versions get their own group (e.g. /api/v2); v1 routes they
+	// replace are wrapped with handlers.Deprecated.
+	e.Pre(handlers.UnversionedAPIRewrite("/api/docs"))
 
-	// Photo routes (using actual handler methods) - require authentication
-	photoAPI := api.Group("/photos", sessionHandler.AuthMiddleware())
-	photoAPI.POST("/upload-url", photoHandler.GenerateUploadURL)
-	photoAPI.POST("/confirm/:id", photoHandler.ConfirmUpload)
+	routes.Register(e, cfg, routes.Handlers{
+		Auth:           authHandler,
+		Session:        sessionHandler,
+		Event:          eventHandler,
+		Photo:          photoHandler,
+		GalleryStream:  galleryStreamHandler,
+		Archive:        archiveHandler,
+		Metrics:        metricsHandler,
+		Admin:          adminHandler,
+		WrapUp:         wrapUpHandler,
+		Collaborator:   collaboratorHandler,
+		Stats:          statsHandler,
+		Analytics:      analyticsHandler,
+		Theme:          themeHandler,
+		Guestbook:      guestbookHandler,
+		Ban:            banHandler,
+		Alert:          alertHandler,
+		Webhook:        webhookHandler,
+		Audit:          auditHandler,
+		GraphQL:        graphQLHandler,
+		Consent:        consentHandler,
+		SignOut:        signOutHandler,
+		Public:         publicHandler,
+		Health:         healthHandler,
+		InboundWebhook: inboundWebhookHandler,
+	}, collaboratorService, localStorage)
 
-	// Health check
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{"status": "ok"})
-	})
+	// Internal gRPC API for tooling and sync clients, sharing the services above
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			fatal("Failed to listen for gRPC", err)
+		}
+		grpcServer := grpcapi.NewServer(cfg.AdminAPIKeys, eventService, sessionService, photoService, galleryFeed)
+		go func() {
+			slog.Info("gRPC server starting", "port", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal("gRPC server stopped", err)
+			}
+		}()
+	}
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -106,6 +382,10 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(e.Start(":" + port))
+	slog.Info("Server starting", "port", port, "version", version.Short())
+	if err := e.Start(":" + port); err != nil {
+		// Flush the spans still buffered before exiting
+		_ = shutdownTracing(context.Background())
+		fatal("Server stopped", err)
+	}
 }
@@ -1,34 +1,468 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"strings"
 )
 
+// RateLimitRule allows Burst requests at once, then PerMinute requests a
+// minute; a zero PerMinute disables the limit
+type RateLimitRule struct {
+	PerMinute int
+	Burst     int
+}
+
 type Config struct {
 	Port        string
 	DatabaseURL string
 	JWTSecret   string
 
+	// Database pool sizing and limits; DBConnMaxLifetimeMinutes and
+	// DBStatementTimeoutMS are disabled when 0. DBDriver is "stdlib" or
+	// "pgxpool", DBStatementCacheSize the prepared statements kept per
+	// connection (0 for none) and DBLogLevel one of silent, error, warn or info.
+	DBDriver                 string
+	DBStatementCacheSize     int
+	DBMaxIdleConns           int
+	DBMaxOpenConns           int
+	DBConnMaxLifetimeMinutes int
+	DBStatementTimeoutMS     int
+	DBLogLevel               string
+	// DBCreateBatchSize caps the rows of one INSERT when records are created
+	// together, keeping large batches under PostgreSQL's parameter limit
+	DBCreateBatchSize int
+
+	// StorageProvider selects where objects are stored: "r2" (default, also
+	// used for MinIO), "gcs", "azure" or "local"
+	StorageProvider string
+
 	R2AccountID       string
 	R2AccessKey       string
 	R2SecretAccessKey string
 	R2BucketName      string
 	R2PublicDomain    string
+	// R2 calls failing with transient errors are retried up to
+	// StorageRetryMaxAttempts times in all, waiting at most
+	// StorageRetryMaxBackoffMS between attempts
+	StorageRetryMaxAttempts  int
+	StorageRetryMaxBackoffMS int
+	// After StorageBreakerThreshold storage calls in a row fail, calls are
+	// refused for StorageBreakerCooldownSeconds instead of each waiting out
+	// its retries
+	StorageBreakerThreshold       int
+	StorageBreakerCooldownSeconds int
+
+	// Google Cloud Storage; credentials come from Application Default
+	// Credentials. GCSPublicDomain defaults to storage.googleapis.com.
+	GCSBucketName   string
+	GCSPublicDomain string
+
+	// Azure Blob Storage; AzureStorageEndpoint overrides the account's blob
+	// endpoint (e.g. Azurite) and AzurePublicDomain defaults to the container URL
+	AzureStorageAccount   string
+	AzureStorageKey       string
+	AzureStorageContainer string
+	AzureStorageEndpoint  string
+	AzurePublicDomain     string
+
+	// LocalStorageDir is where the local provider keeps objects; they are
+	// served under APIBaseURL/storage with URLs signed by JWTSecret
+	LocalStorageDir string
+
+	// AppBaseURL is the public frontend URL used to build guest join links
+	AppBaseURL string
+	// APIBaseURL is the public URL of this API, used for OAuth redirect URLs
+	APIBaseURL string
+
+	// TrustedProxies are the CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header is believed. Without any, the client IP is the
+	// peer address and forwarding headers are ignored, so clients cannot
+	// choose the IP they are rate limited and audited by.
+	TrustedProxies []*net.IPNet
+
+	// AdminAPIKeys guard /api/admin; admin endpoints are disabled when empty.
+	// Several keys can be active at once so they can be rotated without downtime.
+	AdminAPIKeys []string
+	// GRPCPort serves the internal gRPC API, which accepts the admin API
+	// keys; the gRPC server is not started when empty
+	GRPCPort string
+	// InboundWebhookSecrets holds the HMAC secrets of each source allowed to
+	// post signed callbacks to /api/webhooks/inbound/:source. A source may
+	// have several secrets so they can be rotated.
+	InboundWebhookSecrets map[string][]string
+
+	// OAuth sign-in; each provider is enabled only when its settings are present
+	GoogleClientID     string
+	GoogleClientSecret string
+	AppleClientID      string
+	AppleTeamID        string
+	AppleKeyID         string
+	ApplePrivateKey    string
+
+	// TurnstileSecretKey verifies guest joins on events that require a captcha;
+	// verification is skipped when empty
+	TurnstileSecretKey string
+
+	// RedisURL enables caching of event and session lookups; disabled when empty
+	RedisURL string
+
+	// OpenTelemetry tracing; spans are exported over OTLP/HTTP to
+	// OTLPTracesEndpoint (e.g. http://localhost:4318/v1/traces), disabled when empty
+	OTLPTracesEndpoint string
+	OTLPHeaders        map[string]string
+	TracingServiceName string
+	TracingSampleRatio float64
+
+	// SMTP settings; emails are only logged when SMTPHost is empty
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// WrapUpDelayDays is how many days after event_date the wrap-up workflow runs
+	WrapUpDelayDays int
+
+	// DeletionGraceDays is how long a deleted event's files stay in storage
+	DeletionGraceDays int
+
+	// BackupEncryptionKey (hex, 32 bytes) enables encrypted database backups to
+	// the storage provider every BackupIntervalHours, keeping the newest
+	// BackupRetentionCount of them
+	BackupEncryptionKey  []byte
+	BackupIntervalHours  int
+	BackupRetentionCount int
+
+	// ArchiveRetentionDays is how long archive zips are kept before the bucket
+	// expires them; MultipartAbortDays is when the bucket aborts incomplete
+	// multipart uploads. Both are applied as R2 lifecycle rules on startup.
+	ArchiveRetentionDays int
+	MultipartAbortDays   int
+
+	// Images are decoded and rendered by at most MediaMaxConcurrent jobs at
+	// once with up to MediaQueueDepth more waiting; further jobs get 503.
+	// A job may decode an image of at most MediaMaxJobMB megabytes.
+	MediaMaxConcurrent int
+	MediaQueueDepth    int
+	MediaMaxJobMB      int
+
+	// SessionTTLHours is the default guest session lifetime; events may override it.
+	// With SessionSlidingExpiry, sessions in use are extended automatically.
+	SessionTTLHours      int
+	SessionSlidingExpiry bool
+
+	// Per-minute request limits and bursts; 0 disables a limit
+	SessionRateLimit          int // POST /sessions, per client IP
+	SessionRateBurst          int
+	UploadURLIPRateLimit      int // POST /photos/upload-url, per client IP
+	UploadURLSessionRateLimit int // POST /photos/upload-url, per guest session
+	UploadURLRateBurst        int
+
+	// API-wide limits: all clients together, per client IP and per bearer
+	// token. RouteRateLimits limits single routes per client IP, keyed by
+	// "METHOD /path" as registered (e.g. "GET /api/v1/events/:code").
+	GlobalRateLimit RateLimitRule
+	IPRateLimit     RateLimitRule
+	TokenRateLimit  RateLimitRule
+	RouteRateLimits map[string]RateLimitRule
+
+	// EventCodeLength and EventCodeCharset shape newly generated event codes
+	EventCodeLength  int
+	EventCodeCharset string
 }
 
+// defaultEventCodeCharset leaves out characters that are easily confused (0/O, 1/I)
+const defaultEventCodeCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
 func Load() (*Config, error) {
 	config := &Config{
 		Port:        os.Getenv("PORT"),
 		DatabaseURL: os.Getenv("DATABASE_URL"),
 		JWTSecret:   os.Getenv("JWT_SECRET"),
+		GRPCPort:    os.Getenv("GRPC_PORT"),
+
+		DBDriver:   strings.ToLower(os.Getenv("DB_DRIVER")),
+		DBLogLevel: strings.ToLower(os.Getenv("DB_LOG_LEVEL")),
+
+		StorageProvider: os.Getenv("STORAGE_PROVIDER"),
 
 		R2AccountID:       os.Getenv("R2_ACCOUNT_ID"),
 		R2AccessKey:       os.Getenv("R2_ACCESS_KEY"),
 		R2SecretAccessKey: os.Getenv("R2_SECRET_ACCESS_KEY"),
 		R2BucketName:      os.Getenv("R2_BUCKET_NAME"),
 		R2PublicDomain:    os.Getenv("R2_PUBLIC_DOMAIN"),
+
+		GCSBucketName:   os.Getenv("GCS_BUCKET_NAME"),
+		GCSPublicDomain: os.Getenv("GCS_PUBLIC_DOMAIN"),
+
+		AzureStorageAccount:   os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		AzureStorageKey:       os.Getenv("AZURE_STORAGE_KEY"),
+		AzureStorageContainer: os.Getenv("AZURE_STORAGE_CONTAINER"),
+		AzureStorageEndpoint:  os.Getenv("AZURE_STORAGE_ENDPOINT"),
+		AzurePublicDomain:     os.Getenv("AZURE_PUBLIC_DOMAIN"),
+
+		LocalStorageDir: os.Getenv("LOCAL_STORAGE_DIR"),
+
+		AppBaseURL: os.Getenv("APP_BASE_URL"),
+		APIBaseURL: os.Getenv("API_BASE_URL"),
+
+		GoogleClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		AppleClientID:      os.Getenv("APPLE_CLIENT_ID"),
+		AppleTeamID:        os.Getenv("APPLE_TEAM_ID"),
+		AppleKeyID:         os.Getenv("APPLE_KEY_ID"),
+		ApplePrivateKey:    os.Getenv("APPLE_PRIVATE_KEY"),
+
+		TurnstileSecretKey: os.Getenv("TURNSTILE_SECRET_KEY"),
+
+		RedisURL: os.Getenv("REDIS_URL"),
+
+		OTLPTracesEndpoint: os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		TracingServiceName: os.Getenv("OTEL_SERVICE_NAME"),
+
+		SessionSlidingExpiry: os.Getenv("SESSION_SLIDING_EXPIRY") == "true",
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     os.Getenv("MAIL_FROM"),
+
+		EventCodeCharset: os.Getenv("EVENT_CODE_CHARSET"),
+	}
+
+	// ADMIN_API_KEY is the original single-key setting and is still honoured
+	config.AdminAPIKeys = splitList(os.Getenv("ADMIN_API_KEYS"))
+	if key := strings.TrimSpace(os.Getenv("ADMIN_API_KEY")); key != "" {
+		config.AdminAPIKeys = append(config.AdminAPIKeys, key)
+	}
+
+	for _, cidr := range splitList(os.Getenv("TRUSTED_PROXIES")) {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: invalid CIDR %q", cidr)
+		}
+		config.TrustedProxies = append(config.TrustedProxies, ipNet)
+	}
+
+	dbMaxIdle, err := getEnvInt("DB_MAX_IDLE_CONNS", 10)
+	if err != nil {
+		return nil, err
+	}
+	config.DBMaxIdleConns = dbMaxIdle
+
+	dbMaxOpen, err := getEnvInt("DB_MAX_OPEN_CONNS", 100)
+	if err != nil {
+		return nil, err
+	}
+	config.DBMaxOpenConns = dbMaxOpen
+
+	dbConnLifetime, err := getEnvInt("DB_CONN_MAX_LIFETIME_MINUTES", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.DBConnMaxLifetimeMinutes = dbConnLifetime
+
+	dbStatementTimeout, err := getEnvInt("DB_STATEMENT_TIMEOUT_MS", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.DBStatementTimeoutMS = dbStatementTimeout
+
+	dbStatementCache, err := getEnvInt("DB_STATEMENT_CACHE_SIZE", 512)
+	if err != nil {
+		return nil, err
+	}
+	config.DBStatementCacheSize = dbStatementCache
+
+	dbCreateBatch, err := getEnvInt("DB_CREATE_BATCH_SIZE", 1000)
+	if err != nil {
+		return nil, err
+	}
+	config.DBCreateBatchSize = dbCreateBatch
+
+	wrapUpDelay, err := getEnvInt("WRAPUP_DELAY_DAYS", 3)
+	if err != nil {
+		return nil, err
+	}
+	config.WrapUpDelayDays = wrapUpDelay
+
+	deletionGrace, err := getEnvInt("EVENT_DELETION_GRACE_DAYS", 7)
+	if err != nil {
+		return nil, err
+	}
+	config.DeletionGraceDays = deletionGrace
+
+	if v := os.Getenv("BACKUP_ENCRYPTION_KEY"); v != "" {
+		key, err := hex.DecodeString(v)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("BACKUP_ENCRYPTION_KEY must be 64 hex characters")
+		}
+		config.BackupEncryptionKey = key
+	}
+
+	backupInterval, err := getEnvInt("BACKUP_INTERVAL_HOURS", 24)
+	if err != nil {
+		return nil, err
+	}
+	config.BackupIntervalHours = backupInterval
+
+	backupRetention, err := getEnvInt("BACKUP_RETENTION_COUNT", 7)
+	if err != nil {
+		return nil, err
+	}
+	config.BackupRetentionCount = backupRetention
+
+	archiveRetention, err := getEnvInt("ARCHIVE_RETENTION_DAYS", 7)
+	if err != nil {
+		return nil, err
+	}
+	config.ArchiveRetentionDays = archiveRetention
+
+	multipartAbort, err := getEnvInt("MULTIPART_ABORT_DAYS", 1)
+	if err != nil {
+		return nil, err
+	}
+	config.MultipartAbortDays = multipartAbort
+
+	mediaConcurrent, err := getEnvInt("MEDIA_MAX_CONCURRENT", 4)
+	if err != nil {
+		return nil, err
+	}
+	config.MediaMaxConcurrent = mediaConcurrent
+
+	mediaQueue, err := getEnvInt("MEDIA_QUEUE_DEPTH", 32)
+	if err != nil {
+		return nil, err
+	}
+	config.MediaQueueDepth = mediaQueue
+
+	mediaJobMB, err := getEnvInt("MEDIA_MAX_JOB_MB", 256)
+	if err != nil {
+		return nil, err
+	}
+	config.MediaMaxJobMB = mediaJobMB
+
+	sessionTTL, err := getEnvInt("SESSION_TTL_HOURS", 24)
+	if err != nil {
+		return nil, err
+	}
+	config.SessionTTLHours = sessionTTL
+
+	sessionRate, err := getEnvInt("SESSION_RATE_LIMIT", 10)
+	if err != nil {
+		return nil, err
+	}
+	config.SessionRateLimit = sessionRate
+
+	sessionBurst, err := getEnvInt("SESSION_RATE_BURST", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.SessionRateBurst = sessionBurst
+
+	uploadURLIPRate, err := getEnvInt("UPLOAD_URL_IP_RATE_LIMIT", 300)
+	if err != nil {
+		return nil, err
+	}
+	config.UploadURLIPRateLimit = uploadURLIPRate
+
+	uploadURLSessionRate, err := getEnvInt("UPLOAD_URL_SESSION_RATE_LIMIT", 60)
+	if err != nil {
+		return nil, err
+	}
+	config.UploadURLSessionRateLimit = uploadURLSessionRate
+
+	uploadURLBurst, err := getEnvInt("UPLOAD_URL_RATE_BURST", 20)
+	if err != nil {
+		return nil, err
+	}
+	config.UploadURLRateBurst = uploadURLBurst
+
+	globalRate, err := getEnvRateLimit("GLOBAL_RATE_LIMIT", "GLOBAL_RATE_BURST", RateLimitRule{})
+	if err != nil {
+		return nil, err
+	}
+	config.GlobalRateLimit = globalRate
+
+	ipRate, err := getEnvRateLimit("IP_RATE_LIMIT", "IP_RATE_BURST", RateLimitRule{PerMinute: 600, Burst: 100})
+	if err != nil {
+		return nil, err
+	}
+	config.IPRateLimit = ipRate
+
+	tokenRate, err := getEnvRateLimit("TOKEN_RATE_LIMIT", "TOKEN_RATE_BURST", RateLimitRule{PerMinute: 300, Burst: 60})
+	if err != nil {
+		return nil, err
+	}
+	config.TokenRateLimit = tokenRate
+
+	// Event lookups by code are unauthenticated, so keep them from being
+	// enumerated unless ROUTE_RATE_LIMITS is set, even to an empty value
+	routeLimits, ok := os.LookupEnv("ROUTE_RATE_LIMITS")
+	if !ok {
+		routeLimits = "GET /api/v1/events/:code=30:10"
+	}
+	routeRates, err := parseRouteRateLimits(routeLimits)
+	if err != nil {
+		return nil, err
+	}
+	config.RouteRateLimits = routeRates
+
+	storageRetryAttempts, err := getEnvInt("STORAGE_RETRY_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.StorageRetryMaxAttempts = storageRetryAttempts
+
+	storageRetryBackoff, err := getEnvInt("STORAGE_RETRY_MAX_BACKOFF_MS", 5000)
+	if err != nil {
+		return nil, err
+	}
+	config.StorageRetryMaxBackoffMS = storageRetryBackoff
+
+	storageBreakerThreshold, err := getEnvInt("STORAGE_BREAKER_THRESHOLD", 5)
+	if err != nil {
+		return nil, err
 	}
+	config.StorageBreakerThreshold = storageBreakerThreshold
+
+	storageBreakerCooldown, err := getEnvInt("STORAGE_BREAKER_COOLDOWN_SECONDS", 30)
+	if err != nil {
+		return nil, err
+	}
+	config.StorageBreakerCooldownSeconds = storageBreakerCooldown
+
+	codeLength, err := getEnvInt("EVENT_CODE_LENGTH", 8)
+	if err != nil {
+		return nil, err
+	}
+	config.EventCodeLength = codeLength
+
+	otlpHeaders, err := parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_HEADERS: %w", err)
+	}
+	config.OTLPHeaders = otlpHeaders
+
+	inboundSecrets, err := parseKeyValues(os.Getenv("INBOUND_WEBHOOK_SECRETS"))
+	if err != nil {
+		return nil, fmt.Errorf("INBOUND_WEBHOOK_SECRETS: %w", err)
+	}
+	config.InboundWebhookSecrets = make(map[string][]string, len(inboundSecrets))
+	for source, secrets := range inboundSecrets {
+		config.InboundWebhookSecrets[source] = strings.Split(secrets, "|")
+	}
+
+	sampleRatio, err := getEnvFloat("OTEL_TRACES_SAMPLER_ARG", 1)
+	if err != nil {
+		return nil, err
+	}
+	config.TracingSampleRatio = sampleRatio
 
 	if err := config.validate(); err != nil {
 		return nil, err
@@ -44,20 +478,41 @@ func (c *Config) validate() error {
 	if c.JWTSecret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
 	}
-	if c.R2AccountID == "" {
-		return fmt.Errorf("R2_ACCOUNT_ID is required")
+
+	if c.DBMaxOpenConns < 1 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be at least 1")
+	}
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.DBConnMaxLifetimeMinutes < 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME_MINUTES must not be negative")
 	}
-	if c.R2AccessKey == "" {
-		return fmt.Errorf("R2_ACCESS_KEY is required")
+	if c.DBStatementTimeoutMS < 0 {
+		return fmt.Errorf("DB_STATEMENT_TIMEOUT_MS must not be negative")
 	}
-	if c.R2SecretAccessKey == "" {
-		return fmt.Errorf("R2_SECRET_ACCESS_KEY is required")
+	if c.DBStatementCacheSize < 0 {
+		return fmt.Errorf("DB_STATEMENT_CACHE_SIZE must not be negative")
 	}
-	if c.R2BucketName == "" {
-		return fmt.Errorf("R2_BUCKET_NAME is required")
+	if c.DBCreateBatchSize < 1 {
+		return fmt.Errorf("DB_CREATE_BATCH_SIZE must be at least 1")
 	}
-	if c.R2PublicDomain == "" {
-		return fmt.Errorf("R2_PUBLIC_DOMAIN is required")
+	switch c.DBDriver {
+	case "":
+		c.DBDriver = "stdlib"
+	case "stdlib", "pgxpool":
+	default:
+		return fmt.Errorf("DB_DRIVER must be stdlib or pgxpool")
+	}
+	switch c.DBLogLevel {
+	case "":
+		c.DBLogLevel = "info"
+	case "silent", "error", "warn", "info":
+	default:
+		return fmt.Errorf("DB_LOG_LEVEL must be silent, error, warn or info")
+	}
+	if err := c.validateStorage(); err != nil {
+		return err
 	}
 
 	// Set default port if not provided
@@ -65,5 +520,216 @@ func (c *Config) validate() error {
 		c.Port = "8080"
 	}
 
+	if c.AppBaseURL == "" {
+		c.AppBaseURL = "http://localhost:3000"
+	}
+	if c.APIBaseURL == "" {
+		c.APIBaseURL = "http://localhost:8080"
+	}
+
+	if c.SMTPPort == "" {
+		c.SMTPPort = "587"
+	}
+	if c.MailFrom == "" {
+		c.MailFrom = "no-reply@snapshare.local"
+	}
+
+	if c.TracingServiceName == "" {
+		c.TracingServiceName = "snapshare-api"
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+	}
+
+	if c.BackupIntervalHours < 1 {
+		return fmt.Errorf("BACKUP_INTERVAL_HOURS must be at least 1")
+	}
+	if c.BackupRetentionCount < 1 {
+		return fmt.Errorf("BACKUP_RETENTION_COUNT must be at least 1")
+	}
+
+	if c.ArchiveRetentionDays < 1 {
+		return fmt.Errorf("ARCHIVE_RETENTION_DAYS must be at least 1")
+	}
+	if c.MultipartAbortDays < 1 {
+		return fmt.Errorf("MULTIPART_ABORT_DAYS must be at least 1")
+	}
+
+	if c.MediaMaxConcurrent < 1 {
+		return fmt.Errorf("MEDIA_MAX_CONCURRENT must be at least 1")
+	}
+	if c.MediaQueueDepth < 0 {
+		return fmt.Errorf("MEDIA_QUEUE_DEPTH must not be negative")
+	}
+	if c.MediaMaxJobMB < 1 {
+		return fmt.Errorf("MEDIA_MAX_JOB_MB must be at least 1")
+	}
+
+	if c.SessionTTLHours < 1 {
+		return fmt.Errorf("SESSION_TTL_HOURS must be at least 1")
+	}
+
+	if c.EventCodeLength < 4 || c.EventCodeLength > 16 {
+		return fmt.Errorf("EVENT_CODE_LENGTH must be between 4 and 16")
+	}
+	if c.EventCodeCharset == "" {
+		c.EventCodeCharset = defaultEventCodeCharset
+	}
+	c.EventCodeCharset = strings.ToUpper(c.EventCodeCharset)
+	if len(c.EventCodeCharset) < 10 {
+		return fmt.Errorf("EVENT_CODE_CHARSET must contain at least 10 characters")
+	}
+
+	return nil
+}
+
+func (c *Config) validateStorage() error {
+	if c.StorageBreakerThreshold < 1 {
+		return fmt.Errorf("STORAGE_BREAKER_THRESHOLD must be at least 1")
+	}
+	if c.StorageBreakerCooldownSeconds < 1 {
+		return fmt.Errorf("STORAGE_BREAKER_COOLDOWN_SECONDS must be at least 1")
+	}
+
+	switch c.StorageProvider {
+	case "", "r2":
+		c.StorageProvider = "r2"
+		if c.R2AccountID == "" {
+			return fmt.Errorf("R2_ACCOUNT_ID is required")
+		}
+		if c.R2AccessKey == "" {
+			return fmt.Errorf("R2_ACCESS_KEY is required")
+		}
+		if c.R2SecretAccessKey == "" {
+			return fmt.Errorf("R2_SECRET_ACCESS_KEY is required")
+		}
+		if c.R2BucketName == "" {
+			return fmt.Errorf("R2_BUCKET_NAME is required")
+		}
+		if c.R2PublicDomain == "" {
+			return fmt.Errorf("R2_PUBLIC_DOMAIN is required")
+		}
+		if c.StorageRetryMaxAttempts < 1 {
+			return fmt.Errorf("STORAGE_RETRY_MAX_ATTEMPTS must be at least 1")
+		}
+		if c.StorageRetryMaxBackoffMS < 0 {
+			return fmt.Errorf("STORAGE_RETRY_MAX_BACKOFF_MS must not be negative")
+		}
+	case "gcs":
+		if c.GCSBucketName == "" {
+			return fmt.Errorf("GCS_BUCKET_NAME is required")
+		}
+	case "azure":
+		if c.AzureStorageAccount == "" {
+			return fmt.Errorf("AZURE_STORAGE_ACCOUNT is required")
+		}
+		if c.AzureStorageKey == "" {
+			return fmt.Errorf("AZURE_STORAGE_KEY is required")
+		}
+		if c.AzureStorageContainer == "" {
+			return fmt.Errorf("AZURE_STORAGE_CONTAINER is required")
+		}
+	case "local":
+		if c.LocalStorageDir == "" {
+			c.LocalStorageDir = "./data/storage"
+		}
+	default:
+		return fmt.Errorf("STORAGE_PROVIDER must be r2, gcs, azure or local")
+	}
+
 	return nil
 }
+
+// getEnvInt reads an integer environment variable, falling back to def when unset
+func getEnvInt(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	return n, nil
+}
+
+// getEnvFloat reads a decimal environment variable, falling back to def when unset
+func getEnvFloat(key string, def float64) (float64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return f, nil
+}
+
+// getEnvRateLimit reads a per-minute limit and its burst
+func getEnvRateLimit(rateKey, burstKey string, def RateLimitRule) (RateLimitRule, error) {
+	perMinute, err := getEnvInt(rateKey, def.PerMinute)
+	if err != nil {
+		return RateLimitRule{}, err
+	}
+	burst, err := getEnvInt(burstKey, def.Burst)
+	if err != nil {
+		return RateLimitRule{}, err
+	}
+	return RateLimitRule{PerMinute: perMinute, Burst: burst}, nil
+}
+
+// parseRouteRateLimits parses "METHOD /path=perMinute[:burst]" pairs
+func parseRouteRateLimits(v string) (map[string]RateLimitRule, error) {
+	pairs, err := parseKeyValues(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ROUTE_RATE_LIMITS: %w", err)
+	}
+
+	rules := make(map[string]RateLimitRule, len(pairs))
+	for route, limit := range pairs {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			return nil, fmt.Errorf("invalid ROUTE_RATE_LIMITS route %q: want \"METHOD /path\"", route)
+		}
+
+		perMinuteStr, burstStr, hasBurst := strings.Cut(limit, ":")
+		perMinute, err := strconv.Atoi(perMinuteStr)
+		if err != nil || perMinute < 0 {
+			return nil, fmt.Errorf("invalid ROUTE_RATE_LIMITS limit %q for %s", limit, route)
+		}
+		burst := perMinute
+		if hasBurst {
+			if burst, err = strconv.Atoi(burstStr); err != nil || burst < 0 {
+				return nil, fmt.Errorf("invalid ROUTE_RATE_LIMITS burst %q for %s", limit, route)
+			}
+		}
+
+		rules[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = RateLimitRule{PerMinute: perMinute, Burst: burst}
+	}
+	return rules, nil
+}
+
+// parseKeyValues parses a comma-separated list of key=value pairs
+func parseKeyValues(v string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range splitList(v) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", item)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return pairs, nil
+}
+
+// splitList parses a comma-separated environment value, dropping empty items
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
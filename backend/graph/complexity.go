@@ -0,0 +1,17 @@
+package graph
+
+import "snapShare/pagination"
+
+// Complexity weighs the lists of a query by the page size they may return,
+// so that the complexity limit bounds the rows a query can fetch rather than
+// the fields it names
+func Complexity() ComplexityRoot {
+	var complexity ComplexityRoot
+	complexity.Query.Events = func(childComplexity int, _ *string, first *int64, _ *string) int {
+		return 1 + childComplexity*pagination.ClampLimit(int(deref(first)), defaultEventPageSize, maxEventPageSize)
+	}
+	complexity.Event.Photos = func(childComplexity int, _ *string, _ *string, _ *string, first *int64, _ *string) int {
+		return 1 + childComplexity*pagination.ClampLimit(int(deref(first)), defaultPhotoPageSize, maxPhotoPageSize)
+	}
+	return complexity
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"snapShare/graph/model"
+	"snapShare/models"
+	"snapShare/services"
+)
+
+func toEvent(event *models.Event) *model.Event {
+	return &model.Event{
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              string(event.Status),
+		CloseAt:             event.CloseAt,
+		GalleryVisibility:   string(event.GalleryVisibility),
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		CreatedAt:           event.CreatedAt,
+	}
+}
+
+func toPhoto(photo *models.Photo, url string) *model.Photo {
+	return &model.Photo{
+		ID:           photo.ID.String(),
+		UploaderName: photo.UploaderName,
+		URL:          url,
+		FileSize:     photo.Size,
+		MimeType:     photo.MimeType,
+		Width:        int64Value(photo.Width),
+		Height:       int64Value(photo.Height),
+		Caption:      photo.Caption,
+		TakenAt:      photo.TakenAt,
+		ConfirmedAt:  photo.ConfirmedAt,
+		CreatedAt:    photo.CreatedAt,
+	}
+}
+
+func toEventStats(stats *services.EventStats) *model.EventStats {
+	response := &model.EventStats{
+		PhotoCount:      stats.PhotoCount,
+		TotalBytes:      stats.TotalBytes,
+		Uploaders:       stats.Uploaders,
+		InFlightUploads: stats.InFlightUploads,
+		LastActivityAt:  stats.LastActivityAt,
+		Timezone:        stats.Location.String(),
+		UploadsPerHour:  make([]*model.UploadBucket, len(stats.UploadsPerHour)),
+		UploadsPerDay:   make([]*model.UploadBucket, len(stats.UploadsPerDay)),
+	}
+	for i, bucket := range stats.UploadsPerHour {
+		response.UploadsPerHour[i] = &model.UploadBucket{Start: bucket.Hour, Count: bucket.Count}
+	}
+	for i, bucket := range stats.UploadsPerDay {
+		response.UploadsPerDay[i] = &model.UploadBucket{Start: bucket.Day, Count: bucket.Count}
+	}
+	return response
+}
+
+func toPageInfo(nextCursor string) *model.PageInfo {
+	if nextCursor == "" {
+		return &model.PageInfo{}
+	}
+	return &model.PageInfo{EndCursor: &nextCursor, HasNextPage: true}
+}
+
+func int64Value(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	value := int64(*v)
+	return &value
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"snapShare/services"
+)
+
+const (
+	// loaderWait is how long a batch collects keys after its first load
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch starts a batch early once it holds this many keys
+	loaderMaxBatch = 100
+)
+
+// loader batches the loads that resolvers of one query make concurrently,
+// such as the stats of every event in a list, into a single call of fetch
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	once    sync.Once
+	done    chan struct{}
+	results map[K]V
+	err     error
+}
+
+// Load returns the value of key, fetched together with the keys loaded
+// within loaderWait of the batch's first key
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	batch := l.batch
+	if batch == nil {
+		batch = &loaderBatch[K, V]{done: make(chan struct{})}
+		l.batch = batch
+		time.AfterFunc(loaderWait, func() { l.run(ctx, batch) })
+	}
+	batch.keys = append(batch.keys, key)
+	if len(batch.keys) >= loaderMaxBatch {
+		go l.run(ctx, batch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case <-batch.done:
+	}
+	return batch.results[key], batch.err
+}
+
+// run fetches a batch once, whichever of its timer and size limit comes first
+func (l *loader[K, V]) run(ctx context.Context, batch *loaderBatch[K, V]) {
+	batch.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == batch {
+			l.batch = nil
+		}
+		keys := batch.keys
+		l.mu.Unlock()
+
+		batch.results, batch.err = l.fetch(ctx, keys)
+		close(batch.done)
+	})
+}
+
+// photosKey is an event's photos listed with the arguments of a photos field
+type photosKey struct {
+	eventID uuid.UUID
+	filter  services.PhotoFilter
+}
+
+// loaders are the batch loaders of one GraphQL request
+type loaders struct {
+	stats  *loader[uuid.UUID, *services.EventStats]
+	photos *loader[photosKey, *services.PhotoPage]
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns ctx carrying fresh batch loaders, so that the stats
+// and photos of the events a query lists are each fetched in one query.
+// Every request needs its own, as loaded values are not invalidated.
+func (r *Resolver) WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		stats: &loader[uuid.UUID, *services.EventStats]{fetch: r.statsService.GetEventsStats},
+		photos: &loader[photosKey, *services.PhotoPage]{fetch: func(ctx context.Context, keys []photosKey) (map[photosKey]*services.PhotoPage, error) {
+			// Every photos field of a query may have its own arguments
+			eventIDs := make(map[services.PhotoFilter][]uuid.UUID)
+			for _, key := range keys {
+				eventIDs[key.filter] = append(eventIDs[key.filter], key.eventID)
+			}
+
+			pages := make(map[photosKey]*services.PhotoPage, len(keys))
+			for filter, ids := range eventIDs {
+				byEvent, err := r.photoService.GetPhotosByEvents(ctx, ids, filter)
+				if err != nil {
+					return nil, err
+				}
+				for eventID, page := range byEvent {
+					pages[photosKey{eventID: eventID, filter: filter}] = page
+				}
+			}
+			return pages, nil
+		}},
+	})
+}
+
+func loadersFor(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
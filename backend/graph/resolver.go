@@ -0,0 +1,57 @@
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"context"
+
+	"snapShare/services"
+)
+
+// Resolver serves the GraphQL schema from the same services as the REST API.
+// Queries are scoped to the owner set with WithOwner.
+type Resolver struct {
+	eventService        *services.EventService
+	photoService        *services.PhotoService
+	statsService        *services.StatsService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewResolver(eventService *services.EventService, photoService *services.PhotoService,
+	statsService *services.StatsService, collaboratorService *services.CollaboratorService) *Resolver {
+	return &Resolver{
+		eventService:        eventService,
+		photoService:        photoService,
+		statsService:        statsService,
+		collaboratorService: collaboratorService,
+	}
+}
+
+// Page sizes of the events and photos connections, the same as the REST listings
+const (
+	defaultEventPageSize = 20
+	maxEventPageSize     = 100
+	defaultPhotoPageSize = 100
+	maxPhotoPageSize     = 500
+)
+
+type ownerKey struct{}
+
+// WithOwner returns ctx carrying the signed-in owner
+func WithOwner(ctx context.Context, owner services.Owner) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+func currentOwner(ctx context.Context) services.Owner {
+	owner, _ := ctx.Value(ownerKey{}).(services.Owner)
+	return owner
+}
+
+// deref returns the value of an optional argument, or its zero value
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
@@ -0,0 +1,139 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+	"snapShare/graph/model"
+	"snapShare/models"
+	"snapShare/pagination"
+	"snapShare/services"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Photos is the resolver for the photos field.
+func (r *eventResolver) Photos(ctx context.Context, obj *model.Event, query *string, sort *string, order *string, first *int64, after *string) (*model.PhotoConnection, error) {
+	eventID, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := services.PhotoFilter{
+		Query:  deref(query),
+		Sort:   deref(sort),
+		Order:  deref(order),
+		Limit:  pagination.ClampLimit(int(deref(first)), defaultPhotoPageSize, maxPhotoPageSize),
+		Cursor: deref(after),
+	}
+	switch filter.Sort {
+	case "", "created_at", "taken_at", "size", "uploader":
+	default:
+		return nil, gqlerror.Errorf("invalid sort: %s", filter.Sort)
+	}
+	switch strings.ToLower(filter.Order) {
+	case "", "asc", "desc":
+	default:
+		return nil, gqlerror.Errorf("invalid order: %s", filter.Order)
+	}
+
+	page, err := loadersFor(ctx).photos.Load(ctx, photosKey{eventID: eventID, filter: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.PhotoConnection{
+		Nodes:    make([]*model.Photo, len(page.Photos)),
+		PageInfo: toPageInfo(page.NextCursor),
+	}
+	for i := range page.Photos {
+		connection.Nodes[i] = toPhoto(&page.Photos[i], r.photoService.PublicURL(page.Photos[i].ObjectKey))
+	}
+
+	return connection, nil
+}
+
+// Stats is the resolver for the stats field.
+func (r *eventResolver) Stats(ctx context.Context, obj *model.Event) (*model.EventStats, error) {
+	eventID, err := uuid.Parse(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := loadersFor(ctx).stats.Load(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, services.ErrEventNotFound
+	}
+
+	return toEventStats(stats), nil
+}
+
+// Events is the resolver for the events field.
+func (r *queryResolver) Events(ctx context.Context, status *string, first *int64, after *string) (*model.EventConnection, error) {
+	filter := services.EventFilter{
+		Status: models.EventStatus(deref(status)),
+		Limit:  pagination.ClampLimit(int(deref(first)), defaultEventPageSize, maxEventPageSize),
+		Cursor: deref(after),
+	}
+	switch filter.Status {
+	case "", models.EventStatusActive, models.EventStatusInactive, models.EventStatusClosed:
+	default:
+		return nil, gqlerror.Errorf("invalid status: %s", filter.Status)
+	}
+
+	page, err := r.eventService.GetEventsByOwner(ctx, currentOwner(ctx), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.EventConnection{
+		Nodes:      make([]*model.Event, len(page.Events)),
+		TotalCount: page.Total,
+		PageInfo:   toPageInfo(page.NextCursor),
+	}
+	for i := range page.Events {
+		connection.Nodes[i] = toEvent(&page.Events[i])
+	}
+
+	return connection, nil
+}
+
+// Event is the resolver for the event field.
+func (r *queryResolver) Event(ctx context.Context, id string) (*model.Event, error) {
+	eventID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, gqlerror.Errorf("invalid event ID")
+	}
+
+	// Events the owner may not manage look the same as missing ones
+	allowed, err := r.collaboratorService.CanAccessEvent(ctx, eventID, currentOwner(ctx), services.EventCapabilityView)
+	if err != nil || !allowed {
+		return nil, err
+	}
+
+	event, err := r.eventService.GetEventByID(ctx, eventID)
+	if err == services.ErrEventNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toEvent(event), nil
+}
+
+// Event returns EventResolver implementation.
+func (r *Resolver) Event() EventResolver { return &eventResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type eventResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"snapShare/models"
+	"snapShare/pagination"
+	snapsharev1 "snapShare/proto/snapshare/v1"
+	"snapShare/services"
+)
+
+// Page sizes of ListEvents, the same as the REST listing
+const (
+	defaultEventPageSize = 20
+	maxEventPageSize     = 100
+)
+
+type EventServer struct {
+	snapsharev1.UnimplementedEventServiceServer
+	eventService *services.EventService
+}
+
+func NewEventServer(eventService *services.EventService) *EventServer {
+	return &EventServer{
+		eventService: eventService,
+	}
+}
+
+// GetEvent looks an event up by ID or code
+func (s *EventServer) GetEvent(ctx context.Context, req *snapsharev1.GetEventRequest) (*snapsharev1.Event, error) {
+	var event *models.Event
+	switch lookup := req.Lookup.(type) {
+	case *snapsharev1.GetEventRequest_Id:
+		eventID, err := parseID("id", lookup.Id)
+		if err != nil {
+			return nil, err
+		}
+		if event, err = s.eventService.GetEventByID(ctx, eventID); err != nil {
+			return nil, statusError(ctx, err)
+		}
+	case *snapsharev1.GetEventRequest_Code:
+		var err error
+		if event, err = s.eventService.GetEventByCode(ctx, lookup.Code); err != nil {
+			return nil, statusError(ctx, err)
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or code is required")
+	}
+
+	return toEvent(event), nil
+}
+
+// ListEvents returns a page of the events of the owner account with owner_email
+func (s *EventServer) ListEvents(ctx context.Context, req *snapsharev1.ListEventsRequest) (*snapsharev1.ListEventsResponse, error) {
+	if req.OwnerEmail == "" {
+		return nil, status.Error(codes.InvalidArgument, "owner_email is required")
+	}
+	switch models.EventStatus(req.Status) {
+	case "", models.EventStatusActive, models.EventStatusInactive, models.EventStatusClosed:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid status")
+	}
+
+	owner, err := s.eventService.OwnerByEmail(ctx, req.OwnerEmail)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	page, err := s.eventService.GetEventsByOwner(ctx, owner, services.EventFilter{
+		Status: models.EventStatus(req.Status),
+		Limit:  pagination.ClampLimit(int(req.PageSize), defaultEventPageSize, maxEventPageSize),
+		Cursor: req.PageToken,
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := &snapsharev1.ListEventsResponse{
+		Events:        make([]*snapsharev1.Event, len(page.Events)),
+		Total:         page.Total,
+		NextPageToken: page.NextCursor,
+	}
+	for i := range page.Events {
+		response.Events[i] = toEvent(&page.Events[i])
+	}
+
+	return response, nil
+}
+
+// CloseEvent closes an event and returns it
+func (s *EventServer) CloseEvent(ctx context.Context, req *snapsharev1.CloseEventRequest) (*snapsharev1.Event, error) {
+	eventID, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.eventService.CloseEvent(ctx, eventID); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	event, err := s.eventService.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return toEvent(event), nil
+}
+
+func toEvent(event *models.Event) *snapsharev1.Event {
+	return &snapsharev1.Event{
+		Id:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           timestamp(event.EventDate),
+		Timezone:            event.Timezone,
+		Status:              string(event.Status),
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           int32Value(event.MaxGuests),
+		MaxPhotos:           int32Value(event.MaxPhotos),
+		CloseAt:             timestamp(event.CloseAt),
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   string(event.GalleryVisibility),
+		CreatedAt:           timestamppb.New(event.CreatedAt),
+		UpdatedAt:           timestamppb.New(event.UpdatedAt),
+	}
+}
+
+// timestamp converts an optional time, leaving unset times unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func int32Value(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	value := int32(*v)
+	return &value
+}
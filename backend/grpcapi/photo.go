@@ -0,0 +1,197 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"snapShare/models"
+	"snapShare/pagination"
+	snapsharev1 "snapShare/proto/snapshare/v1"
+	"snapShare/services"
+)
+
+// Page sizes of ListPhotos, the same as the REST gallery
+const (
+	defaultPhotoPageSize = 100
+	maxPhotoPageSize     = 500
+)
+
+// syncBatchSize is how many photos SyncPhotos loads at once while catching up
+const syncBatchSize = 500
+
+type PhotoServer struct {
+	snapsharev1.UnimplementedPhotoServiceServer
+	photoService *services.PhotoService
+	feed         *services.GalleryFeed
+}
+
+func NewPhotoServer(photoService *services.PhotoService, feed *services.GalleryFeed) *PhotoServer {
+	return &PhotoServer{
+		photoService: photoService,
+		feed:         feed,
+	}
+}
+
+// GetPhoto returns a single photo
+func (s *PhotoServer) GetPhoto(ctx context.Context, req *snapsharev1.GetPhotoRequest) (*snapsharev1.Photo, error) {
+	photoID, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.photoService.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return toPhoto(photo, s.photoService.PublicURL(photo.ObjectKey)), nil
+}
+
+// ListPhotos returns a page of an event's visible photos
+func (s *PhotoServer) ListPhotos(ctx context.Context, req *snapsharev1.ListPhotosRequest) (*snapsharev1.ListPhotosResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Sort {
+	case "", "created_at", "taken_at", "size", "uploader":
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid sort")
+	}
+	switch strings.ToLower(req.Order) {
+	case "", "asc", "desc":
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid order")
+	}
+
+	page, err := s.photoService.GetPhotosByEvent(ctx, eventID, services.PhotoFilter{
+		Query:  req.Query,
+		Sort:   req.Sort,
+		Order:  req.Order,
+		Limit:  pagination.ClampLimit(int(req.PageSize), defaultPhotoPageSize, maxPhotoPageSize),
+		Cursor: req.PageToken,
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := &snapsharev1.ListPhotosResponse{
+		Photos:        make([]*snapsharev1.Photo, len(page.Photos)),
+		NextPageToken: page.NextCursor,
+	}
+	for i := range page.Photos {
+		response.Photos[i] = toPhoto(&page.Photos[i], s.photoService.PublicURL(page.Photos[i].ObjectKey))
+	}
+
+	return response, nil
+}
+
+// SyncPhotos sends the photos confirmed since the requested time, then live
+// gallery updates until the client goes away
+func (s *PhotoServer) SyncPhotos(req *snapsharev1.SyncPhotosRequest, stream grpc.ServerStreamingServer[snapsharev1.GalleryUpdate]) error {
+	ctx := stream.Context()
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return err
+	}
+	if _, err := s.photoService.GetGalleryVisibility(ctx, eventID); err != nil {
+		return statusError(ctx, err)
+	}
+
+	// Subscribe before catching up so that no upload falls in between
+	updates, unsubscribe := s.feed.Subscribe(eventID)
+	defer unsubscribe()
+
+	var since *time.Time
+	if req.Since != nil {
+		t := req.Since.AsTime()
+		since = &t
+	}
+	var cursor string
+	for {
+		page, err := s.photoService.GetSlideshowPhotos(ctx, eventID, since, cursor, syncBatchSize)
+		if err != nil {
+			return statusError(ctx, err)
+		}
+		for i := range page.Photos {
+			update := services.GalleryUpdate{Type: services.GalleryUpdatePhotoUploaded, Photo: page.Photos[i]}
+			if err := stream.Send(s.toGalleryUpdate(update)); err != nil {
+				return err
+			}
+		}
+		if len(page.Photos) < syncBatchSize {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update := <-updates:
+			if err := stream.Send(s.toGalleryUpdate(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// DeletePhoto deletes a photo
+func (s *PhotoServer) DeletePhoto(ctx context.Context, req *snapsharev1.DeletePhotoRequest) (*snapsharev1.DeletePhotoResponse, error) {
+	photoID, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.photoService.DeletePhoto(ctx, photoID, true); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &snapsharev1.DeletePhotoResponse{}, nil
+}
+
+func (s *PhotoServer) toGalleryUpdate(update services.GalleryUpdate) *snapsharev1.GalleryUpdate {
+	if update.Type != services.GalleryUpdatePhotoUploaded {
+		return &snapsharev1.GalleryUpdate{
+			Type: snapsharev1.GalleryUpdate_PHOTO_DELETED,
+			Photo: &snapsharev1.Photo{
+				Id:      update.Photo.ID.String(),
+				EventId: update.Photo.EventID.String(),
+			},
+		}
+	}
+
+	return &snapsharev1.GalleryUpdate{
+		Type:  snapsharev1.GalleryUpdate_PHOTO_UPLOADED,
+		Photo: toPhoto(&update.Photo, s.photoService.PublicURL(update.Photo.ObjectKey)),
+	}
+}
+
+func toPhoto(photo *models.Photo, url string) *snapsharev1.Photo {
+	response := &snapsharev1.Photo{
+		Id:           photo.ID.String(),
+		EventId:      photo.EventID.String(),
+		UploaderName: photo.UploaderName,
+		Url:          url,
+		FileSize:     photo.Size,
+		MimeType:     photo.MimeType,
+		Width:        int32Value(photo.Width),
+		Height:       int32Value(photo.Height),
+		Caption:      photo.Caption,
+		TakenAt:      timestamp(photo.TakenAt),
+		ConfirmedAt:  timestamp(photo.ConfirmedAt),
+		CreatedAt:    timestamppb.New(photo.CreatedAt),
+	}
+	if photo.GuestID != nil {
+		guestID := photo.GuestID.String()
+		response.GuestId = &guestID
+	}
+	return response
+}
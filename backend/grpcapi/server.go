@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"snapShare/infra/storage"
+	snapsharev1 "snapShare/proto/snapshare/v1"
+	"snapShare/services"
+)
+
+// apiKeyMetadata is the metadata key carrying the caller's API key
+const apiKeyMetadata = "x-api-key"
+
+// NewServer returns a gRPC server exposing events, sessions and photos to
+// internal callers. Every call needs one of apiKeys in the x-api-key
+// metadata, the same keys that guard the admin REST API.
+func NewServer(apiKeys []string, eventService *services.EventService, sessionService *services.SessionService,
+	photoService *services.PhotoService, feed *services.GalleryFeed) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuth(apiKeys)),
+		grpc.ChainStreamInterceptor(streamAuth(apiKeys)),
+	)
+
+	snapsharev1.RegisterEventServiceServer(server, NewEventServer(eventService))
+	snapsharev1.RegisterSessionServiceServer(server, NewSessionServer(sessionService))
+	snapsharev1.RegisterPhotoServiceServer(server, NewPhotoServer(photoService, feed))
+
+	return server
+}
+
+func unaryAuth(apiKeys []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorize(ctx, apiKeys); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(apiKeys []string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(stream.Context(), apiKeys); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authorize requires one of apiKeys in the call's metadata
+func authorize(ctx context.Context, apiKeys []string) error {
+	if len(apiKeys) == 0 {
+		return status.Error(codes.PermissionDenied, "gRPC API is disabled")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	provided := md.Get(apiKeyMetadata)
+	if len(provided) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	matched := 0
+	for _, key := range apiKeys {
+		matched |= subtle.ConstantTimeCompare([]byte(provided[0]), []byte(key))
+	}
+	if matched != 1 {
+		return status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	return nil
+}
+
+// errorCodes maps service errors to status codes, mirroring the REST API
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{services.ErrEventNotFound, codes.NotFound},
+	{services.ErrPhotoNotFound, codes.NotFound},
+	{services.ErrSessionNotFound, codes.NotFound},
+	{gorm.ErrRecordNotFound, codes.NotFound},
+	{services.ErrSessionExpired, codes.Unauthenticated},
+	{services.ErrEventNotActive, codes.Unauthenticated},
+	{services.ErrInvalidCursor, codes.InvalidArgument},
+	{storage.ErrUnavailable, codes.Unavailable},
+}
+
+// statusError converts a service error to a status error. Unknown errors are
+// logged and reported as INTERNAL without their details.
+func statusError(ctx context.Context, err error) error {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return status.Error(known.code, known.err.Error())
+		}
+	}
+
+	slog.ErrorContext(ctx, "gRPC call failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// parseID parses a UUID request field
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s", field)
+	}
+	return id, nil
+}
@@ -0,0 +1,113 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"snapShare/models"
+	"snapShare/pagination"
+	snapsharev1 "snapShare/proto/snapshare/v1"
+	"snapShare/services"
+)
+
+// Page sizes of ListSessions, the same as the REST listing
+const (
+	defaultSessionPageSize = 50
+	maxSessionPageSize     = 200
+)
+
+type SessionServer struct {
+	snapsharev1.UnimplementedSessionServiceServer
+	sessionService *services.SessionService
+}
+
+func NewSessionServer(sessionService *services.SessionService) *SessionServer {
+	return &SessionServer{
+		sessionService: sessionService,
+	}
+}
+
+// ValidateSession resolves a guest session token
+func (s *SessionServer) ValidateSession(ctx context.Context, req *snapsharev1.ValidateSessionRequest) (*snapsharev1.Session, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	session, err := s.sessionService.ValidateSession(ctx, req.Token)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return toSession(session), nil
+}
+
+// ListSessions returns a page of an event's sessions
+func (s *SessionServer) ListSessions(ctx context.Context, req *snapsharev1.ListSessionsRequest) (*snapsharev1.ListSessionsResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Status {
+	case "", services.SessionStatusActive, services.SessionStatusExpired, services.SessionStatusAll:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid status")
+	}
+
+	page, err := s.sessionService.GetSessionsByEvent(ctx, eventID, services.SessionFilter{
+		Status: req.Status,
+		Limit:  pagination.ClampLimit(int(req.PageSize), defaultSessionPageSize, maxSessionPageSize),
+		Cursor: req.PageToken,
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	response := &snapsharev1.ListSessionsResponse{
+		Sessions:      make([]*snapsharev1.Session, len(page.Sessions)),
+		Total:         page.Total,
+		NextPageToken: page.NextCursor,
+	}
+	for i := range page.Sessions {
+		response.Sessions[i] = toSession(&page.Sessions[i])
+	}
+
+	return response, nil
+}
+
+// RevokeSession revokes a single session of an event
+func (s *SessionServer) RevokeSession(ctx context.Context, req *snapsharev1.RevokeSessionRequest) (*snapsharev1.RevokeSessionResponse, error) {
+	eventID, err := parseID("event_id", req.EventId)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := parseID("session_id", req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionService.RevokeEventSession(ctx, eventID, sessionID); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &snapsharev1.RevokeSessionResponse{}, nil
+}
+
+func toSession(session *models.Session) *snapsharev1.Session {
+	response := &snapsharev1.Session{
+		Id:        session.ID.String(),
+		EventId:   session.EventID.String(),
+		GuestName: session.GuestName,
+		Scopes:    strings.Split(session.Scopes, ","),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		CreatedAt: timestamppb.New(session.CreatedAt),
+	}
+	if session.GuestID != nil {
+		guestID := session.GuestID.String()
+		response.GuestId = &guestID
+	}
+	return response
+}
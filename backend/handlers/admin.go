@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type ReassignUploaderRequest struct {
+	UploaderName string `json:"uploader_name" validate:"required,min=1,max=100"`
+	Reason       string `json:"reason" validate:"required,max=500"`
+}
+
+type MovePhotoRequest struct {
+	EventID string `json:"event_id" validate:"required,uuid"`
+	Reason  string `json:"reason" validate:"required,max=500"`
+}
+
+type AdminActionRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// AdminAuthMiddleware requires one of the configured admin API keys in the X-API-Key header
+func AdminAuthMiddleware(apiKeys []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(apiKeys) == 0 {
+				return echo.NewHTTPError(http.StatusForbidden, "admin API is disabled")
+			}
+
+			provided := c.Request().Header.Get("X-API-Key")
+			if provided == "" || !matchAPIKey(provided, apiKeys) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid API key")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ReassignUploader fixes the uploader name of a photo
+func (h *AdminHandler) ReassignUploader(c echo.Context) error {
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
+	}
+
+	var req ReassignUploaderRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	photo, err := h.adminService.ReassignUploader(c.Request().Context(), adminActor(c), photoID, req.UploaderName, req.Reason)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "uploader reassigned", "photo_id": photo.ID.String()})
+}
+
+// MovePhoto fixes a photo recorded against the wrong event
+func (h *AdminHandler) MovePhoto(c echo.Context) error {
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
+	}
+
+	var req MovePhotoRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	eventID, err := uuid.Parse(req.EventID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	photo, err := h.adminService.MovePhotoToEvent(c.Request().Context(), adminActor(c), photoID, eventID, req.Reason)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "photo moved", "photo_id": photo.ID.String()})
+}
+
+// ReprocessPhoto re-extracts derived metadata for a photo
+func (h *AdminHandler) ReprocessPhoto(c echo.Context) error {
+	photoID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
+	}
+
+	var req AdminActionRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	photo, err := h.adminService.ReprocessPhoto(c.Request().Context(), adminActor(c), photoID, req.Reason)
+	if errors.Is(err, services.ErrMediaBusy) {
+		return fail(http.StatusServiceUnavailable, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "photo reprocessed", "photo_id": photo.ID.String()})
+}
+
+// ExpireBatch force-expires the unconfirmed photos of a stuck upload batch
+func (h *AdminHandler) ExpireBatch(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid batch ID")
+	}
+
+	var req AdminActionRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	expired, err := h.adminService.ExpireBatch(c.Request().Context(), adminActor(c), batchID, req.Reason)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "batch expired", "count": expired})
+}
+
+// matchAPIKey compares provided against every key so the timing does not
+// reveal which key, if any, matched
+func matchAPIKey(provided string, apiKeys []string) bool {
+	matched := 0
+	for _, key := range apiKeys {
+		matched |= subtle.ConstantTimeCompare([]byte(provided), []byte(key))
+	}
+	return matched == 1
+}
+
+// adminActor identifies the operator for the audit log
+func adminActor(c echo.Context) services.AuditActor {
+	name := c.Request().Header.Get("X-Admin-Actor")
+	if name == "" {
+		name = "admin"
+	}
+	return services.AuditActor{Type: models.AuditActorAdmin, Name: name, IPAddress: c.RealIP()}
+}
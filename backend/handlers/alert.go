@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type CreateAlertRequest struct {
+	Metric    models.AlertMetric `json:"metric" validate:"required,oneof=photo_count storage_bytes photo_quota_percent guest_quota_percent"`
+	Threshold int64              `json:"threshold" validate:"required,min=1"`
+}
+
+// Response DTOs
+type AlertResponse struct {
+	ID          string             `json:"id"`
+	EventID     string             `json:"event_id"`
+	Metric      models.AlertMetric `json:"metric"`
+	Threshold   int64              `json:"threshold"`
+	TriggeredAt *time.Time         `json:"triggered_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type AlertHandler struct {
+	alertService *services.AlertService
+}
+
+func NewAlertHandler(alertService *services.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// CreateAlert adds a usage threshold alert to an event
+func (h *AlertHandler) CreateAlert(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req CreateAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	alert, err := h.alertService.CreateAlert(c.Request().Context(), eventID, req.Metric, req.Threshold)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, toAlertResponse(alert))
+}
+
+// GetAlertsByEvent lists the alerts configured for an event
+func (h *AlertHandler) GetAlertsByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	alerts, err := h.alertService.GetAlertsByEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]AlertResponse, len(alerts))
+	for i := range alerts {
+		responses[i] = toAlertResponse(&alerts[i])
+	}
+
+	return listResponse(c, responses, "")
+}
+
+// DeleteAlert removes an alert from an event
+func (h *AlertHandler) DeleteAlert(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	alertIDStr := c.Param("alert_id")
+	alertID, err := uuid.Parse(alertIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid alert ID")
+	}
+
+	if err := h.alertService.DeleteAlert(c.Request().Context(), eventID, alertID); err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "alert deleted"})
+}
+
+func toAlertResponse(alert *models.ThresholdAlert) AlertResponse {
+	return AlertResponse{
+		ID:          alert.ID.String(),
+		EventID:     alert.EventID.String(),
+		Metric:      alert.Metric,
+		Threshold:   alert.Threshold,
+		TriggeredAt: alert.TriggeredAt,
+		CreatedAt:   alert.CreatedAt,
+	}
+}
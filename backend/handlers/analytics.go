@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+// Response DTOs
+type OwnerOverviewResponse struct {
+	EventCount     int64                  `json:"event_count"`
+	ActiveEvents   int64                  `json:"active_events"`
+	PhotoCount     int64                  `json:"photo_count"`
+	StorageBytes   int64                  `json:"storage_bytes"`
+	ActiveGuests   int64                  `json:"active_guests"`
+	UploadsPerDay  []DailyUploadsResponse `json:"uploads_per_day"`
+	RecentActivity []ActivityResponse     `json:"recent_activity"`
+}
+
+type ActivityResponse struct {
+	Type      string    `json:"type"`
+	EventID   string    `json:"event_id"`
+	EventName string    `json:"event_name"`
+	GuestName string    `json:"guest_name"`
+	At        time.Time `json:"at"`
+}
+
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetOwnerOverview summarises uploads, storage and guests across the
+// signed-in owner's events for the dashboard
+func (h *AnalyticsHandler) GetOwnerOverview(c echo.Context) error {
+	owner, ok := currentOwner(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
+	}
+
+	overview, err := h.analyticsService.GetOwnerOverview(c.Request().Context(), owner)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	daily := make([]DailyUploadsResponse, len(overview.UploadsPerDay))
+	for i, bucket := range overview.UploadsPerDay {
+		daily[i] = DailyUploadsResponse{
+			Date:  bucket.Day.Format(time.DateOnly),
+			Count: bucket.Count,
+		}
+	}
+
+	activity := make([]ActivityResponse, len(overview.RecentActivity))
+	for i, item := range overview.RecentActivity {
+		activity[i] = ActivityResponse{
+			Type:      item.Type,
+			EventID:   item.EventID.String(),
+			EventName: item.EventName,
+			GuestName: item.GuestName,
+			At:        item.At,
+		}
+	}
+
+	return c.JSON(http.StatusOK, OwnerOverviewResponse{
+		EventCount:     overview.EventCount,
+		ActiveEvents:   overview.ActiveEvents,
+		PhotoCount:     overview.PhotoCount,
+		StorageBytes:   overview.StorageBytes,
+		ActiveGuests:   overview.ActiveGuests,
+		UploadsPerDay:  daily,
+		RecentActivity: activity,
+	})
+}
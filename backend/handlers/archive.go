@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type ExportRequest struct {
+	NotifyEmail string `json:"notify_email,omitempty" validate:"omitempty,email"`
+}
+
+// Response DTOs
+type ArchiveJobResponse struct {
+	JobID        string                  `json:"job_id"`
+	EventID      string                  `json:"event_id"`
+	UploaderName *string                 `json:"uploader_name,omitempty"`
+	Status       models.ArchiveJobStatus `json:"status"`
+	PhotoCount   int                     `json:"photo_count"`
+	Error        *string                 `json:"error,omitempty"`
+	DownloadURL  string                  `json:"download_url,omitempty"`
+	ExpiresAt    *time.Time              `json:"expires_at,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	CompletedAt  *time.Time              `json:"completed_at,omitempty"`
+}
+
+type ArchiveEstimateResponse struct {
+	EventID      string  `json:"event_id"`
+	UploaderName *string `json:"uploader_name,omitempty"`
+	PhotoCount   int64   `json:"photo_count"`
+	TotalBytes   int64   `json:"total_bytes"`
+	ArchiveBytes int64   `json:"estimated_archive_bytes"`
+}
+
+type ArchiveHandler struct {
+	archiveService *services.ArchiveService
+}
+
+func NewArchiveHandler(archiveService *services.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// StartArchive starts building a zip of all photos in an event.
+// ?uploader= restricts the archive to a single guest.
+func (h *ArchiveHandler) StartArchive(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var uploaderName *string
+	if uploader := c.QueryParam("uploader"); uploader != "" {
+		uploaderName = &uploader
+	}
+
+	return h.startArchive(c, eventID, uploaderName)
+}
+
+// EstimateArchive returns the photo count and byte size of an event archive.
+// ?uploader= restricts the estimate to a single guest.
+func (h *ArchiveHandler) EstimateArchive(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var uploaderName *string
+	if uploader := c.QueryParam("uploader"); uploader != "" {
+		uploaderName = &uploader
+	}
+
+	estimate, err := h.archiveService.EstimateArchive(c.Request().Context(), eventID, uploaderName)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	response := ArchiveEstimateResponse{
+		EventID:      eventID.String(),
+		UploaderName: uploaderName,
+		PhotoCount:   estimate.PhotoCount,
+		TotalBytes:   estimate.TotalBytes,
+		ArchiveBytes: estimate.ArchiveBytes,
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// StartMyArchive starts building a zip of the photos uploaded by the current guest
+func (h *ArchiveHandler) StartMyArchive(c echo.Context) error {
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+	}
+
+	if err := h.checkGuestDownloads(c, session.EventID); err != nil {
+		return err
+	}
+
+	return h.startArchive(c, session.EventID, &session.GuestName)
+}
+
+// GetMyArchiveStatus reports the progress of an archive started by the current guest
+func (h *ArchiveHandler) GetMyArchiveStatus(c echo.Context) error {
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+	}
+
+	jobIDStr := c.Param("job_id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid job ID")
+	}
+
+	if err := h.checkGuestDownloads(c, session.EventID); err != nil {
+		return err
+	}
+
+	status, err := h.archiveService.GetArchiveStatus(c.Request().Context(), session.EventID, jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrArchiveExpired) {
+			return fail(http.StatusGone, err)
+		}
+		return fail(http.StatusNotFound, err)
+	}
+
+	// Guests may only poll archives of their own photos
+	if status.Job.UploaderName == nil || *status.Job.UploaderName != session.GuestName {
+		return echo.NewHTTPError(http.StatusNotFound, "archive job not found")
+	}
+
+	return c.JSON(http.StatusOK, toArchiveJobResponse(status))
+}
+
+// StartExport starts building the full event export: every photo plus JSON
+// and CSV manifests of photo metadata and guests. notify_email, if given,
+// receives a download link when the export is ready.
+func (h *ArchiveHandler) StartExport(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req ExportRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	job, err := h.archiveService.StartExport(c.Request().Context(), eventID, req.NotifyEmail)
+	if errors.Is(err, services.ErrNoPhotos) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return h.archiveJobResponse(c, job)
+}
+
+func (h *ArchiveHandler) startArchive(c echo.Context, eventID uuid.UUID, uploaderName *string) error {
+	job, err := h.archiveService.StartArchive(c.Request().Context(), eventID, uploaderName)
+	if errors.Is(err, services.ErrNoPhotos) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return h.archiveJobResponse(c, job)
+}
+
+// checkGuestDownloads rejects guest archive requests when the owner has
+// turned guest downloads off
+func (h *ArchiveHandler) checkGuestDownloads(c echo.Context, eventID uuid.UUID) error {
+	allowed, err := h.archiveService.GuestDownloadsAllowed(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		return fail(http.StatusForbidden, services.ErrGuestDownloadsDisabled)
+	}
+
+	return nil
+}
+
+// archiveJobResponse answers 202 for a queued job, or 200 with a download URL
+// when the job is already complete
+func (h *ArchiveHandler) archiveJobResponse(c echo.Context, job *models.ArchiveJob) error {
+	// A cached archive can be downloaded right away
+	if job.Status == models.ArchiveJobStatusCompleted {
+		status, err := h.archiveService.GetArchiveStatus(c.Request().Context(), job.EventID, job.ID)
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+		return c.JSON(http.StatusOK, toArchiveJobResponse(status))
+	}
+
+	response := ArchiveJobResponse{
+		JobID:        job.ID.String(),
+		EventID:      job.EventID.String(),
+		UploaderName: job.UploaderName,
+		Status:       job.Status,
+		CreatedAt:    job.CreatedAt,
+	}
+
+	return c.JSON(http.StatusAccepted, response)
+}
+
+// GetArchiveStatus reports the progress of an archive job
+func (h *ArchiveHandler) GetArchiveStatus(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	jobIDStr := c.Param("job_id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid job ID")
+	}
+
+	status, err := h.archiveService.GetArchiveStatus(c.Request().Context(), eventID, jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrArchiveExpired) {
+			return fail(http.StatusGone, err)
+		}
+		return fail(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, toArchiveJobResponse(status))
+}
+
+func toArchiveJobResponse(status *services.ArchiveStatus) ArchiveJobResponse {
+	return ArchiveJobResponse{
+		JobID:        status.Job.ID.String(),
+		EventID:      status.Job.EventID.String(),
+		UploaderName: status.Job.UploaderName,
+		Status:       status.Job.Status,
+		PhotoCount:   status.Job.PhotoCount,
+		Error:        status.Job.Error,
+		DownloadURL:  status.DownloadURL,
+		ExpiresAt:    status.ExpiresAt,
+		CreatedAt:    status.Job.CreatedAt,
+		CompletedAt:  status.Job.CompletedAt,
+	}
+}
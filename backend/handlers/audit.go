@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+type AuditLogQuery struct {
+	Action string     `query:"action" validate:"omitempty,max=100"`
+	Before *time.Time `query:"before"`
+	PageQuery
+}
+
+type AuditLogResponse struct {
+	ID         string    `json:"id"`
+	ActorType  string    `json:"actor_type"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	Details    *string   `json:"details,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 200
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogByEvent lists who did what to an event, newest first.
+// Supports ?action= (exact, or a prefix ending in "."), ?before=, ?limit= and
+// ?cursor= (next_cursor of the previous page)
+func (h *AuditHandler) GetAuditLogByEvent(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var query AuditLogQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	page, err := h.auditService.GetAuditLogByEvent(c.Request().Context(), eventID, services.AuditFilter{
+		Action: query.Action,
+		Limit:  query.PageLimit(defaultAuditLogLimit, maxAuditLogLimit),
+		Cursor: query.Cursor,
+		Before: query.Before,
+	})
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]AuditLogResponse, len(page.Entries))
+	for i, entry := range page.Entries {
+		responses[i] = AuditLogResponse{
+			ID:         entry.ID.String(),
+			ActorType:  entry.ActorType,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			Details:    entry.Details,
+			IPAddress:  entry.IPAddress,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	return listResponse(c, responses, page.NextCursor)
+}
+
+// requestActor identifies the caller for the audit log: the signed-in owner,
+// the guest session, or an admin
+func requestActor(c echo.Context) services.AuditActor {
+	if email, ok := c.Get("owner_email").(string); ok {
+		return services.AuditActor{Type: models.AuditActorOwner, Name: email, IPAddress: c.RealIP()}
+	}
+	if session, ok := c.Get("session").(*models.Session); ok {
+		return guestActor(c, session)
+	}
+	return adminActor(c)
+}
+
+// guestActor identifies a guest session that is not yet, or no longer, in the request context
+func guestActor(c echo.Context, session *models.Session) services.AuditActor {
+	return services.AuditActor{Type: models.AuditActorGuest, Name: session.GuestName, IPAddress: c.RealIP()}
+}
+
+// recordAudit logs an action that already succeeded, attributed to the caller
+// unless entry.Actor is set. Failures are only logged so that the audit trail
+// never turns a completed action into an error.
+func recordAudit(c echo.Context, auditService *services.AuditService, entry services.AuditEntry) {
+	if entry.Actor.Name == "" {
+		entry.Actor = requestActor(c)
+	}
+
+	if err := auditService.Record(c.Request().Context(), nil, entry); err != nil {
+		slog.ErrorContext(c.Request().Context(), "failed to record audit entry", "action", entry.Action, "target_type", entry.TargetType, "target_id", entry.TargetID, "error", err)
+	}
+}
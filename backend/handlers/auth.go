@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/infra/auth"
+	"snapShare/models"
+	"snapShare/services"
+	"snapShare/utils"
+)
+
+// Request DTOs
+type RegisterRequest struct {
+	Email    string  `json:"email" validate:"required,email,max=255"`
+	Password string  `json:"password" validate:"required,min=8,max=72"`
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Response DTOs
+type UserResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      *string   `json:"name,omitempty"`
+	Locale    string    `json:"locale"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuthResponse struct {
+	User         UserResponse `json:"user"`
+	AccessToken  string       `json:"access_token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	RefreshToken string       `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+type AuthHandler struct {
+	authService    *services.AuthService
+	oauthProviders auth.Providers
+}
+
+func NewAuthHandler(authService *services.AuthService, oauthProviders auth.Providers) *AuthHandler {
+	return &AuthHandler{
+		authService:    authService,
+		oauthProviders: oauthProviders,
+	}
+}
+
+// Register emails a link confirming a new owner account; the account is
+// created and signed in when the link is verified
+func (h *AuthHandler) Register(c echo.Context) error {
+	var req RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	err := h.authService.Register(c.Request().Context(), req.Email, req.Password, req.Name)
+	if errors.Is(err, services.ErrEmailTaken) {
+		return fail(http.StatusConflict, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "confirmation link sent"})
+}
+
+// Login verifies an owner's email and password and issues tokens
+func (h *AuthHandler) Login(c echo.Context) error {
+	var req LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	user, err := h.authService.Login(c.Request().Context(), req.Email, req.Password)
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		return fail(http.StatusUnauthorized, err)
+	}
+	if errors.Is(err, services.ErrEmailUnverified) {
+		return fail(http.StatusForbidden, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return h.authResponse(c, http.StatusOK, user)
+}
+
+// SendMagicLink emails a single-use login link. The response is the same
+// whether or not the email has an account.
+func (h *AuthHandler) SendMagicLink(c echo.Context) error {
+	var req MagicLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := h.authService.SendMagicLink(c.Request().Context(), req.Email); err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "login link sent"})
+}
+
+// VerifyMagicLink exchanges a login link token for owner tokens
+func (h *AuthHandler) VerifyMagicLink(c echo.Context) error {
+	var req VerifyMagicLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	user, err := h.authService.VerifyMagicLink(c.Request().Context(), req.Token)
+	if errors.Is(err, services.ErrInvalidMagicLink) {
+		return fail(http.StatusUnauthorized, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return h.authResponse(c, http.StatusOK, user)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var req RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	tokens, err := h.authService.Refresh(c.Request().Context(), req.RefreshToken)
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		return fail(http.StatusUnauthorized, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		ExpiresAt:    tokens.AccessExpiresAt,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+// Logout revokes the refresh token and every token rotated from the same login
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := h.authService.Logout(c.Request().Context(), req.RefreshToken); err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "logged out"})
+}
+
+// GetMe returns the authenticated owner's account
+func (h *AuthHandler) GetMe(c echo.Context) error {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
+	}
+
+	user, err := h.authService.GetUserByID(c.Request().Context(), userID)
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+// OwnerAuthMiddleware validates the owner access token from the Authorization header
+func (h *AuthHandler) OwnerAuthMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "authorization header required")
+			}
+
+			token, ok := strings.CutPrefix(authHeader, "Bearer ")
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid authorization format")
+			}
+
+			claims, err := utils.ValidateOwnerJWT(token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired access token")
+			}
+
+			userID, err := uuid.Parse(claims.UserID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired access token")
+			}
+
+			// Set owner info in context for use by handlers
+			c.Set("user_id", userID)
+			c.Set("owner_email", claims.Email)
+
+			return next(c)
+		}
+	}
+}
+
+// EventAccessMiddleware requires the authenticated owner to own the event in
+// the :id path parameter, or to collaborate on it in a role that grants
+// capability. It must run after OwnerAuthMiddleware.
+func EventAccessMiddleware(collaboratorService *services.CollaboratorService, capability services.EventCapability) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			eventID, err := uuid.Parse(c.Param("id"))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+			}
+
+			owner, ok := currentOwner(c)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
+			}
+
+			allowed, err := collaboratorService.CanAccessEvent(c.Request().Context(), eventID, owner, capability)
+			if err != nil {
+				return fail(http.StatusInternalServerError, err)
+			}
+			if !allowed {
+				return echo.NewHTTPError(http.StatusForbidden, "you do not have access to this event")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// EventOwnerMiddleware limits a route to the event's owner; co-hosts are
+// refused. It must run after OwnerAuthMiddleware.
+func EventOwnerMiddleware(collaboratorService *services.CollaboratorService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			eventID, err := uuid.Parse(c.Param("id"))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+			}
+
+			owner, ok := currentOwner(c)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
+			}
+
+			isOwner, err := collaboratorService.IsEventOwner(c.Request().Context(), eventID, owner)
+			if err != nil {
+				return fail(http.StatusInternalServerError, err)
+			}
+			if !isOwner {
+				return echo.NewHTTPError(http.StatusForbidden, "only the event owner can do this")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// currentOwner returns the owner authenticated by OwnerAuthMiddleware
+func currentOwner(c echo.Context) (services.Owner, bool) {
+	userID, ok := c.Get("user_id").(uuid.UUID)
+	if !ok {
+		return services.Owner{}, false
+	}
+	email, _ := c.Get("owner_email").(string)
+	return services.Owner{UserID: userID, Email: email}, true
+}
+
+func (h *AuthHandler) authResponse(c echo.Context, status int, user *models.User) error {
+	tokens, err := h.authService.IssueTokens(c.Request().Context(), user)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(status, AuthResponse{
+		User:         toUserResponse(user),
+		AccessToken:  tokens.AccessToken,
+		ExpiresAt:    tokens.AccessExpiresAt,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+func toUserResponse(user *models.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID.String(),
+		Email:     user.Email,
+		Name:      user.Name,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+// Request DTOs
+type BanGuestRequest struct {
+	SessionID *string `json:"session_id,omitempty" validate:"omitempty,uuid"`
+	GuestName string  `json:"guest_name,omitempty" validate:"required_without=SessionID,max=100"`
+	Uploads   string  `json:"uploads,omitempty" validate:"omitempty,oneof=keep hide delete"`
+	Reason    *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// Response DTOs
+type BanGuestResponse struct {
+	GuestName       string `json:"guest_name"`
+	RevokedSessions int64  `json:"revoked_sessions"`
+	AffectedPhotos  int64  `json:"affected_photos"`
+}
+
+type GuestBanResponse struct {
+	GuestName string    `json:"guest_name"`
+	Reason    *string   `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BanHandler struct {
+	banService   *services.BanService
+	auditService *services.AuditService
+}
+
+func NewBanHandler(banService *services.BanService, auditService *services.AuditService) *BanHandler {
+	return &BanHandler{
+		banService:   banService,
+		auditService: auditService,
+	}
+}
+
+// BanGuest bans a guest from an event, revokes their sessions and optionally
+// hides (uploads=hide) or deletes (uploads=delete) their photos
+func (h *BanHandler) BanGuest(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req BanGuestRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	serviceReq := &services.BanRequest{
+		GuestName: req.GuestName,
+		Uploads:   req.Uploads,
+		Reason:    req.Reason,
+	}
+	if req.SessionID != nil {
+		sessionID := uuid.MustParse(*req.SessionID)
+		serviceReq.SessionID = &sessionID
+	}
+
+	result, err := h.banService.BanGuest(c.Request().Context(), eventID, serviceReq)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "moderation.ban",
+		TargetType: "guest",
+		TargetID:   result.GuestName,
+		Details: map[string]any{
+			"uploads":          req.Uploads,
+			"reason":           req.Reason,
+			"revoked_sessions": result.RevokedSessions,
+			"affected_photos":  result.AffectedPhotos,
+		},
+	})
+
+	return c.JSON(http.StatusOK, BanGuestResponse{
+		GuestName:       result.GuestName,
+		RevokedSessions: result.RevokedSessions,
+		AffectedPhotos:  result.AffectedPhotos,
+	})
+}
+
+// GetBansByEvent lists the guests banned from an event
+func (h *BanHandler) GetBansByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	bans, err := h.banService.GetBansByEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]GuestBanResponse, len(bans))
+	for i, ban := range bans {
+		responses[i] = GuestBanResponse{
+			GuestName: ban.GuestName,
+			Reason:    ban.Reason,
+			CreatedAt: ban.CreatedAt,
+		}
+	}
+
+	return listResponse(c, responses, "")
+}
+
+// UnbanGuest lifts the ban on a guest name
+func (h *BanHandler) UnbanGuest(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	guestName := c.Param("guest_name")
+
+	if err := h.banService.UnbanGuest(c.Request().Context(), eventID, guestName); err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "moderation.unban",
+		TargetType: "guest",
+		TargetID:   guestName,
+	})
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "ban lifted"})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type InviteCollaboratorRequest struct {
+	Email string                  `json:"email" validate:"required,email,max=255"`
+	Role  models.CollaboratorRole `json:"role" validate:"omitempty,oneof=co_host moderator"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Response DTOs
+type CollaboratorResponse struct {
+	ID         string                  `json:"id"`
+	EventID    string                  `json:"event_id"`
+	Email      string                  `json:"email"`
+	Role       models.CollaboratorRole `json:"role"`
+	Pending    bool                    `json:"pending"`
+	AcceptedAt *time.Time              `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+type CollaboratorHandler struct {
+	collaboratorService *services.CollaboratorService
+}
+
+func NewCollaboratorHandler(collaboratorService *services.CollaboratorService) *CollaboratorHandler {
+	return &CollaboratorHandler{
+		collaboratorService: collaboratorService,
+	}
+}
+
+// InviteCollaborator invites a co-host to an event by email
+func (h *CollaboratorHandler) InviteCollaborator(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req InviteCollaboratorRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if req.Role == "" {
+		req.Role = models.CollaboratorRoleCoHost
+	}
+
+	collaborator, err := h.collaboratorService.InviteCollaborator(c.Request().Context(), eventID, req.Email, req.Role)
+	if errors.Is(err, services.ErrAlreadyCollaborator) {
+		return fail(http.StatusConflict, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, toCollaboratorResponse(collaborator))
+}
+
+// AcceptInvitation accepts a co-host invitation using the emailed token
+func (h *CollaboratorHandler) AcceptInvitation(c echo.Context) error {
+	var req AcceptInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	collaborator, err := h.collaboratorService.AcceptInvitation(c.Request().Context(), req.Token)
+	if errors.Is(err, services.ErrInvitationNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, toCollaboratorResponse(collaborator))
+}
+
+// GetCollaboratorsByEvent lists an event's co-hosts and pending invitations
+func (h *CollaboratorHandler) GetCollaboratorsByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	collaborators, err := h.collaboratorService.GetCollaboratorsByEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]CollaboratorResponse, len(collaborators))
+	for i := range collaborators {
+		responses[i] = toCollaboratorResponse(&collaborators[i])
+	}
+
+	return listResponse(c, responses, "")
+}
+
+// RemoveCollaborator revokes a co-host or a pending invitation
+func (h *CollaboratorHandler) RemoveCollaborator(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	collaboratorIDStr := c.Param("collaborator_id")
+	collaboratorID, err := uuid.Parse(collaboratorIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid collaborator ID")
+	}
+
+	if err := h.collaboratorService.RemoveCollaborator(c.Request().Context(), eventID, collaboratorID); err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "collaborator removed"})
+}
+
+func toCollaboratorResponse(collaborator *models.EventCollaborator) CollaboratorResponse {
+	return CollaboratorResponse{
+		ID:         collaborator.ID.String(),
+		EventID:    collaborator.EventID.String(),
+		Email:      collaborator.Email,
+		Role:       collaborator.Role,
+		Pending:    collaborator.AcceptedAt == nil,
+		AcceptedAt: collaborator.AcceptedAt,
+		CreatedAt:  collaborator.CreatedAt,
+	}
+}
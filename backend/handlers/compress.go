@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+// compressMinLength is the smallest body worth compressing; shorter bodies
+// grow rather than shrink once the encoding overhead is added
+const compressMinLength = 1024
+
+// brotliLevel trades ratio for speed, since responses are compressed per request
+const brotliLevel = 4
+
+// compressibleTypes are the content types compressed by Compress. Images,
+// videos, archives and other binary bodies are already compressed.
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/graphql-response+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/",
+}
+
+// Compress encodes response bodies with brotli or gzip, whichever the client
+// prefers, for compressible content types of at least compressMinLength
+// bytes. Event streams are left alone so that every event is sent at once.
+func Compress() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" || c.Request().Method == http.MethodHead {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := &compressWriter{ResponseWriter: res.Writer, encoding: encoding}
+			res.Writer = writer
+			defer func() {
+				// A body that never reached the minimum length is sent as is
+				if err := writer.Close(); err != nil {
+					c.Logger().Error(err)
+				}
+				res.Writer = writer.ResponseWriter
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// negotiateEncoding picks br or gzip from an Accept-Encoding header,
+// preferring br when both are equally acceptable
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "br" && name != "gzip" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ || (q == bestQ && name == "br") {
+			best, bestQ = name, q
+		}
+	}
+
+	return best
+}
+
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	if mediaType == "text/event-stream" {
+		return false
+	}
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter holds the body back until it is known to be compressible
+// and long enough, then either encodes it or passes it through
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status  int
+	decided bool
+	buffer  bytes.Buffer
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.Header().Get(echo.HeaderContentType) == "" {
+		w.Header().Set(echo.HeaderContentType, http.DetectContentType(b))
+	}
+
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	if !w.compresses() {
+		if err := w.passThrough(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buffer.Write(b)
+	if w.buffer.Len() >= compressMinLength {
+		if err := w.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// compresses reports whether the response may be encoded at all
+func (w *compressWriter) compresses() bool {
+	header := w.Header()
+	return header.Get(echo.HeaderContentEncoding) == "" &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified &&
+		compressible(header.Get(echo.HeaderContentType))
+}
+
+func (w *compressWriter) startEncoding() error {
+	w.decided = true
+
+	header := w.Header()
+	header.Set(echo.HeaderContentEncoding, w.encoding)
+	header.Del(echo.HeaderContentLength)
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.encoding == "br" {
+		w.encoder = brotli.NewWriterLevel(w.ResponseWriter, brotliLevel)
+	} else {
+		w.encoder = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	_, err := w.encoder.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+// passThrough sends the headers and anything buffered without encoding
+func (w *compressWriter) passThrough() error {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+
+	_, err := w.ResponseWriter.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+// Close finishes the encoded body, or sends a short or empty one as is
+func (w *compressWriter) Close() error {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Close()
+		}
+		return nil
+	}
+	if w.status == 0 {
+		// Nothing was written; the handler may have failed before responding
+		return nil
+	}
+	return w.passThrough()
+}
+
+// Flush sends what was written so far; the body can no longer wait for
+// the minimum length once a handler flushes
+func (w *compressWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		var err error
+		if w.compresses() {
+			err = w.startEncoding()
+		} else {
+			err = w.passThrough()
+		}
+		if err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+type ConsentTermsResponse struct {
+	Version   int       `json:"version"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ConsentRecordResponse struct {
+	SessionID      string    `json:"session_id"`
+	GuestName      string    `json:"guest_name"`
+	ConsentVersion int       `json:"consent_version"`
+	AcceptedAt     time.Time `json:"accepted_at"`
+	IPAddress      string    `json:"ip_address"`
+	UserAgent      string    `json:"user_agent"`
+}
+
+type ConsentHandler struct {
+	consentService *services.ConsentService
+}
+
+func NewConsentHandler(consentService *services.ConsentService) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+	}
+}
+
+// ExportConsents returns every consent text version of an event and every
+// guest acceptance. Supports ?format=json (default) or csv, which puts the
+// accepted text on each row.
+func (h *ConsentHandler) ExportConsents(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be json or csv")
+	}
+
+	export, err := h.consentService.GetConsentExport(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	if format == "csv" {
+		return writeConsentCSV(c, eventID, export)
+	}
+
+	terms := make([]ConsentTermsResponse, len(export.Terms))
+	for i, t := range export.Terms {
+		terms[i] = ConsentTermsResponse{Version: t.Version, Text: t.Text, CreatedAt: t.CreatedAt}
+	}
+
+	records := make([]ConsentRecordResponse, len(export.Records))
+	for i, r := range export.Records {
+		records[i] = ConsentRecordResponse{
+			SessionID:      r.SessionID.String(),
+			GuestName:      r.GuestName,
+			ConsentVersion: r.ConsentVersion,
+			AcceptedAt:     r.AcceptedAt,
+			IPAddress:      r.IPAddress,
+			UserAgent:      r.UserAgent,
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"terms": terms, "records": records})
+}
+
+func writeConsentCSV(c echo.Context, eventID uuid.UUID, export *services.ConsentExport) error {
+	texts := make(map[int]string, len(export.Terms))
+	for _, t := range export.Terms {
+		texts[t.Version] = t.Text
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="consents-%s.csv"`, eventID))
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+	w.Write([]string{"session_id", "guest_name", "consent_version", "accepted_at", "ip_address", "user_agent", "consent_text"})
+	for _, r := range export.Records {
+		w.Write([]string{
+			r.SessionID.String(),
+			r.GuestName,
+			strconv.Itoa(r.ConsentVersion),
+			r.AcceptedAt.Format(time.RFC3339),
+			r.IPAddress,
+			r.UserAgent,
+			texts[r.ConsentVersion],
+		})
+	}
+	w.Flush()
+
+	return w.Error()
+}
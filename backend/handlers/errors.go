@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"snapShare/i18n"
+	"snapShare/infra/captcha"
+	"snapShare/infra/storage"
+	"snapShare/infra/webhooksig"
+	"snapShare/services"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of every error response. Code is stable and meant for
+// clients to branch on; Detail is a human readable explanation that may change.
+// RequestID lets users quote the failing request when asking for support.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a request field that failed validation. Field is the
+// JSON path of the input, Rule the failed validation rule and Message an
+// English explanation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error codes that are not tied to a single service error
+const (
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeInternal         = "INTERNAL_ERROR"
+)
+
+// errorCode maps a service error to its code, and to the status used when
+// a handler returns the error as is
+type errorCode struct {
+	err    error
+	status int
+	code   string
+}
+
+var errorCodes = []errorCode{
+	{services.ErrEventNotFound, http.StatusNotFound, "EVENT_NOT_FOUND"},
+	{services.ErrEventNotActive, http.StatusForbidden, "EVENT_NOT_ACTIVE"},
+	{services.ErrEventNotClosed, http.StatusConflict, "EVENT_NOT_CLOSED"},
+	{services.ErrInvalidTimezone, http.StatusBadRequest, "INVALID_TIMEZONE"},
+	{services.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+	{services.ErrSessionExpired, http.StatusUnauthorized, "SESSION_EXPIRED"},
+	{services.ErrSessionNotFound, http.StatusNotFound, "SESSION_NOT_FOUND"},
+	{services.ErrInvalidScope, http.StatusBadRequest, "INVALID_SCOPE"},
+	{services.ErrGuestBanned, http.StatusForbidden, "GUEST_BANNED"},
+	{services.ErrConsentRequired, http.StatusUnprocessableEntity, "CONSENT_REQUIRED"},
+	{captcha.ErrVerificationFailed, http.StatusForbidden, "CAPTCHA_FAILED"},
+	{services.ErrGuestLimitReached, http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+	{services.ErrPhotoLimitReached, http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+	{services.ErrPhotoQuotaExceeded, http.StatusUnprocessableEntity, "QUOTA_EXCEEDED"},
+	{services.ErrPhotoNotFound, http.StatusNotFound, "PHOTO_NOT_FOUND"},
+	{services.ErrGuestDownloadsDisabled, http.StatusForbidden, "DOWNLOADS_DISABLED"},
+	{services.ErrMediaTypeNotAllowed, http.StatusUnsupportedMediaType, "MEDIA_TYPE_NOT_ALLOWED"},
+	{services.ErrInvalidMediaPolicy, http.StatusBadRequest, "INVALID_MEDIA_POLICY"},
+	{services.ErrInvalidLogoKey, http.StatusBadRequest, "INVALID_LOGO_KEY"},
+	{services.ErrNoPhotos, http.StatusNotFound, "NO_PHOTOS"},
+	{services.ErrArchiveExpired, http.StatusGone, "ARCHIVE_EXPIRED"},
+	{services.ErrGalleryNotPublic, http.StatusNotFound, "GALLERY_NOT_PUBLIC"},
+	{services.ErrGalleryPrivate, http.StatusForbidden, "GALLERY_PRIVATE"},
+	{services.ErrImageNotRenderable, http.StatusUnsupportedMediaType, "IMAGE_NOT_RENDERABLE"},
+	{services.ErrImageTooLarge, http.StatusUnprocessableEntity, "IMAGE_TOO_LARGE"},
+	{services.ErrMediaBusy, http.StatusServiceUnavailable, "MEDIA_BUSY"},
+	{services.ErrWrapUpStarted, http.StatusConflict, "WRAP_UP_STARTED"},
+	{services.ErrAlreadyCollaborator, http.StatusConflict, "ALREADY_COLLABORATOR"},
+	{services.ErrInvitationNotFound, http.StatusNotFound, "INVITATION_NOT_FOUND"},
+	{services.ErrWebhookNotFound, http.StatusNotFound, "WEBHOOK_NOT_FOUND"},
+	{services.ErrInvalidWebhookEventType, http.StatusBadRequest, "INVALID_WEBHOOK_EVENT_TYPE"},
+	{services.ErrInvalidWebhookURL, http.StatusBadRequest, "INVALID_WEBHOOK_URL"},
+	{services.ErrUnknownWebhookSource, http.StatusNotFound, "UNKNOWN_WEBHOOK_SOURCE"},
+	{services.ErrWebhookReplayed, http.StatusConflict, "WEBHOOK_REPLAYED"},
+	{webhooksig.ErrMissingSignature, http.StatusUnauthorized, "WEBHOOK_SIGNATURE_MISSING"},
+	{webhooksig.ErrInvalidSignature, http.StatusUnauthorized, "WEBHOOK_SIGNATURE_INVALID"},
+	{webhooksig.ErrTimestampOutOfRange, http.StatusUnauthorized, "WEBHOOK_TIMESTAMP_OUT_OF_RANGE"},
+	{services.ErrEmailTaken, http.StatusConflict, "EMAIL_TAKEN"},
+	{services.ErrInvalidCredentials, http.StatusUnauthorized, "INVALID_CREDENTIALS"},
+	{services.ErrEmailUnverified, http.StatusForbidden, "EMAIL_UNVERIFIED"},
+	{services.ErrInvalidRefreshToken, http.StatusUnauthorized, "INVALID_REFRESH_TOKEN"},
+	{services.ErrInvalidMagicLink, http.StatusUnauthorized, "INVALID_MAGIC_LINK"},
+	{services.ErrOAuthEmailUnverified, http.StatusForbidden, "OAUTH_EMAIL_UNVERIFIED"},
+	{storage.ErrUnavailable, http.StatusServiceUnavailable, "STORAGE_UNAVAILABLE"},
+	{gorm.ErrRecordNotFound, http.StatusNotFound, "NOT_FOUND"},
+}
+
+// genericStatuses are the statuses answered with a status-derived code, e.g.
+// NOT_FOUND, when no service error explains them
+var genericStatuses = []int{
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusConflict,
+	http.StatusRequestEntityTooLarge,
+	http.StatusUnsupportedMediaType,
+	http.StatusUnprocessableEntity,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusServiceUnavailable,
+}
+
+// fail returns an HTTP error with status caused by err. The error handler
+// only shows clients the message of known service errors; anything else is
+// logged but not returned.
+func fail(status int, err error) *echo.HTTPError {
+	return echo.NewHTTPError(status).SetInternal(err)
+}
+
+// sessionError maps a failed session lookup to 401, or to 500 when the
+// lookup itself failed
+func sessionError(err error) *echo.HTTPError {
+	if errors.Is(err, services.ErrSessionExpired) || errors.Is(err, services.ErrEventNotActive) {
+		return fail(http.StatusUnauthorized, err)
+	}
+	return fail(http.StatusInternalServerError, err)
+}
+
+// HTTPErrorHandler writes every error as a problem+json response, with
+// Detail and field messages in the language the client prefers. The request
+// logger records the full error, including the cause hidden here.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	lang := requestLang(c)
+	p := newProblem(err, lang)
+	p.Instance = c.Request().URL.Path
+	p.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	c.Response().Header().Set(HeaderContentLanguage, string(lang))
+	// Tell clients when storage calls will be let through again
+	var open *storage.CircuitOpenError
+	if errors.As(err, &open) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(open.RetryAfter.Seconds()))))
+	}
+	c.Response().Header().Add(echo.HeaderVary, HeaderAcceptLanguage)
+
+	var writeErr error
+	if c.Request().Method == http.MethodHead {
+		writeErr = c.NoContent(p.Status)
+	} else {
+		body, _ := json.Marshal(p)
+		writeErr = c.Blob(p.Status, ProblemContentType, body)
+	}
+	if writeErr != nil {
+		c.Logger().Error(writeErr)
+	}
+}
+
+func newProblem(err error, lang i18n.Lang) Problem {
+	status := http.StatusInternalServerError
+	cause := err
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		status = he.Code
+		cause = he.Internal
+	}
+
+	p := Problem{Status: status, Code: statusCode(status)}
+	if known, ok := lookupErrorCode(cause); ok {
+		// Handlers fall back to 500 for errors they do not expect
+		if he == nil || he.Code == http.StatusInternalServerError {
+			p.Status = known.status
+		}
+		p.Code = known.code
+		p.Detail = i18n.T(lang, known.err.Error())
+	} else if cause == nil {
+		// Messages given directly to echo.NewHTTPError are written for clients
+		if msg, ok := he.Message.(string); ok && msg != http.StatusText(status) {
+			p.Detail = i18n.T(lang, msg)
+		}
+	} else if p.Status < http.StatusInternalServerError {
+		p.Detail, p.Errors = clientDetail(cause, lang)
+		if p.Errors != nil {
+			p.Code = CodeValidationFailed
+		}
+	}
+
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	return p
+}
+
+func lookupErrorCode(err error) (errorCode, bool) {
+	if err == nil {
+		return errorCode{}, false
+	}
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known, true
+		}
+	}
+	return errorCode{}, false
+}
+
+// clientDetail explains a 4xx error. Bind and validation errors are safe to
+// show; other errors are shown only when they wrap nothing, since wrapped
+// errors carry database or storage messages.
+func clientDetail(err error, lang i18n.Lang) (string, []FieldError) {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return i18n.T(lang, "request validation failed"), fieldErrors(validationErrs, lang)
+	}
+
+	var bindErr *echo.HTTPError
+	if errors.As(err, &bindErr) {
+		msg, _ := bindErr.Message.(string)
+		return msg, nil
+	}
+
+	if !wrapsError(err) {
+		return i18n.T(lang, err.Error()), nil
+	}
+	return "", nil
+}
+
+func wrapsError(err error) bool {
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return true
+	}
+	return false
+}
+
+// statusCode is the code of errors that have no more specific one, e.g.
+// NOT_FOUND for 404
+func statusCode(status int) string {
+	switch status {
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusInternalServerError:
+		return CodeInternal
+	}
+
+	text := http.StatusText(status)
+	if text == "" {
+		return CodeInternal
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
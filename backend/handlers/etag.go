@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// notModified sets a weak ETag derived from parts and reports whether the
+// client already holds that version per If-None-Match, in which case the
+// handler should answer 304. Responses must be revalidated on every use.
+func notModified(c echo.Context, parts ...string) bool {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "private, no-cache")
+	header.Add("Vary", echo.HeaderAuthorization)
+
+	for _, candidate := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-None-Match uses weak comparison, which ignores the W/ prefix
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// notModifiedSince sets Last-Modified to modified and reports whether the
+// client's copy is still current per If-Modified-Since, in which case the
+// handler should answer 304. HTTP dates have whole seconds, so modified is
+// truncated. For public responses that clients may keep but must revalidate.
+func notModifiedSince(c echo.Context, modified time.Time) bool {
+	modified = modified.UTC().Truncate(time.Second)
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderLastModified, modified.Format(http.TimeFormat))
+	header.Set("Cache-Control", "no-cache")
+
+	req := c.Request()
+	// If-None-Match takes precedence, and these responses carry no ETag to match
+	if req.Header.Get("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -9,43 +11,118 @@ import (
 
 	"snapShare/models"
 	"snapShare/services"
+	"snapShare/utils"
 )
 
 // Request DTOs
 type CreateEventRequest struct {
-	Name        string     `json:"name" validate:"required,min=1,max=255"`
-	Description *string    `json:"description,omitempty" validate:"omitempty,max=1000"`
-	EventDate   *time.Time `json:"event_date,omitempty"`
-	OwnerEmail  string     `json:"owner_email" validate:"required,email"`
+	Name                string                   `json:"name" validate:"required,min=1,max=255"`
+	Description         *string                  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	EventDate           *time.Time               `json:"event_date,omitempty"`
+	Timezone            string                   `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	MaxGuests           *int                     `json:"max_guests,omitempty" validate:"omitempty,min=1"`
+	MaxPhotos           *int                     `json:"max_photos,omitempty" validate:"omitempty,min=1"`
+	CloseAt             *time.Time               `json:"close_at,omitempty"`
+	RetentionDays       *int                     `json:"retention_days,omitempty" validate:"omitempty,min=1,max=3650"`
+	SessionTTLHours     *int                     `json:"session_ttl_hours,omitempty" validate:"omitempty,min=1,max=720"`
+	GuestbookPublic     bool                     `json:"guestbook_public,omitempty"`
+	ConsentText         *string                  `json:"consent_text,omitempty" validate:"omitempty,max=10000"`
+	RequireCaptcha      bool                     `json:"require_captcha,omitempty"`
+	AllowGuestDownloads *bool                    `json:"allow_guest_downloads,omitempty"`
+	GalleryVisibility   models.GalleryVisibility `json:"gallery_visibility,omitempty" validate:"omitempty,oneof=private guests public"`
+	MediaPolicy         models.MediaPolicy       `json:"media_policy,omitempty" validate:"omitempty,oneof=images images_videos custom"`
+	AllowedMimeTypes    []string                 `json:"allowed_mime_types,omitempty" validate:"omitempty,max=50,dive,min=3,max=100,contains=/"`
 }
 
 type UpdateEventRequest struct {
-	Name        *string             `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
-	Description *string             `json:"description,omitempty" validate:"omitempty,max=1000"`
-	EventDate   *time.Time          `json:"event_date,omitempty"`
-	Status      *models.EventStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive closed"`
+	Name                *string                   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description         *string                   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	EventDate           *time.Time                `json:"event_date,omitempty"`
+	Timezone            *string                   `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Status              *models.EventStatus       `json:"status,omitempty" validate:"omitempty,oneof=active inactive closed"`
+	MaxGuests           *int                      `json:"max_guests,omitempty" validate:"omitempty,min=1"`
+	MaxPhotos           *int                      `json:"max_photos,omitempty" validate:"omitempty,min=1"`
+	CloseAt             *time.Time                `json:"close_at,omitempty"`
+	RetentionDays       *int                      `json:"retention_days,omitempty" validate:"omitempty,min=1,max=3650"`
+	SessionTTLHours     *int                      `json:"session_ttl_hours,omitempty" validate:"omitempty,min=1,max=720"`
+	GuestbookPublic     *bool                     `json:"guestbook_public,omitempty"`
+	ConsentText         *string                   `json:"consent_text,omitempty" validate:"omitempty,max=10000"`
+	RequireCaptcha      *bool                     `json:"require_captcha,omitempty"`
+	AllowGuestDownloads *bool                     `json:"allow_guest_downloads,omitempty"`
+	GalleryVisibility   *models.GalleryVisibility `json:"gallery_visibility,omitempty" validate:"omitempty,oneof=private guests public"`
+	MediaPolicy         *models.MediaPolicy       `json:"media_policy,omitempty" validate:"omitempty,oneof=images images_videos custom"`
+	AllowedMimeTypes    []string                  `json:"allowed_mime_types,omitempty" validate:"omitempty,max=50,dive,min=3,max=100,contains=/"`
+}
+
+type DuplicateEventRequest struct {
+	Name      *string    `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	EventDate *time.Time `json:"event_date,omitempty"`
+}
+
+type ReopenEventRequest struct {
+	CloseAt *time.Time `json:"close_at,omitempty"`
+}
+
+type EventListQuery struct {
+	Status models.EventStatus `query:"status" validate:"omitempty,oneof=active inactive closed"`
+	PageQuery
+	FieldsQuery
+}
+
+type QRCodeQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=png svg"`
+	Size   int    `query:"size" validate:"omitempty,min=64,max=2048"`
+	Level  string `query:"ecc" validate:"omitempty,oneof=L M Q H l m q h"`
 }
 
 // Response DTOs
 type EventResponse struct {
-	ID          string              `json:"id"`
-	Name        string              `json:"name"`
-	Code        string              `json:"code"`
-	Description *string             `json:"description,omitempty"`
-	EventDate   *time.Time          `json:"event_date,omitempty"`
-	Status      models.EventStatus  `json:"status"`
-	OwnerEmail  string              `json:"owner_email"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	ID                  string                   `json:"id"`
+	Name                string                   `json:"name"`
+	Code                string                   `json:"code"`
+	Description         *string                  `json:"description,omitempty"`
+	EventDate           *time.Time               `json:"event_date,omitempty"`
+	Timezone            string                   `json:"timezone"`
+	Status              models.EventStatus       `json:"status"`
+	OwnerEmail          string                   `json:"owner_email"`
+	MaxGuests           *int                     `json:"max_guests,omitempty"`
+	MaxPhotos           *int                     `json:"max_photos,omitempty"`
+	CloseAt             *time.Time               `json:"close_at,omitempty"`
+	RetentionDays       *int                     `json:"retention_days,omitempty"`
+	SessionTTLHours     *int                     `json:"session_ttl_hours,omitempty"`
+	PurgeAt             *time.Time               `json:"purge_at,omitempty"`
+	GuestbookPublic     bool                     `json:"guestbook_public"`
+	ConsentText         *string                  `json:"consent_text,omitempty"`
+	ConsentVersion      int                      `json:"consent_version"`
+	RequireCaptcha      bool                     `json:"require_captcha"`
+	AllowGuestDownloads bool                     `json:"allow_guest_downloads"`
+	GalleryVisibility   models.GalleryVisibility `json:"gallery_visibility"`
+	MediaPolicy         models.MediaPolicy       `json:"media_policy"`
+	AllowedMimeTypes    []string                 `json:"allowed_mime_types,omitempty"`
+	Theme               *EventThemeResponse      `json:"theme,omitempty"`
+	CreatedAt           time.Time                `json:"created_at"`
+	UpdatedAt           time.Time                `json:"updated_at"`
+	Links               EventLinks               `json:"links"`
 }
 
+const (
+	defaultEventListLimit = 20
+	maxEventListLimit     = 100
+)
+
 type EventHandler struct {
-	eventService *services.EventService
+	eventService   *services.EventService
+	archiveService *services.ArchiveService
+	themeService   *services.ThemeService
+	auditService   *services.AuditService
 }
 
-func NewEventHandler(eventService *services.EventService) *EventHandler {
+func NewEventHandler(eventService *services.EventService, archiveService *services.ArchiveService, themeService *services.ThemeService, auditService *services.AuditService) *EventHandler {
 	return &EventHandler{
-		eventService: eventService,
+		eventService:   eventService,
+		archiveService: archiveService,
+		themeService:   themeService,
+		auditService:   auditService,
 	}
 }
 
@@ -53,36 +130,132 @@ func NewEventHandler(eventService *services.EventService) *EventHandler {
 func (h *EventHandler) CreateEvent(c echo.Context) error {
 	var req CreateEventRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
+	}
+
+	owner, ok := currentOwner(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
 	}
 
 	// Convert to service layer request
 	serviceReq := &services.CreateEventRequest{
-		Name:        req.Name,
-		Description: req.Description,
-		EventDate:   req.EventDate,
-		OwnerEmail:  req.OwnerEmail,
+		Name:                req.Name,
+		Description:         req.Description,
+		EventDate:           req.EventDate,
+		Timezone:            req.Timezone,
+		OwnerEmail:          owner.Email,
+		UserID:              &owner.UserID,
+		MaxGuests:           req.MaxGuests,
+		MaxPhotos:           req.MaxPhotos,
+		CloseAt:             req.CloseAt,
+		RetentionDays:       req.RetentionDays,
+		SessionTTLHours:     req.SessionTTLHours,
+		GuestbookPublic:     req.GuestbookPublic,
+		ConsentText:         req.ConsentText,
+		RequireCaptcha:      req.RequireCaptcha,
+		AllowGuestDownloads: req.AllowGuestDownloads,
+		GalleryVisibility:   req.GalleryVisibility,
+		MediaPolicy:         req.MediaPolicy,
+		AllowedMimeTypes:    req.AllowedMimeTypes,
 	}
 
 	event, err := h.eventService.CreateEvent(c.Request().Context(), serviceReq)
+	if errors.Is(err, services.ErrInvalidMediaPolicy) || errors.Is(err, services.ErrInvalidTimezone) {
+		return fail(http.StatusBadRequest, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	response := EventResponse{
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, true),
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// DuplicateEvent creates a new event with a fresh code from an existing event's settings
+func (h *EventHandler) DuplicateEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req DuplicateEventRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	serviceReq := &services.DuplicateEventRequest{
+		Name:      req.Name,
+		EventDate: req.EventDate,
+	}
+
+	event, err := h.eventService.DuplicateEvent(c.Request().Context(), eventID, serviceReq)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
 	response := EventResponse{
-		ID:          event.ID.String(),
-		Name:        event.Name,
-		Code:        event.Code,
-		Description: event.Description,
-		EventDate:   event.EventDate,
-		Status:      event.Status,
-		OwnerEmail:  event.OwnerEmail,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, true),
 	}
 
 	return c.JSON(http.StatusCreated, response)
@@ -98,19 +271,36 @@ func (h *EventHandler) GetEventByID(c echo.Context) error {
 
 	event, err := h.eventService.GetEventByID(c.Request().Context(), eventID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return fail(http.StatusNotFound, err)
 	}
 
 	response := EventResponse{
-		ID:          event.ID.String(),
-		Name:        event.Name,
-		Code:        event.Code,
-		Description: event.Description,
-		EventDate:   event.EventDate,
-		Status:      event.Status,
-		OwnerEmail:  event.OwnerEmail,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, true),
+		Theme:               toEventThemeResponse(h.themeService, event),
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -124,54 +314,171 @@ func (h *EventHandler) GetEventByCode(c echo.Context) error {
 	}
 
 	event, err := h.eventService.GetEventByCode(c.Request().Context(), code)
+	if errors.Is(err, services.ErrEventNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	// Guests reload the join page repeatedly on venue Wi-Fi
+	if notModifiedSince(c, event.UpdatedAt) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	response := EventResponse{
-		ID:          event.ID.String(),
-		Name:        event.Name,
-		Code:        event.Code,
-		Description: event.Description,
-		EventDate:   event.EventDate,
-		Status:      event.Status,
-		OwnerEmail:  event.OwnerEmail,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, false),
+		Theme:               toEventThemeResponse(h.themeService, event),
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
-// GetEventsByOwner retrieves all events owned by a user
+// GetEventQRCode renders a QR code of the event's join URL for printing.
+// Supports ?format=png|svg, ?size= (pixels) and ?ecc=L|M|Q|H
+func (h *EventHandler) GetEventQRCode(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var query QRCodeQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if query.Size == 0 {
+		query.Size = 512
+	}
+
+	level, err := utils.QRRecoveryLevel(query.Level)
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	event, err := h.eventService.GetEventByID(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	joinURL := h.eventService.JoinURL(event.Code)
+
+	if query.Format == "svg" {
+		svg, err := utils.GenerateQRCodeSVG(joinURL, query.Size, level)
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+		return c.Blob(http.StatusOK, "image/svg+xml", svg)
+	}
+
+	png, err := utils.GenerateQRCodePNG(joinURL, query.Size, level)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	return c.Blob(http.StatusOK, "image/png", png)
+}
+
+// GetEventsByOwner retrieves a page of events owned or co-hosted by the authenticated owner.
+// Supports ?status=, ?limit=, ?cursor= (next_cursor of the previous page) and ?fields=
 func (h *EventHandler) GetEventsByOwner(c echo.Context) error {
-	ownerEmail := c.QueryParam("owner_email")
-	if ownerEmail == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "owner_email query parameter is required")
+	var query EventListQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	fields, err := query.FieldNames(EventResponse{})
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	filter := services.EventFilter{
+		Status: query.Status,
+		Limit:  query.PageLimit(defaultEventListLimit, maxEventListLimit),
+		Cursor: query.Cursor,
 	}
 
-	events, err := h.eventService.GetEventsByOwner(c.Request().Context(), ownerEmail)
+	owner, ok := currentOwner(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "owner authentication required")
+	}
+
+	page, err := h.eventService.GetEventsByOwner(c.Request().Context(), owner, filter)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		return fail(http.StatusBadRequest, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
 	// Convert to response DTOs
-	responses := make([]EventResponse, len(events))
-	for i, event := range events {
+	responses := make([]EventResponse, len(page.Events))
+	for i, event := range page.Events {
 		responses[i] = EventResponse{
-			ID:          event.ID.String(),
-			Name:        event.Name,
-			Code:        event.Code,
-			Description: event.Description,
-			EventDate:   event.EventDate,
-			Status:      event.Status,
-			OwnerEmail:  event.OwnerEmail,
-			CreatedAt:   event.CreatedAt,
-			UpdatedAt:   event.UpdatedAt,
+			ID:                  event.ID.String(),
+			Name:                event.Name,
+			Code:                event.Code,
+			Description:         event.Description,
+			EventDate:           event.EventDate,
+			Timezone:            event.Timezone,
+			Status:              event.Status,
+			OwnerEmail:          event.OwnerEmail,
+			MaxGuests:           event.MaxGuests,
+			MaxPhotos:           event.MaxPhotos,
+			CloseAt:             event.CloseAt,
+			RetentionDays:       event.RetentionDays,
+			SessionTTLHours:     event.SessionTTLHours,
+			PurgeAt:             event.PurgeAt,
+			GuestbookPublic:     event.GuestbookPublic,
+			ConsentText:         event.ConsentText,
+			ConsentVersion:      event.ConsentVersion,
+			RequireCaptcha:      event.RequireCaptcha,
+			AllowGuestDownloads: event.AllowGuestDownloads,
+			GalleryVisibility:   event.GalleryVisibility,
+			MediaPolicy:         event.MediaPolicy,
+			AllowedMimeTypes:    services.AllowedMimeTypes(&event),
+			CreatedAt:           event.CreatedAt,
+			UpdatedAt:           event.UpdatedAt,
+			Links:               eventLinks(&event, true),
 		}
 	}
 
-	return c.JSON(http.StatusOK, map[string]any{"events": responses})
+	response := newListResponse(c, responses, page.NextCursor)
+	response.Meta.Total = &page.Total
+
+	return listJSON(c, response, fields)
 }
 
 // UpdateEvent updates an existing event
@@ -184,36 +491,76 @@ func (h *EventHandler) UpdateEvent(c echo.Context) error {
 
 	var req UpdateEventRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	// Convert to service layer request
 	serviceReq := &services.UpdateEventRequest{
-		Name:        req.Name,
-		Description: req.Description,
-		EventDate:   req.EventDate,
-		Status:      req.Status,
+		Name:                req.Name,
+		Description:         req.Description,
+		EventDate:           req.EventDate,
+		Timezone:            req.Timezone,
+		Status:              req.Status,
+		MaxGuests:           req.MaxGuests,
+		MaxPhotos:           req.MaxPhotos,
+		CloseAt:             req.CloseAt,
+		RetentionDays:       req.RetentionDays,
+		SessionTTLHours:     req.SessionTTLHours,
+		GuestbookPublic:     req.GuestbookPublic,
+		ConsentText:         req.ConsentText,
+		RequireCaptcha:      req.RequireCaptcha,
+		AllowGuestDownloads: req.AllowGuestDownloads,
+		GalleryVisibility:   req.GalleryVisibility,
+		MediaPolicy:         req.MediaPolicy,
+		AllowedMimeTypes:    req.AllowedMimeTypes,
 	}
 
 	event, err := h.eventService.UpdateEvent(c.Request().Context(), eventID, serviceReq)
+	if errors.Is(err, services.ErrInvalidMediaPolicy) || errors.Is(err, services.ErrInvalidTimezone) {
+		return fail(http.StatusBadRequest, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "event.update",
+		TargetType: "event",
+		TargetID:   eventID.String(),
+		Details:    map[string]any{"changes": req},
+	})
+
 	response := EventResponse{
-		ID:          event.ID.String(),
-		Name:        event.Name,
-		Code:        event.Code,
-		Description: event.Description,
-		EventDate:   event.EventDate,
-		Status:      event.Status,
-		OwnerEmail:  event.OwnerEmail,
-		CreatedAt:   event.CreatedAt,
-		UpdatedAt:   event.UpdatedAt,
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, true),
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -227,16 +574,21 @@ func (h *EventHandler) DeleteEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
 
-	// TODO: Add authorization check to ensure only the owner can delete
-
 	if err := h.eventService.DeleteEvent(c.Request().Context(), eventID); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "event.delete",
+		TargetType: "event",
+		TargetID:   eventID.String(),
+	})
+
 	return c.JSON(http.StatusOK, map[string]string{"message": "event deleted"})
 }
 
-// CloseEvent closes an event (sets status to closed)
+// CloseEvent closes an event (sets status to closed) and emails the owner a full export
 func (h *EventHandler) CloseEvent(c echo.Context) error {
 	eventIDStr := c.Param("id")
 	eventID, err := uuid.Parse(eventIDStr)
@@ -244,11 +596,89 @@ func (h *EventHandler) CloseEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
 
-	// TODO: Add authorization check to ensure only the owner can close
-
 	if err := h.eventService.CloseEvent(c.Request().Context(), eventID); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "event.close",
+		TargetType: "event",
+		TargetID:   eventID.String(),
+	})
+
+	// The export is best effort; closing must not fail because of it
+	event, err := h.eventService.GetEventByID(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if _, err := h.archiveService.StartExport(c.Request().Context(), eventID, event.OwnerEmail); err != nil && !errors.Is(err, services.ErrNoPhotos) {
+		slog.ErrorContext(c.Request().Context(), "failed to start export for closed event", "event_id", eventID, "error", err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "event closed"})
-}
\ No newline at end of file
+}
+
+// ReopenEvent makes a closed event active again, optionally with a new close time
+func (h *EventHandler) ReopenEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req ReopenEventRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if req.CloseAt != nil && !req.CloseAt.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "close_at must be in the future")
+	}
+
+	event, err := h.eventService.ReopenEvent(c.Request().Context(), eventID, req.CloseAt)
+	if errors.Is(err, services.ErrEventNotClosed) {
+		return fail(http.StatusConflict, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "event.reopen",
+		TargetType: "event",
+		TargetID:   eventID.String(),
+		Details:    map[string]any{"close_at": req.CloseAt},
+	})
+
+	response := EventResponse{
+		ID:                  event.ID.String(),
+		Name:                event.Name,
+		Code:                event.Code,
+		Description:         event.Description,
+		EventDate:           event.EventDate,
+		Timezone:            event.Timezone,
+		Status:              event.Status,
+		OwnerEmail:          event.OwnerEmail,
+		MaxGuests:           event.MaxGuests,
+		MaxPhotos:           event.MaxPhotos,
+		CloseAt:             event.CloseAt,
+		RetentionDays:       event.RetentionDays,
+		SessionTTLHours:     event.SessionTTLHours,
+		PurgeAt:             event.PurgeAt,
+		GuestbookPublic:     event.GuestbookPublic,
+		ConsentText:         event.ConsentText,
+		ConsentVersion:      event.ConsentVersion,
+		RequireCaptcha:      event.RequireCaptcha,
+		AllowGuestDownloads: event.AllowGuestDownloads,
+		GalleryVisibility:   event.GalleryVisibility,
+		MediaPolicy:         event.MediaPolicy,
+		AllowedMimeTypes:    services.AllowedMimeTypes(event),
+		CreatedAt:           event.CreatedAt,
+		UpdatedAt:           event.UpdatedAt,
+		Links:               eventLinks(event, true),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
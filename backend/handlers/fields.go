@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FieldsQuery is the ?fields= parameter of listings that support sparse
+// fieldsets: a comma-separated list of the JSON fields each item keeps,
+// e.g. fields=id,object_key. Embed it in list queries.
+type FieldsQuery struct {
+	Fields string `query:"fields" validate:"omitempty,max=500"`
+}
+
+// FieldNames returns the requested fields, or nil when every field was
+// requested. Names must be JSON fields of item.
+func (q FieldsQuery) FieldNames(item any) ([]string, error) {
+	if strings.TrimSpace(q.Fields) == "" {
+		return nil, nil
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(item))
+	var names []string
+	for _, name := range strings.Split(q.Fields, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// listJSON writes response, trimming each of its items to fields. A nil
+// fields writes response unchanged.
+func listJSON(c echo.Context, response ListResponse, fields []string) error {
+	if fields == nil {
+		return c.JSON(http.StatusOK, response)
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(body["data"], &items); err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	trimmed := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		trimmed[i] = make(map[string]json.RawMessage, len(fields))
+		for _, name := range fields {
+			if value, ok := item[name]; ok {
+				trimmed[i][name] = value
+			}
+		}
+	}
+
+	if body["data"], err = json.Marshal(trimmed); err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, body)
+}
+
+// jsonFieldNames lists the JSON names of a struct's fields, including those
+// of embedded structs
+func jsonFieldNames(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+// Response DTOs
+type GalleryUpdateResponse struct {
+	PhotoID      string     `json:"photo_id"`
+	URL          string     `json:"url,omitempty"`
+	UploaderName string     `json:"uploader_name,omitempty"`
+	Caption      *string    `json:"caption,omitempty"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// streamKeepAlive is how often an idle stream sends a comment line so that
+// proxies do not close the connection
+const streamKeepAlive = 25 * time.Second
+
+type GalleryStreamHandler struct {
+	feed         *services.GalleryFeed
+	photoService *services.PhotoService
+}
+
+func NewGalleryStreamHandler(feed *services.GalleryFeed, photoService *services.PhotoService) *GalleryStreamHandler {
+	return &GalleryStreamHandler{
+		feed:         feed,
+		photoService: photoService,
+	}
+}
+
+// StreamGallery pushes photo.uploaded and photo.deleted updates of an event
+// as server-sent events, so live slideshows don't have to poll. Clients that
+// reconnect can catch up through the slideshow endpoint's cursor.
+// It must run after SessionHandler.EventViewerMiddleware.
+func (h *GalleryStreamHandler) StreamGallery(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	if err := checkGalleryVisible(c, h.photoService, eventID); err != nil {
+		return err
+	}
+
+	canDownload, err := callerCanDownload(c, h.photoService, eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	updates, unsubscribe := h.feed.Subscribe(eventID)
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case update := <-updates:
+			data, err := json.Marshal(h.toGalleryUpdateResponse(update, canDownload))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", update.Type, data); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// toGalleryUpdateResponse omits the object URL for callers who may not download
+func (h *GalleryStreamHandler) toGalleryUpdateResponse(update services.GalleryUpdate, canDownload bool) GalleryUpdateResponse {
+	response := GalleryUpdateResponse{PhotoID: update.Photo.ID.String()}
+	if update.Type != services.GalleryUpdatePhotoUploaded {
+		return response
+	}
+
+	photo := update.Photo
+	if canDownload {
+		response.URL = h.photoService.PublicURL(photo.ObjectKey)
+	}
+	response.UploaderName = photo.UploaderName
+	response.Caption = photo.Caption
+	response.Width = photo.Width
+	response.Height = photo.Height
+	response.ConfirmedAt = photo.ConfirmedAt
+	return response
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/labstack/echo/v4"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"snapShare/graph"
+)
+
+// graphQLComplexityLimit caps the fields a single query may resolve, lists
+// counting once per row they may return, so that nested lists cannot fan
+// out into hundreds of thousands of rows. It fits one event's largest photo
+// page, or a default page of events with a few fields of their photos.
+const graphQLComplexityLimit = 10000
+
+type GraphQLHandler struct {
+	server   *handler.Server
+	resolver *graph.Resolver
+}
+
+func NewGraphQLHandler(resolver *graph.Resolver) *GraphQLHandler {
+	server := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver, Complexity: graph.Complexity()}))
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(graphQLComplexityLimit))
+	server.SetErrorPresenter(graphQLError)
+
+	return &GraphQLHandler{
+		server:   server,
+		resolver: resolver,
+	}
+}
+
+// Query serves GraphQL queries of the signed-in owner; it must run after
+// AuthHandler.OwnerAuthMiddleware
+func (h *GraphQLHandler) Query(c echo.Context) error {
+	owner, _ := currentOwner(c)
+	ctx := h.resolver.WithLoaders(graph.WithOwner(c.Request().Context(), owner))
+
+	h.server.ServeHTTP(c.Response(), c.Request().WithContext(ctx))
+	return nil
+}
+
+// graphQLError reports service errors with the same codes as the REST API
+// in extensions.code. Unknown resolver errors are logged and hidden.
+func graphQLError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	if known, ok := lookupErrorCode(err); ok {
+		presented.Message = known.err.Error()
+		presented.Extensions = map[string]any{"code": known.code}
+		return presented
+	}
+
+	// Errors raised by gqlgen or the resolvers themselves are meant for clients
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err == nil {
+		return presented
+	}
+
+	slog.ErrorContext(ctx, "GraphQL resolver failed", "error", err)
+	presented.Message = "internal error"
+	presented.Extensions = map[string]any{"code": CodeInternal}
+	return presented
+}
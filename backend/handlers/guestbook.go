@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type CreateGuestbookEntryRequest struct {
+	Message string `json:"message" validate:"required,min=1,max=2000"`
+}
+
+type GuestbookQuery struct {
+	PageQuery
+}
+
+// Response DTOs
+type GuestbookEntryResponse struct {
+	ID        string    `json:"id"`
+	EventID   string    `json:"event_id"`
+	GuestName string    `json:"guest_name"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	defaultGuestbookLimit = 50
+	maxGuestbookLimit     = 200
+)
+
+type GuestbookHandler struct {
+	guestbookService *services.GuestbookService
+	auditService     *services.AuditService
+}
+
+func NewGuestbookHandler(guestbookService *services.GuestbookService, auditService *services.AuditService) *GuestbookHandler {
+	return &GuestbookHandler{
+		guestbookService: guestbookService,
+		auditService:     auditService,
+	}
+}
+
+// CreateEntry leaves a message in the guestbook of the current guest's event
+func (h *GuestbookHandler) CreateEntry(c echo.Context) error {
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+	}
+
+	var req CreateGuestbookEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	entry, err := h.guestbookService.CreateEntry(c.Request().Context(), session, req.Message)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, toGuestbookEntryResponse(entry))
+}
+
+// GetMyGuestbook lists the guestbook for the current guest: every entry when
+// the owner made the guestbook public, otherwise only the guest's own.
+// Supports ?limit= and ?cursor= (next_cursor of the previous page)
+func (h *GuestbookHandler) GetMyGuestbook(c echo.Context) error {
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+	}
+
+	query, err := bindGuestbookQuery(c)
+	if err != nil {
+		return err
+	}
+
+	public, err := h.guestbookService.IsPublic(c.Request().Context(), session.EventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	filter := services.GuestbookFilter{
+		Limit:  query.PageLimit(defaultGuestbookLimit, maxGuestbookLimit),
+		Cursor: query.Cursor,
+	}
+	if !public {
+		filter.GuestName = &session.GuestName
+	}
+
+	page, err := h.guestbookService.GetEntriesByEvent(c.Request().Context(), session.EventID, filter)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return listResponse(c, toGuestbookEntryResponses(page.Entries), page.NextCursor)
+}
+
+// GetGuestbookByEvent lists the guestbook entries of an event for its owner.
+// Supports ?limit= and ?cursor= (next_cursor of the previous page)
+func (h *GuestbookHandler) GetGuestbookByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	query, err := bindGuestbookQuery(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.guestbookService.GetEntriesByEvent(c.Request().Context(), eventID, services.GuestbookFilter{
+		Limit:  query.PageLimit(defaultGuestbookLimit, maxGuestbookLimit),
+		Cursor: query.Cursor,
+	})
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return listResponse(c, toGuestbookEntryResponses(page.Entries), page.NextCursor)
+}
+
+func bindGuestbookQuery(c echo.Context) (*GuestbookQuery, error) {
+	var query GuestbookQuery
+	if err := c.Bind(&query); err != nil {
+		return nil, fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return nil, fail(http.StatusBadRequest, err)
+	}
+
+	return &query, nil
+}
+
+// DeleteEntry removes a guestbook entry
+func (h *GuestbookHandler) DeleteEntry(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	entryIDStr := c.Param("entry_id")
+	entryID, err := uuid.Parse(entryIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid entry ID")
+	}
+
+	if err := h.guestbookService.DeleteEntry(c.Request().Context(), eventID, entryID); err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "moderation.guestbook_delete",
+		TargetType: "guestbook_entry",
+		TargetID:   entryID.String(),
+	})
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "guestbook entry deleted"})
+}
+
+func toGuestbookEntryResponse(entry *models.GuestbookEntry) GuestbookEntryResponse {
+	return GuestbookEntryResponse{
+		ID:        entry.ID.String(),
+		EventID:   entry.EventID.String(),
+		GuestName: entry.GuestName,
+		Message:   entry.Message,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func toGuestbookEntryResponses(entries []models.GuestbookEntry) []GuestbookEntryResponse {
+	responses := make([]GuestbookEntryResponse, len(entries))
+	for i := range entries {
+		responses[i] = toGuestbookEntryResponse(&entries[i])
+	}
+	return responses
+}
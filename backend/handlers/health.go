@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+	"snapShare/version"
+)
+
+// Response DTOs
+type LivenessResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+type ReadinessResponse struct {
+	Status  string                              `json:"status"`
+	Version string                              `json:"version"`
+	Checks  map[string]DependencyStatusResponse `json:"checks"`
+}
+
+type DependencyStatusResponse struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type VersionResponse struct {
+	GitSHA    string `json:"git_sha"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Modified  bool   `json:"modified"`
+}
+
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Liveness reports that the process is up; it checks no dependencies so a
+// database outage doesn't get every pod restarted
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, LivenessResponse{Status: "ok", Version: version.Short()})
+}
+
+// Readiness checks the database and R2, answering 503 with the status of
+// each dependency when any of them fails
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	response := ReadinessResponse{
+		Status:  "ok",
+		Version: version.Short(),
+		Checks:  map[string]DependencyStatusResponse{},
+	}
+
+	for _, dep := range h.healthService.CheckDependencies(c.Request().Context()) {
+		check := DependencyStatusResponse{
+			Status:    "ok",
+			LatencyMS: dep.Latency.Milliseconds(),
+		}
+		if dep.Err != nil {
+			check.Status = "error"
+			check.Error = dep.Err.Error()
+			response.Status = "unavailable"
+		}
+		response.Checks[dep.Name] = check
+	}
+
+	status := http.StatusOK
+	if response.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, response)
+}
+
+// Version reports which build is serving traffic
+func (h *HealthHandler) Version(c echo.Context) error {
+	info := version.Get()
+	return c.JSON(http.StatusOK, VersionResponse{
+		GitSHA:    info.GitSHA,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+		Modified:  info.Modified,
+	})
+}
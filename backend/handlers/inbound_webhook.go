@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+// maxInboundWebhookBody caps callback payloads; signatures cover the whole
+// body, so it is read in full before being verified
+const maxInboundWebhookBody = 1 << 20
+
+// Response DTOs
+type InboundWebhookResponse struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	DeliveryID string    `json:"delivery_id"`
+	SignedAt   time.Time `json:"signed_at"`
+}
+
+type InboundWebhookHandler struct {
+	inboundWebhookService *services.InboundWebhookService
+}
+
+func NewInboundWebhookHandler(inboundWebhookService *services.InboundWebhookService) *InboundWebhookHandler {
+	return &InboundWebhookHandler{
+		inboundWebhookService: inboundWebhookService,
+	}
+}
+
+// ReceiveWebhook accepts a callback signed with one of the :source secrets.
+// Senders sign "<timestamp>.<body>" as described in package webhooksig.
+func (h *InboundWebhookHandler) ReceiveWebhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxInboundWebhookBody+1))
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+	if len(body) > maxInboundWebhookBody {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "webhook payload is too large")
+	}
+
+	webhook, err := h.inboundWebhookService.Receive(c.Request().Context(), c.Param("source"), c.Request().Header, body)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, InboundWebhookResponse{
+		ID:         webhook.ID.String(),
+		Source:     webhook.Source,
+		DeliveryID: webhook.DeliveryID,
+		SignedAt:   webhook.SignedAt,
+	})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"snapShare/i18n"
+)
+
+const (
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+)
+
+// Language stores the language negotiated from Accept-Language in the
+// request context, where error responses and emails sent while serving the
+// request pick it up
+func Language() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if lang, ok := i18n.Negotiate(c.Request().Header.Get(HeaderAcceptLanguage)); ok {
+				req := c.Request()
+				c.SetRequest(req.WithContext(i18n.WithLang(req.Context(), lang)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// requestLang is the language of the client; API clients that do not ask
+// for one get English
+func requestLang(c echo.Context) i18n.Lang {
+	return i18n.FromContext(c.Request().Context(), i18n.English)
+}
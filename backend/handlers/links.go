@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+
+	"snapShare/models"
+)
+
+// EventLinks point at the resources of an event that the client receiving it
+// can use, so that clients follow links instead of building URLs
+type EventLinks struct {
+	Self      string `json:"self"`
+	Photos    string `json:"photos"`
+	Slideshow string `json:"slideshow"`
+	// QRCode is only given to owners
+	QRCode string `json:"qr_code,omitempty"`
+	// Gallery is the public gallery, when the event shares one
+	Gallery string `json:"gallery,omitempty"`
+}
+
+// PhotoLinks point at a photo and its renditions. Event is only given to
+// owners, since guests get their event with their session. Download is only
+// set when the client may fetch the original; Thumbnail once a downscaled
+// rendition is served for the photo.
+type PhotoLinks struct {
+	Self      string `json:"self"`
+	Event     string `json:"event,omitempty"`
+	Download  string `json:"download,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// apiPath builds a path under the current API version
+func apiPath(format string, args ...any) string {
+	return "/api/" + CurrentAPIVersion + fmt.Sprintf(format, args...)
+}
+
+// eventLinks links an event for its owner, or for a guest when owner is false
+func eventLinks(event *models.Event, owner bool) EventLinks {
+	links := EventLinks{
+		Self:      apiPath("/events/%s", event.Code),
+		Photos:    apiPath("/photos"),
+		Slideshow: apiPath("/events/%s/slideshow", event.ID),
+	}
+	if owner {
+		links.Self = apiPath("/events/%s/details", event.ID)
+		links.Photos = apiPath("/events/%s/photos", event.ID)
+		links.QRCode = apiPath("/events/%s/qr", event.ID)
+	}
+	if event.GalleryVisibility == models.GalleryVisibilityPublic {
+		links.Gallery = apiPath("/public/events/%s/photos", event.Code)
+	}
+	return links
+}
+
+// photoLinks links a photo for its event's owner, or for a guest when owner
+// is false; downloadURL is empty when the client may not download it
+func photoLinks(photo *models.Photo, downloadURL string, owner bool) PhotoLinks {
+	links := PhotoLinks{
+		Self:     apiPath("/photos/%s", photo.ID),
+		Download: downloadURL,
+	}
+	if owner {
+		links.Event = apiPath("/events/%s/details", photo.EventID)
+	}
+	return links
+}
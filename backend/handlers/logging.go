@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"log/slog"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"snapShare/infra/logging"
+)
+
+// validRequestID matches the IDs accepted from clients and proxies
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID assigns every request an ID, returned in X-Request-ID and in
+// error responses, and puts it in the request context so that handler and
+// service logs can be correlated. An ID sent by a proxy in X-Request-ID is
+// kept when it is well formed.
+func RequestID() echo.MiddlewareFunc {
+	requestID := middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			c.SetRequest(c.Request().WithContext(logging.WithRequestID(c.Request().Context(), id)))
+		},
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		handler := requestID(next)
+		return func(c echo.Context) error {
+			header := c.Request().Header
+			if id := header.Get(echo.HeaderXRequestID); id != "" && !validRequestID.MatchString(id) {
+				header.Del(echo.HeaderXRequestID)
+			}
+			return handler(c)
+		}
+	}
+}
+
+// RequestLogger logs one JSON line per request. Only the path is logged since
+// query strings can carry OAuth codes.
+func RequestLogger() echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURIPath:   true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			if v.Status >= 500 {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("path", v.URIPath),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			slog.LogAttrs(c.Request().Context(), level, "request", attrs...)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/i18n"
+)
+
+// Response DTOs
+type ErrorCodeResponse struct {
+	Code   string `json:"code"`
+	Status int    `json:"status"`
+	// Retryable tells clients whether repeating the same request later may
+	// succeed; honour Retry-After when the response has one
+	Retryable   bool   `json:"retryable"`
+	Description string `json:"description"`
+}
+
+// MetaHandler describes the API itself to clients and SDK generators
+type MetaHandler struct{}
+
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// GetErrorCodes lists every code error responses may carry, with the status
+// it comes with, so that clients can branch on codes without reading the source
+func (h *MetaHandler) GetErrorCodes(c echo.Context) error {
+	lang := requestLang(c)
+	seen := map[ErrorCodeResponse]bool{}
+	var codes []ErrorCodeResponse
+	add := func(code string, status int, description string) {
+		key := ErrorCodeResponse{Code: code, Status: status}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		codes = append(codes, ErrorCodeResponse{
+			Code:        code,
+			Status:      status,
+			Retryable:   retryable(code, status),
+			Description: i18n.T(lang, description),
+		})
+	}
+
+	for _, known := range errorCodes {
+		add(known.code, known.status, known.err.Error())
+	}
+	add(CodeValidationFailed, http.StatusBadRequest, "request validation failed")
+	for _, status := range genericStatuses {
+		add(statusCode(status), status, http.StatusText(status))
+	}
+
+	slices.SortFunc(codes, func(a, b ErrorCodeResponse) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), cmp.Compare(a.Status, b.Status))
+	})
+
+	// The registry only changes with a deploy
+	c.Response().Header().Set("Cache-Control", "public, max-age=3600")
+	c.Response().Header().Add(echo.HeaderVary, HeaderAcceptLanguage)
+	return listResponse(c, codes, "")
+}
+
+// retryable reports whether an error may go away by itself: rate limits and
+// server-side failures do, while reached quotas and client errors do not
+func retryable(code string, status int) bool {
+	return code == CodeRateLimited || status >= http.StatusInternalServerError
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type MetricsHandler struct {
+	metricsService *services.MetricsService
+}
+
+func NewMetricsHandler(metricsService *services.MetricsService) *MetricsHandler {
+	return &MetricsHandler{
+		metricsService: metricsService,
+	}
+}
+
+// GetMetrics exposes storage, processing and queue gauges in OpenMetrics
+// text format. Labels carry event IDs, so it must stay behind admin auth.
+func (h *MetricsHandler) GetMetrics(c echo.Context) error {
+	storage, err := h.metricsService.CollectEventStorage(c.Request().Context())
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	totals, err := h.metricsService.CollectStorageTotals(c.Request().Context())
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	queues, err := h.metricsService.CollectQueues(c.Request().Context())
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# TYPE snapshare_storage_bytes gauge\n")
+	b.WriteString("# UNIT snapshare_storage_bytes bytes\n")
+	b.WriteString("# HELP snapshare_storage_bytes Bytes of confirmed photos stored across all events.\n")
+	fmt.Fprintf(&b, "snapshare_storage_bytes %d\n", totals.StorageBytes)
+
+	b.WriteString("# TYPE snapshare_photos gauge\n")
+	b.WriteString("# HELP snapshare_photos Confirmed photos across all events.\n")
+	fmt.Fprintf(&b, "snapshare_photos %d\n", totals.PhotoCount)
+
+	b.WriteString("# TYPE snapshare_event_storage_bytes gauge\n")
+	b.WriteString("# UNIT snapshare_event_storage_bytes bytes\n")
+	b.WriteString("# HELP snapshare_event_storage_bytes Bytes of confirmed photos stored per active event.\n")
+	for _, m := range storage {
+		fmt.Fprintf(&b, "snapshare_event_storage_bytes{event_id=%q} %d\n", m.EventID.String(), m.StorageBytes)
+	}
+
+	b.WriteString("# TYPE snapshare_event_photos gauge\n")
+	b.WriteString("# HELP snapshare_event_photos Confirmed photos per active event.\n")
+	for _, m := range storage {
+		fmt.Fprintf(&b, "snapshare_event_photos{event_id=%q} %d\n", m.EventID.String(), m.PhotoCount)
+	}
+
+	b.WriteString("# TYPE snapshare_event_pending_photos gauge\n")
+	b.WriteString("# HELP snapshare_event_pending_photos Photos of an active event whose upload URL is still valid but not yet confirmed.\n")
+	for _, m := range storage {
+		fmt.Fprintf(&b, "snapshare_event_pending_photos{event_id=%q} %d\n", m.EventID.String(), m.PendingPhotos)
+	}
+
+	b.WriteString("# TYPE snapshare_outbox_backlog gauge\n")
+	b.WriteString("# HELP snapshare_outbox_backlog Entries waiting in a background delivery queue.\n")
+	fmt.Fprintf(&b, "snapshare_outbox_backlog{queue=\"webhook_deliveries\"} %d\n", queues.PendingWebhookDeliveries)
+	fmt.Fprintf(&b, "snapshare_outbox_backlog{queue=\"object_deletions\"} %d\n", queues.PendingObjectDeletions)
+
+	b.WriteString("# TYPE snapshare_webhook_deliveries_retrying gauge\n")
+	b.WriteString("# HELP snapshare_webhook_deliveries_retrying Webhook deliveries that failed at least once and will be retried.\n")
+	fmt.Fprintf(&b, "snapshare_webhook_deliveries_retrying %d\n", queues.RetryingWebhookDeliveries)
+
+	b.WriteString("# TYPE snapshare_webhook_deliveries_failed gauge\n")
+	b.WriteString("# HELP snapshare_webhook_deliveries_failed Webhook deliveries that ran out of attempts.\n")
+	fmt.Fprintf(&b, "snapshare_webhook_deliveries_failed %d\n", queues.FailedWebhookDeliveries)
+
+	b.WriteString("# EOF\n")
+
+	return c.Blob(http.StatusOK, openMetricsContentType, []byte(b.String()))
+}
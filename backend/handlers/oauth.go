@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+	"snapShare/utils"
+)
+
+// oauthStateTTL is how long a user has to finish signing in with a provider
+const oauthStateTTL = 10 * time.Minute
+
+// StartOAuth redirects to the provider's consent page
+func (h *AuthHandler) StartOAuth(c echo.Context) error {
+	provider, ok := h.oauthProviders[c.Param("provider")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown sign-in provider")
+	}
+
+	state, err := utils.GenerateOAuthState(provider.Name, time.Now().Add(oauthStateTTL))
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.Redirect(http.StatusFound, provider.AuthCodeURL(state))
+}
+
+// OAuthCallback finishes a provider sign-in and redirects to the frontend's
+// /auth/callback with the tokens, or an error, in the URL fragment.
+// Google calls back with GET; Apple posts a form.
+func (h *AuthHandler) OAuthCallback(c echo.Context) error {
+	provider, ok := h.oauthProviders[c.Param("provider")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown sign-in provider")
+	}
+
+	if providerErr := c.FormValue("error"); providerErr != "" {
+		return h.redirectOAuthResult(c, url.Values{"error": {providerErr}})
+	}
+
+	if err := utils.ValidateOAuthState(c.FormValue("state"), provider.Name); err != nil {
+		return h.redirectOAuthResult(c, url.Values{"error": {"invalid_state"}})
+	}
+
+	identity, err := provider.Exchange(c.Request().Context(), c.FormValue("code"))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "oauth sign-in failed", "provider", provider.Name, "error", err)
+		return h.redirectOAuthResult(c, url.Values{"error": {"exchange_failed"}})
+	}
+
+	user, err := h.authService.OAuthLogin(c.Request().Context(), identity)
+	if errors.Is(err, services.ErrOAuthEmailUnverified) {
+		return h.redirectOAuthResult(c, url.Values{"error": {"email_unverified"}})
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	tokens, err := h.authService.IssueTokens(c.Request().Context(), user)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return h.redirectOAuthResult(c, url.Values{
+		"access_token":  {tokens.AccessToken},
+		"expires_at":    {tokens.AccessExpiresAt.Format(time.RFC3339)},
+		"refresh_token": {tokens.RefreshToken},
+	})
+}
+
+// redirectOAuthResult passes the result in the fragment so tokens never reach
+// server logs or Referer headers
+func (h *AuthHandler) redirectOAuthResult(c echo.Context, values url.Values) error {
+	return c.Redirect(http.StatusFound, h.authService.AppURL("/auth/callback#"+values.Encode()))
+}
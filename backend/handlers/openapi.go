@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// apiDoc describes a route for the OpenAPI document. Request and Response are
+// sample values whose types are reflected into schemas; maps describe ad-hoc
+// JSON objects by their keys.
+type apiDoc struct {
+	Summary string
+	// Auth is "owner", "session" or "admin"; public routes leave it empty
+	Auth    string
+	Request any
+	// Status is the success status, 200 when zero
+	Status   int
+	Response any
+}
+
+// binaryBody is a Response for routes that return raw bytes of a content type
+type binaryBody string
+
+var messageBody = map[string]any{"message": ""}
+
+// apiDocs is keyed by method and echo route path. Routes without an entry are
+// still documented, without schemas.
+var apiDocs = map[string]apiDoc{
+	"POST /api/v1/auth/register":                 {Summary: "Email a link confirming a new owner account", Request: RegisterRequest{}, Status: http.StatusAccepted, Response: messageBody},
+	"POST /api/v1/auth/login":                    {Summary: "Sign in with email and password", Request: LoginRequest{}, Response: AuthResponse{}},
+	"POST /api/v1/auth/magic-link":               {Summary: "Email a sign-in link", Request: MagicLinkRequest{}, Status: http.StatusAccepted, Response: messageBody},
+	"POST /api/v1/auth/magic-link/verify":        {Summary: "Sign in with an emailed link", Request: VerifyMagicLinkRequest{}, Response: AuthResponse{}},
+	"GET /api/v1/auth/oauth/:provider":           {Summary: "Start OAuth sign-in", Status: http.StatusFound},
+	"GET /api/v1/auth/oauth/:provider/callback":  {Summary: "Complete OAuth sign-in", Status: http.StatusFound},
+	"POST /api/v1/auth/oauth/:provider/callback": {Summary: "Complete OAuth sign-in (form post)", Status: http.StatusFound},
+	"POST /api/v1/auth/refresh":                  {Summary: "Exchange a refresh token", Request: RefreshTokenRequest{}, Response: TokenResponse{}},
+	"POST /api/v1/auth/logout":                   {Summary: "Revoke a refresh token", Request: RefreshTokenRequest{}, Response: messageBody},
+	"GET /api/v1/auth/me":                        {Summary: "Get the signed-in owner", Auth: "owner", Response: UserResponse{}},
+	"GET /api/v1/owners/me/overview":             {Summary: "Summarise the owner's events", Auth: "owner", Response: OwnerOverviewResponse{}},
+
+	"POST /api/v1/sessions":         {Summary: "Join an event as a guest", Request: CreateSessionRequest{}, Status: http.StatusCreated, Response: SessionResponse{}},
+	"POST /api/v1/sessions/refresh": {Summary: "Refresh a guest session", Request: RefreshSessionRequest{}, Response: SessionResponse{}},
+	"DELETE /api/v1/sessions":       {Summary: "Revoke a guest session", Request: RevokeSessionRequest{}, Response: messageBody},
+	"DELETE /api/v1/sessions/all":   {Summary: "Sign out on every device", Auth: "session", Response: map[string]any{"message": "", "revoked": 0}},
+
+	"GET /api/v1/events":               {Summary: "List the owner's events", Auth: "owner", Response: listBody([]EventResponse{})},
+	"POST /api/v1/events":              {Summary: "Create an event", Auth: "owner", Request: CreateEventRequest{}, Status: http.StatusCreated, Response: EventResponse{}},
+	"GET /api/v1/events/:code":         {Summary: "Look up an open event by code", Response: EventResponse{}},
+	"GET /api/v1/events/:id/uploading": {Summary: "Count uploads in progress", Auth: "session", Response: map[string]any{"uploading": 0}},
+	"GET /api/v1/events/:id/slideshow": {Summary: "Get slideshow photos", Auth: "session", Response: SlideshowResponse{}},
+	"GET /api/v1/events/:id/stream":    {Summary: "Stream gallery updates (server-sent events)", Auth: "session", Response: binaryBody("text/event-stream")},
+
+	"POST /api/v1/events/:id/duplicate":                        {Summary: "Duplicate an event", Auth: "owner", Request: DuplicateEventRequest{}, Status: http.StatusCreated, Response: EventResponse{}},
+	"POST /api/v1/events/:id/reopen":                           {Summary: "Reopen a closed event", Auth: "owner", Request: ReopenEventRequest{}, Response: EventResponse{}},
+	"PUT /api/v1/events/:id/theme":                             {Summary: "Update the event theme", Auth: "owner", Request: UpdateThemeRequest{}, Response: EventThemeResponse{}},
+	"POST /api/v1/events/:id/theme/logo-upload-url":            {Summary: "Get a logo upload URL", Auth: "owner", Request: LogoUploadURLRequest{}, Response: LogoUploadURLResponse{}},
+	"GET /api/v1/events/:id/qr":                                {Summary: "Get the join QR code", Auth: "owner", Response: binaryBody("image/png")},
+	"GET /api/v1/events/:id/stats":                             {Summary: "Get event statistics", Auth: "owner", Response: EventStatsResponse{}},
+	"GET /api/v1/events/:id/stats/uploaders":                   {Summary: "List photo contributions per guest", Auth: "owner", Response: listBody([]UploaderStatsResponse{})},
+	"GET /api/v1/events/:id/guestbook":                         {Summary: "List guestbook entries", Auth: "owner", Response: listBody([]GuestbookEntryResponse{})},
+	"DELETE /api/v1/events/:id/guestbook/:entry_id":            {Summary: "Delete a guestbook entry", Auth: "owner", Response: messageBody},
+	"GET /api/v1/events/:id/photos":                            {Summary: "List event photos", Auth: "owner", Response: listBody([]GalleryPhotoResponse{})},
+	"GET /api/v1/events/:id/guests":                            {Summary: "List guests with their session counts", Auth: "owner", Response: listBody([]GuestSummaryResponse{})},
+	"GET /api/v1/events/:id/sessions":                          {Summary: "List guest sessions", Auth: "owner", Response: listBody([]SessionSummaryResponse{})},
+	"POST /api/v1/events/:id/sessions":                         {Summary: "Issue a guest session", Auth: "owner", Request: IssueSessionRequest{}, Status: http.StatusCreated, Response: SessionResponse{}},
+	"DELETE /api/v1/events/:id/sessions/:session_id":           {Summary: "Revoke a guest session", Auth: "owner", Response: messageBody},
+	"DELETE /api/v1/events/:id/sessions/:session_id/device":    {Summary: "Revoke every session of a device", Auth: "owner", Response: map[string]any{"message": "", "count": 0}},
+	"GET /api/v1/events/:id/bans":                              {Summary: "List banned guests", Auth: "owner", Response: listBody([]GuestBanResponse{})},
+	"POST /api/v1/events/:id/bans":                             {Summary: "Ban a guest", Auth: "owner", Request: BanGuestRequest{}, Response: BanGuestResponse{}},
+	"DELETE /api/v1/events/:id/bans/:guest_name":               {Summary: "Lift a ban", Auth: "owner", Response: messageBody},
+	"GET /api/v1/events/:id/audit-log":                         {Summary: "Get the audit log", Auth: "owner", Response: listBody([]AuditLogResponse{})},
+	"GET /api/v1/events/:id/consents":                          {Summary: "Export consent records", Auth: "owner", Response: map[string]any{"terms": []ConsentTermsResponse{}, "records": []ConsentRecordResponse{}}},
+	"GET /api/v1/events/:id/alerts":                            {Summary: "List threshold alerts", Auth: "owner", Response: listBody([]AlertResponse{})},
+	"POST /api/v1/events/:id/alerts":                           {Summary: "Create a threshold alert", Auth: "owner", Request: CreateAlertRequest{}, Status: http.StatusCreated, Response: AlertResponse{}},
+	"DELETE /api/v1/events/:id/alerts/:alert_id":               {Summary: "Delete a threshold alert", Auth: "owner", Response: messageBody},
+	"GET /api/v1/events/:id/webhooks":                          {Summary: "List webhooks", Auth: "owner", Response: listBody([]WebhookResponse{})},
+	"POST /api/v1/events/:id/webhooks":                         {Summary: "Register a webhook", Auth: "owner", Request: CreateWebhookRequest{}, Status: http.StatusCreated, Response: WebhookResponse{}},
+	"DELETE /api/v1/events/:id/webhooks/:webhook_id":           {Summary: "Delete a webhook", Auth: "owner", Response: messageBody},
+	"POST /api/v1/events/:id/download":                         {Summary: "Start a photo archive", Auth: "owner", Status: http.StatusAccepted, Response: ArchiveJobResponse{}},
+	"GET /api/v1/events/:id/download/status/:job_id":           {Summary: "Get archive status", Auth: "owner", Response: ArchiveJobResponse{}},
+	"GET /api/v1/events/:id/download/estimate":                 {Summary: "Estimate the archive size", Auth: "owner", Response: ArchiveEstimateResponse{}},
+	"POST /api/v1/events/:id/export":                           {Summary: "Start a full event export", Auth: "owner", Request: ExportRequest{}, Status: http.StatusAccepted, Response: ArchiveJobResponse{}},
+	"POST /api/v1/events/:id/wrap-up":                          {Summary: "Start the wrap-up workflow", Auth: "owner", Status: http.StatusAccepted, Response: WrapUpResponse{}},
+	"GET /api/v1/events/:id/wrap-up":                           {Summary: "Get wrap-up progress", Auth: "owner", Response: WrapUpResponse{}},
+	"GET /api/v1/events/:id/collaborators":                     {Summary: "List collaborators", Auth: "owner", Response: listBody([]CollaboratorResponse{})},
+	"POST /api/v1/events/:id/collaborators":                    {Summary: "Invite a collaborator", Auth: "owner", Request: InviteCollaboratorRequest{}, Status: http.StatusCreated, Response: CollaboratorResponse{}},
+	"DELETE /api/v1/events/:id/collaborators/:collaborator_id": {Summary: "Remove a collaborator", Auth: "owner", Response: messageBody},
+	"POST /api/v1/collaborators/accept":                        {Summary: "Accept a collaborator invitation", Auth: "owner", Request: AcceptInvitationRequest{}, Response: CollaboratorResponse{}},
+
+	"POST /api/v1/photos/upload-url":     {Summary: "Get a photo upload URL", Auth: "session", Request: UploadURLRequest{}, Response: UploadURLResponse{}},
+	"POST /api/v1/photos/confirm/:id":    {Summary: "Confirm an upload", Auth: "session", Request: ConfirmUploadRequest{}, Response: messageBody},
+	"POST /api/v1/photos/confirm":        {Summary: "Confirm several uploads", Auth: "session", Request: BulkConfirmRequest{}, Response: BulkConfirmResponse{}},
+	"POST /api/v1/photos/archive":        {Summary: "Start an archive of my photos", Auth: "session", Status: http.StatusAccepted, Response: ArchiveJobResponse{}},
+	"GET /api/v1/photos/archive/:job_id": {Summary: "Get my archive status", Auth: "session", Response: ArchiveJobResponse{}},
+	"GET /api/v1/photos":                 {Summary: "List the gallery of my event", Auth: "session", Response: listBody([]GalleryPhotoResponse{})},
+	"POST /api/v1/photos/batch-get":      {Summary: "Get several photos by ID", Auth: "session", Request: BatchGetPhotosRequest{}, Response: BatchGetPhotosResponse{}},
+	"GET /api/v1/photos/:id":             {Summary: "Get a photo", Auth: "session", Response: PhotoResponse{}},
+	"POST /api/v1/guestbook":             {Summary: "Sign the guestbook", Auth: "session", Request: CreateGuestbookEntryRequest{}, Status: http.StatusCreated, Response: GuestbookEntryResponse{}},
+	"GET /api/v1/guestbook":              {Summary: "List my guestbook entries", Auth: "session", Response: listBody([]GuestbookEntryResponse{})},
+
+	"GET /api/v1/meta/errors":               {Summary: "List error codes", Response: listBody([]ErrorCodeResponse{})},
+	"POST /api/v1/webhooks/inbound/:source": {Summary: "Receive a signed callback from an integration", Response: InboundWebhookResponse{}},
+
+	"GET /api/v1/public/events/:code/photos":                 {Summary: "Get the public gallery", Response: PublicGalleryResponse{}},
+	"GET /api/v1/public/events/:code/photos/:photo_id/image": {Summary: "Get a public gallery image", Response: binaryBody("image/jpeg")},
+
+	"POST /api/v1/admin/sessions/cleanup":             {Summary: "Delete expired sessions", Auth: "admin", Response: messageBody},
+	"POST /api/v1/admin/photos/:id/reassign-uploader": {Summary: "Reassign a photo's uploader", Auth: "admin", Request: ReassignUploaderRequest{}, Response: map[string]any{"message": "", "photo_id": ""}},
+	"POST /api/v1/admin/photos/:id/move":              {Summary: "Move a photo to another event", Auth: "admin", Request: MovePhotoRequest{}, Response: map[string]any{"message": "", "photo_id": ""}},
+	"POST /api/v1/admin/photos/:id/reprocess":         {Summary: "Reprocess a photo", Auth: "admin", Request: AdminActionRequest{}, Response: map[string]any{"message": "", "photo_id": ""}},
+	"POST /api/v1/admin/batches/:id/expire":           {Summary: "Expire an upload batch", Auth: "admin", Request: AdminActionRequest{}, Response: map[string]any{"message": "", "count": 0}},
+
+	"GET /healthz": {Summary: "Liveness check", Response: LivenessResponse{}},
+	"GET /readyz":  {Summary: "Readiness check", Response: ReadinessResponse{}},
+	"GET /version": {Summary: "Get build details", Response: VersionResponse{}},
+	"GET /metrics": {Summary: "OpenMetrics exposition", Auth: "admin", Response: binaryBody("text/plain")},
+}
+
+var securitySchemes = map[string]any{
+	"owner":   map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT", "description": "Owner access token"},
+	"session": map[string]any{"type": "http", "scheme": "bearer", "description": "Guest session token"},
+	"admin":   map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+}
+
+type OpenAPIHandler struct {
+	echo    *echo.Echo
+	title   string
+	version string
+
+	once sync.Once
+	spec map[string]any
+}
+
+// NewOpenAPIHandler documents the routes registered on e. The document is
+// built on first request, once every route is in place.
+func NewOpenAPIHandler(e *echo.Echo, title, version string) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		echo:    e,
+		title:   title,
+		version: version,
+	}
+}
+
+// GetSpec returns the OpenAPI 3 document
+func (h *OpenAPIHandler) GetSpec(c echo.Context) error {
+	h.once.Do(func() {
+		h.spec = h.buildSpec()
+	})
+	return c.JSON(http.StatusOK, h.spec)
+}
+
+// GetSwaggerUI serves Swagger UI for the OpenAPI document
+func (h *OpenAPIHandler) GetSwaggerUI(c echo.Context) error {
+	return c.HTML(http.StatusOK, `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>`+h.title+`</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({ url: "/api/docs/openapi.json", dom_id: "#swagger-ui" })</script>
+</body>
+</html>`)
+}
+
+var (
+	routeParam  = regexp.MustCompile(`:([A-Za-z_]+)`)
+	handlerName = regexp.MustCompile(`\.([A-Za-z]+)-fm$`)
+)
+
+func (h *OpenAPIHandler) buildSpec() map[string]any {
+	schemas := map[string]any{}
+	paths := map[string]map[string]any{}
+	operationIDs := map[string]bool{}
+
+	for _, route := range h.echo.Routes() {
+		// Skip wildcard mounts and the documentation itself
+		if strings.Contains(route.Path, "*") || strings.HasPrefix(route.Path, "/api/docs") {
+			continue
+		}
+
+		doc := apiDocs[route.Method+" "+route.Path]
+		path := routeParam.ReplaceAllString(route.Path, "{$1}")
+
+		operation := map[string]any{"tags": []string{routeTag(route.Path)}}
+		if m := handlerName.FindStringSubmatch(route.Name); m != nil {
+			id := m[1]
+			if operationIDs[id] {
+				id += strings.ToUpper(route.Method[:1]) + strings.ToLower(route.Method[1:])
+			}
+			operationIDs[id] = true
+			operation["operationId"] = id
+			operation["summary"] = m[1]
+		}
+		if doc.Summary != "" {
+			operation["summary"] = doc.Summary
+		}
+
+		var parameters []any
+		for _, m := range routeParam.FindAllStringSubmatch(route.Path, -1) {
+			parameters = append(parameters, map[string]any{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		if parameters != nil {
+			operation["parameters"] = parameters
+		}
+
+		if doc.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": valueSchema(doc.Request, schemas)}},
+			}
+		}
+
+		status := doc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := map[string]any{"description": http.StatusText(status)}
+		switch body := doc.Response.(type) {
+		case nil:
+		case binaryBody:
+			response["content"] = map[string]any{string(body): map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}}
+		default:
+			response["content"] = map[string]any{"application/json": map[string]any{"schema": valueSchema(body, schemas)}}
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(status): response,
+			"default": map[string]any{
+				"description": "Problem details (RFC 7807)",
+				"content":     map[string]any{ProblemContentType: map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Problem"}}},
+			},
+		}
+
+		if doc.Auth != "" {
+			operation["security"] = []any{map[string]any{doc.Auth: []string{}}}
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	// Registers the Problem component the default responses refer to
+	typeSchema(reflect.TypeOf(Problem{}), schemas)
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": h.title, "version": h.version},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas":         schemas,
+			"securitySchemes": securitySchemes,
+		},
+	}
+}
+
+// routeTag groups operations by the first path segment under /api/<version>
+func routeTag(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "system"
+	}
+	_, rest, _ = strings.Cut(rest, "/")
+	tag, _, _ := strings.Cut(rest, "/")
+	return tag
+}
+
+// valueSchema describes a sample value; maps become objects with their keys
+func valueSchema(v any, schemas map[string]any) map[string]any {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Map {
+		return typeSchema(value.Type(), schemas)
+	}
+
+	properties := map[string]any{}
+	for _, key := range value.MapKeys() {
+		properties[key.String()] = typeSchema(value.MapIndex(key).Elem().Type(), schemas)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// typeSchema describes a Go type, registering named structs as components
+func typeSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := typeSchema(t.Elem(), schemas)
+		if _, ok := schema["$ref"]; ok {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		if _, ok := schemas[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			schemas[t.Name()] = map[string]any{}
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := structSchema(field.Type, schemas)
+			for k, v := range embedded["properties"].(map[string]any) {
+				properties[k] = v
+			}
+			if r, ok := embedded["required"].([]string); ok {
+				required = append(required, r...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := typeSchema(field.Type, schemas)
+		if applyValidation(schema, field.Tag.Get("validate")) {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyValidation copies validator rules onto a schema and reports whether the
+// field is required
+func applyValidation(schema map[string]any, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// Later rules apply to elements
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema["format"] = "email"
+		case "url", "http_url":
+			schema["format"] = "uri"
+		case "uuid", "uuid4":
+			schema["format"] = "uuid"
+		case "oneof":
+			schema["enum"] = strings.Fields(arg)
+		case "min", "max", "gte", "lte":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			schema[limitKeyword(schema["type"], name)] = n
+		}
+	}
+	return required
+}
+
+// limitKeyword maps min/max rules to the keyword for the schema's type
+func limitKeyword(schemaType any, rule string) string {
+	lower := rule == "min" || rule == "gte"
+	switch schemaType {
+	case "string":
+		if lower {
+			return "minLength"
+		}
+		return "maxLength"
+	case "array":
+		if lower {
+			return "minItems"
+		}
+		return "maxItems"
+	default:
+		if lower {
+			return "minimum"
+		}
+		return "maximum"
+	}
+}
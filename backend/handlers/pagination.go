@@ -0,0 +1,16 @@
+package handlers
+
+import "snapShare/pagination"
+
+// PageQuery holds the paging parameters every list endpoint accepts: ?limit=
+// and ?cursor= (next_cursor of the previous page). Embed it in list queries.
+type PageQuery struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1"`
+	Cursor string `query:"cursor" validate:"omitempty,max=512"`
+}
+
+// PageLimit returns the requested page size, def when none was requested and
+// at most max
+func (q PageQuery) PageLimit(def, max int) int {
+	return pagination.ClampLimit(q.Limit, def, max)
+}
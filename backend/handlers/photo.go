@@ -1,19 +1,25 @@
 package handlers
 
 import (
+	"errors"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
+	"snapShare/infra/storage"
+	"snapShare/models"
 	"snapShare/services"
 )
 
 // Request DTOs
 type UploadURLRequest struct {
-	EventID     string `json:"event_id" validate:"required,uuid"`
-	ContentType string `json:"content_type" validate:"required"`
+	EventID     string  `json:"event_id" validate:"required,uuid"`
+	ContentType string  `json:"content_type" validate:"required"`
+	Caption     *string `json:"caption,omitempty" validate:"omitempty,max=500"`
 }
 
 type BulkUploadRequest struct {
@@ -22,21 +28,43 @@ type BulkUploadRequest struct {
 }
 
 type FileInfo struct {
-	ContentType string `json:"content_type" validate:"required"`
-	Size        int64  `json:"size,omitempty"`
+	ContentType string  `json:"content_type" validate:"required"`
+	Size        int64   `json:"size,omitempty"`
+	Caption     *string `json:"caption,omitempty" validate:"omitempty,max=500"`
 }
 
 type ConfirmUploadRequest struct {
-	FileSize int64 `json:"file_size" validate:"required,min=1"`
+	FileSize int64      `json:"file_size" validate:"required,min=1"`
+	TakenAt  *time.Time `json:"taken_at,omitempty"`
 }
 
+// BulkConfirmRequest maps photo IDs to their uploaded sizes in bytes
 type BulkConfirmRequest struct {
-	Confirmations map[string]int64 `json:"confirmations" validate:"required"`
+	Confirmations map[string]int64 `json:"confirmations" validate:"required,min=1,dive,keys,uuid,endkeys,min=1"`
+}
+
+type GalleryQuery struct {
+	Q     string `query:"q"`
+	Sort  string `query:"sort" validate:"omitempty,oneof=created_at taken_at size uploader"`
+	Order string `query:"order" validate:"omitempty,oneof=asc desc"`
+	PageQuery
+	FieldsQuery
+}
+
+type SlideshowQuery struct {
+	Since    *time.Time `query:"since"`
+	Cursor   string     `query:"cursor"`
+	Shuffle  bool       `query:"shuffle"`
+	Duration int        `query:"duration" validate:"omitempty,min=1,max=600"`
+	Limit    int        `query:"limit" validate:"omitempty,min=1,max=500"`
 }
 
 type DeleteBulkRequest struct {
 	PhotoIDs []string `json:"photo_ids" validate:"required,min=1"`
-	EventID  string   `json:"event_id" validate:"required,uuid"`
+}
+
+type BatchGetPhotosRequest struct {
+	PhotoIDs []string `json:"photo_ids" validate:"required,min=1,max=100,dive,uuid"`
 }
 
 // Response DTOs
@@ -51,19 +79,79 @@ type BulkUploadResponse struct {
 	BatchID string              `json:"batch_id"`
 }
 
-type BulkDownloadResponse struct {
-	DownloadURL string    `json:"download_url"`
-	ExpiresAt   time.Time `json:"expires_at"`
-	PhotoCount  int       `json:"photo_count"`
+type PhotoResponse struct {
+	ID           string     `json:"id"`
+	EventID      string     `json:"event_id"`
+	UploaderName string     `json:"uploader_name"`
+	URL          string     `json:"url,omitempty"`
+	FileSize     int64      `json:"file_size"`
+	MimeType     string     `json:"mime_type"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	Caption      *string    `json:"caption,omitempty"`
+	TakenAt      *time.Time `json:"taken_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	Links        PhotoLinks `json:"links"`
 }
 
+// GalleryPhotoResponse is a photo in a gallery page, with its links
+type GalleryPhotoResponse struct {
+	models.Photo
+	Links PhotoLinks `json:"links"`
+}
+
+// BulkConfirmResponse reports the IDs that are not pending photos of the
+// session's event; they were not confirmed
+type BulkConfirmResponse struct {
+	Message  string   `json:"message"`
+	NotFound []string `json:"not_found"`
+}
+
+// BatchGetPhotosResponse lists the requested photos in request order.
+// NotFound holds the IDs that do not exist or are not visible to the session.
+type BatchGetPhotosResponse struct {
+	Data     []PhotoResponse `json:"data"`
+	NotFound []string        `json:"not_found"`
+}
+
+type SlideshowItemResponse struct {
+	PhotoID         string    `json:"photo_id"`
+	URL             string    `json:"url,omitempty"`
+	DurationSeconds int       `json:"duration_seconds"`
+	UploaderName    string    `json:"uploader_name"`
+	Caption         *string   `json:"caption,omitempty"`
+	Width           *int      `json:"width,omitempty"`
+	Height          *int      `json:"height,omitempty"`
+	ConfirmedAt     time.Time `json:"confirmed_at"`
+}
+
+// SlideshowResponse is one batch of a slideshow playlist. Clients poll again
+// with cursor=next_cursor to receive only photos added afterwards.
+type SlideshowResponse struct {
+	Items      []SlideshowItemResponse `json:"items"`
+	Count      int                     `json:"count"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+const (
+	defaultSlideDuration  = 8
+	defaultSlideshowLimit = 200
+
+	defaultGalleryLimit = 100
+	maxGalleryLimit     = 500
+)
+
 type PhotoHandler struct {
 	photoService *services.PhotoService
+	eventService *services.EventService
+	auditService *services.AuditService
 }
 
-func NewPhotoHandler(photoService *services.PhotoService) *PhotoHandler {
+func NewPhotoHandler(photoService *services.PhotoService, eventService *services.EventService, auditService *services.AuditService) *PhotoHandler {
 	return &PhotoHandler{
 		photoService: photoService,
+		eventService: eventService,
+		auditService: auditService,
 	}
 }
 
@@ -71,11 +159,11 @@ func NewPhotoHandler(photoService *services.PhotoService) *PhotoHandler {
 func (h *PhotoHandler) GenerateUploadURL(c echo.Context) error {
 	var req UploadURLRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	eventID, err := uuid.Parse(req.EventID)
@@ -89,9 +177,14 @@ func (h *PhotoHandler) GenerateUploadURL(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "uploader name required")
 	}
 
-	uploadInfo, err := h.photoService.GenerateUploadURL(c.Request().Context(), eventID, uploaderName.(string), req.ContentType)
+	event, err := h.uploadEvent(c, eventID)
+	if err != nil {
+		return err
+	}
+
+	uploadInfo, err := h.photoService.GenerateUploadURL(c.Request().Context(), event, uploaderName.(string), sessionGuestID(c), req.ContentType, req.Caption)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return photoUploadError(err)
 	}
 
 	response := UploadURLResponse{
@@ -107,11 +200,11 @@ func (h *PhotoHandler) GenerateUploadURL(c echo.Context) error {
 func (h *PhotoHandler) GenerateBulkUploadURLs(c echo.Context) error {
 	var req BulkUploadRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	eventID, err := uuid.Parse(req.EventID)
@@ -124,18 +217,24 @@ func (h *PhotoHandler) GenerateBulkUploadURLs(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "uploader name required")
 	}
 
+	event, err := h.uploadEvent(c, eventID)
+	if err != nil {
+		return err
+	}
+
 	// Convert DTOs to service layer types
 	files := make([]services.FileSpec, len(req.Files))
 	for i, file := range req.Files {
 		files[i] = services.FileSpec{
 			ContentType: file.ContentType,
 			Size:        file.Size,
+			Caption:     file.Caption,
 		}
 	}
 
-	result, err := h.photoService.GenerateBulkUploadURLs(c.Request().Context(), eventID, uploaderName.(string), files)
+	result, err := h.photoService.GenerateBulkUploadURLs(c.Request().Context(), event, uploaderName.(string), sessionGuestID(c), files)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return photoUploadError(err)
 	}
 
 	// Convert service layer response to DTO
@@ -166,71 +265,350 @@ func (h *PhotoHandler) ConfirmUpload(c echo.Context) error {
 
 	var req ConfirmUploadRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
-	if err := h.photoService.ConfirmUpload(c.Request().Context(), photoID, req.FileSize); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	session := c.Get("session").(*models.Session)
+	err = h.photoService.ConfirmUpload(c.Request().Context(), session.EventID, photoID, req.FileSize, req.TakenAt)
+	if errors.Is(err, services.ErrPhotoNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "upload confirmed"})
 }
 
+// GetPhoto returns the full metadata of a single photo
+func (h *PhotoHandler) GetPhoto(c echo.Context) error {
+	photoIDStr := c.Param("id")
+	photoID, err := uuid.Parse(photoIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
+	}
+
+	photo, err := h.photoService.GetPhotoByID(c.Request().Context(), photoID)
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	// Sessions may only read visible photos of their own event; moderators also see hidden ones
+	session, isGuest := c.Get("session").(*models.Session)
+	moderator := isGuest && services.HasScope(session, models.SessionScopeModerate)
+	if isGuest && (session.EventID != photo.EventID || (photo.HiddenAt != nil && !moderator)) {
+		return echo.NewHTTPError(http.StatusNotFound, "photo not found")
+	}
+
+	// Guests of a private gallery only see their own uploads
+	if isGuest && !moderator && !services.IsOwnUpload(session, photo) {
+		visibility, err := h.photoService.GetGalleryVisibility(c.Request().Context(), photo.EventID)
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+		if visibility == models.GalleryVisibilityPrivate {
+			return echo.NewHTTPError(http.StatusNotFound, "photo not found")
+		}
+	}
+
+	canDownload, err := callerCanDownload(c, h.photoService, photo.EventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, h.toPhotoResponse(photo, canDownload))
+}
+
+// BatchGetPhotos returns up to 100 photos of the session's event in one
+// call, for clients reconciling an offline upload queue. The same
+// visibility rules as GetPhoto apply.
+func (h *PhotoHandler) BatchGetPhotos(c echo.Context) error {
+	var req BatchGetPhotosRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+	}
+
+	photoIDs := make([]uuid.UUID, len(req.PhotoIDs))
+	for i, idStr := range req.PhotoIDs {
+		photoIDs[i] = uuid.MustParse(idStr) // validated as UUIDs
+	}
+
+	photos, err := h.photoService.GetPhotosByIDs(c.Request().Context(), session.EventID, photoIDs)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	moderator := services.HasScope(session, models.SessionScopeModerate)
+	private := false
+	if !moderator {
+		visibility, err := h.photoService.GetGalleryVisibility(c.Request().Context(), session.EventID)
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+		private = visibility == models.GalleryVisibilityPrivate
+	}
+
+	canDownload, err := callerCanDownload(c, h.photoService, session.EventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	byID := make(map[uuid.UUID]*models.Photo, len(photos))
+	for i := range photos {
+		photo := &photos[i]
+		if !moderator && (photo.HiddenAt != nil || (private && !services.IsOwnUpload(session, photo))) {
+			continue
+		}
+		byID[photo.ID] = photo
+	}
+
+	response := BatchGetPhotosResponse{Data: []PhotoResponse{}, NotFound: []string{}}
+	seen := make(map[uuid.UUID]bool, len(photoIDs))
+	for _, photoID := range photoIDs {
+		if seen[photoID] {
+			continue
+		}
+		seen[photoID] = true
+
+		if photo, ok := byID[photoID]; ok {
+			response.Data = append(response.Data, h.toPhotoResponse(photo, canDownload))
+		} else {
+			response.NotFound = append(response.NotFound, photoID.String())
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// toPhotoResponse converts a photo, with its URL when it may be downloaded
+func (h *PhotoHandler) toPhotoResponse(photo *models.Photo, canDownload bool) PhotoResponse {
+	response := PhotoResponse{
+		ID:           photo.ID.String(),
+		EventID:      photo.EventID.String(),
+		UploaderName: photo.UploaderName,
+		FileSize:     photo.Size,
+		MimeType:     photo.MimeType,
+		Width:        photo.Width,
+		Height:       photo.Height,
+		Caption:      photo.Caption,
+		TakenAt:      photo.TakenAt,
+		CreatedAt:    photo.CreatedAt,
+	}
+	if canDownload {
+		response.URL = h.photoService.PublicURL(photo.ObjectKey)
+	}
+	response.Links = photoLinks(photo, response.URL, false)
+	return response
+}
+
 // ConfirmBulkUpload confirms multiple photo uploads
 func (h *PhotoHandler) ConfirmBulkUpload(c echo.Context) error {
 	var req BulkConfirmRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
-	if err := h.photoService.ConfirmBulkUpload(c.Request().Context(), req.Confirmations); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	session := c.Get("session").(*models.Session)
+	notFound, err := h.photoService.ConfirmBulkUpload(c.Request().Context(), session.EventID, req.Confirmations)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if notFound == nil {
+		notFound = []string{}
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "bulk upload confirmed"})
+	return c.JSON(http.StatusOK, BulkConfirmResponse{Message: "bulk upload confirmed", NotFound: notFound})
 }
 
-// GetPhotosByEvent retrieves all photos for an event
+// GetPhotosByEvent retrieves a page of photos for an event.
+// Supports ?q= search, ?sort=created_at|taken_at|size|uploader&order=asc|desc,
+// ?limit=, ?cursor= (next_cursor of the previous page) and ?fields=.
+// Guests get the gallery of their session's event, owners the :id event.
 func (h *PhotoHandler) GetPhotosByEvent(c echo.Context) error {
-	eventIDStr := c.Param("event_id")
+	var eventID uuid.UUID
+	session, guest := c.Get("session").(*models.Session)
+	if guest {
+		eventID = session.EventID
+	} else {
+		var err error
+		if eventID, err = uuid.Parse(c.Param("id")); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+		}
+	}
+
+	var query GalleryQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	fields, err := query.FieldNames(GalleryPhotoResponse{})
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := checkGalleryVisible(c, h.photoService, eventID); err != nil {
+		return err
+	}
+
+	canDownload, err := callerCanDownload(c, h.photoService, eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	// Galleries are polled, so let clients revalidate cheaply
+	version, err := h.photoService.GalleryVersion(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if notModified(c, version, strconv.FormatBool(canDownload)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	filter := services.PhotoFilter{
+		Query:  query.Q,
+		Sort:   query.Sort,
+		Order:  query.Order,
+		Limit:  query.PageLimit(defaultGalleryLimit, maxGalleryLimit),
+		Cursor: query.Cursor,
+	}
+
+	page, err := h.photoService.GetPhotosByEvent(c.Request().Context(), eventID, filter)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	photos := make([]GalleryPhotoResponse, len(page.Photos))
+	for i := range page.Photos {
+		photo := &page.Photos[i]
+		// Gallery items carry the public URL as their object key; guests get
+		// a view-only gallery without object URLs
+		var url string
+		if canDownload {
+			url = h.photoService.PublicURL(photo.ObjectKey)
+		}
+		photos[i] = GalleryPhotoResponse{Photo: *photo, Links: photoLinks(photo, url, !guest)}
+		photos[i].ObjectKey = url
+	}
+
+	return listJSON(c, newListResponse(c, photos, page.NextCursor), fields)
+}
+
+// GetInFlightUploads returns how many photos are currently being uploaded to an event
+func (h *PhotoHandler) GetInFlightUploads(c echo.Context) error {
+	eventIDStr := c.Param("id")
 	eventID, err := uuid.Parse(eventIDStr)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
 
-	photos, err := h.photoService.GetPhotosByEvent(c.Request().Context(), eventID)
+	count, err := h.photoService.CountInFlightUploads(c.Request().Context(), eventID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
-	return c.JSON(http.StatusOK, map[string]any{"photos": photos})
+	return c.JSON(http.StatusOK, map[string]int64{"uploading": count})
 }
 
-// GenerateBulkDownloadURL creates a download URL for all photos in an event
-func (h *PhotoHandler) GenerateBulkDownloadURL(c echo.Context) error {
-	eventIDStr := c.Param("event_id")
+// GetSlideshow returns a playlist of display URLs for a big-screen slideshow.
+// Supports ?cursor= (the previous next_cursor) for incremental fetching,
+// ?since= (RFC 3339) to start after a time, ?shuffle=true,
+// ?duration= (seconds per photo) and ?limit=
+func (h *PhotoHandler) GetSlideshow(c echo.Context) error {
+	eventIDStr := c.Param("id")
 	eventID, err := uuid.Parse(eventIDStr)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
 
-	downloadInfo, err := h.photoService.GenerateBulkDownloadURL(c.Request().Context(), eventID)
+	var query SlideshowQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if query.Duration == 0 {
+		query.Duration = defaultSlideDuration
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultSlideshowLimit
+	}
+
+	if err := checkGalleryVisible(c, h.photoService, eventID); err != nil {
+		return err
+	}
+
+	canDownload, err := callerCanDownload(c, h.photoService, eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	version, err := h.photoService.GalleryVersion(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if notModified(c, version, strconv.FormatBool(canDownload)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	page, err := h.photoService.GetSlideshowPhotos(c.Request().Context(), eventID, query.Since, query.Cursor, query.Limit)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		return fail(http.StatusBadRequest, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
-	response := BulkDownloadResponse{
-		DownloadURL: downloadInfo.DownloadURL,
-		ExpiresAt:   downloadInfo.ExpiresAt,
-		PhotoCount:  downloadInfo.PhotoCount,
+	// The cursor follows confirmation order, whether or not items are shuffled
+	response := SlideshowResponse{
+		Items:      make([]SlideshowItemResponse, len(page.Photos)),
+		Count:      len(page.Photos),
+		NextCursor: page.NextCursor,
+	}
+	for i, photo := range page.Photos {
+		// Like the gallery, guests without downloads get no object URLs
+		var url string
+		if canDownload {
+			url = h.photoService.PublicURL(photo.ObjectKey)
+		}
+		response.Items[i] = SlideshowItemResponse{
+			PhotoID:         photo.ID.String(),
+			URL:             url,
+			DurationSeconds: query.Duration,
+			UploaderName:    photo.UploaderName,
+			Caption:         photo.Caption,
+			Width:           photo.Width,
+			Height:          photo.Height,
+			ConfirmedAt:     *photo.ConfirmedAt,
+		}
+	}
+
+	if query.Shuffle {
+		rand.Shuffle(len(response.Items), func(i, j int) {
+			response.Items[i], response.Items[j] = response.Items[j], response.Items[i]
+		})
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -244,28 +622,48 @@ func (h *PhotoHandler) DeletePhoto(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
 	}
 
-	// Check if user can delete (implement proper authorization)
-	userCanDelete := true // TODO: Implement proper authorization logic
+	photo, err := h.photoService.GetPhotoByID(c.Request().Context(), photoID)
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	// Guests may delete their own uploads; moderators any photo of their event
+	session := c.Get("session").(*models.Session)
+	if session.EventID != photo.EventID {
+		return echo.NewHTTPError(http.StatusNotFound, "photo not found")
+	}
+	userCanDelete := services.IsOwnUpload(session, photo) || services.HasScope(session, models.SessionScopeModerate)
+	if !userCanDelete {
+		return echo.NewHTTPError(http.StatusForbidden, "you can only delete your own photos")
+	}
 
 	if err := h.photoService.DeletePhoto(c.Request().Context(), photoID, userCanDelete); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &photo.EventID,
+		Action:     "photo.delete",
+		TargetType: "photo",
+		TargetID:   photo.ID.String(),
+		Details:    map[string]any{"uploader_name": photo.UploaderName},
+	})
+
 	return c.JSON(http.StatusOK, map[string]string{"message": "photo deleted"})
 }
 
-// DeleteBulkPhotos deletes multiple photos
+// DeleteBulkPhotos deletes multiple photos of the :id event
 func (h *PhotoHandler) DeleteBulkPhotos(c echo.Context) error {
 	var req DeleteBulkRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
-	eventID, err := uuid.Parse(req.EventID)
+	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
@@ -281,8 +679,93 @@ func (h *PhotoHandler) DeleteBulkPhotos(c echo.Context) error {
 	}
 
 	if err := h.photoService.DeleteBulkPhotos(c.Request().Context(), photoIDs, eventID); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "photo.delete_bulk",
+		TargetType: "event",
+		TargetID:   eventID.String(),
+		Details:    map[string]any{"photo_ids": req.PhotoIDs},
+	})
+
 	return c.JSON(http.StatusOK, map[string]any{"message": "photos deleted", "count": len(photoIDs)})
 }
+
+// photoUploadError maps quota violations to 429/422, disallowed media types
+// to 415 and anything else to 500
+func photoUploadError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrPhotoLimitReached):
+		return fail(http.StatusTooManyRequests, err)
+	case errors.Is(err, services.ErrPhotoQuotaExceeded):
+		return fail(http.StatusUnprocessableEntity, err)
+	case errors.Is(err, services.ErrMediaTypeNotAllowed):
+		return fail(http.StatusUnsupportedMediaType, err)
+	case errors.Is(err, storage.ErrUnavailable):
+		return fail(http.StatusServiceUnavailable, err)
+	default:
+		return fail(http.StatusInternalServerError, err)
+	}
+}
+
+// uploadEvent returns the event a session uploads to. AuthMiddleware already
+// loaded the session's event, so only requests naming another event hit the
+// database, to tell a missing event from one the session cannot upload to.
+func (h *PhotoHandler) uploadEvent(c echo.Context, eventID uuid.UUID) (*models.Event, error) {
+	session, ok := c.Get("session").(*models.Session)
+	if ok && session.EventID == eventID {
+		return &session.Event, nil
+	}
+
+	active, err := h.eventService.ExistsActive(c.Request().Context(), eventID)
+	if err != nil {
+		return nil, fail(http.StatusInternalServerError, err)
+	}
+	if !active {
+		return nil, fail(http.StatusNotFound, services.ErrEventNotFound)
+	}
+	return nil, echo.NewHTTPError(http.StatusForbidden, "you do not have access to this event")
+}
+
+// sessionGuestID returns the guest of the calling session, if any
+func sessionGuestID(c echo.Context) *uuid.UUID {
+	if session, ok := c.Get("session").(*models.Session); ok {
+		return session.GuestID
+	}
+	return nil
+}
+
+// callerCanDownload reports whether the caller may fetch original files of an event.
+// Guest sessions need the download scope and are subject to the event's
+// download setting.
+func callerCanDownload(c echo.Context, photoService *services.PhotoService, eventID uuid.UUID) (bool, error) {
+	session, ok := c.Get("session").(*models.Session)
+	if !ok {
+		return true, nil
+	}
+	if !services.HasScope(session, models.SessionScopeDownload) {
+		return false, nil
+	}
+
+	return photoService.GuestDownloadsAllowed(c.Request().Context(), eventID)
+}
+
+// checkGalleryVisible refuses guests the gallery of an event whose gallery is
+// private; owners and co-hosts always see it
+func checkGalleryVisible(c echo.Context, photoService *services.PhotoService, eventID uuid.UUID) error {
+	if _, guest := c.Get("session").(*models.Session); !guest {
+		return nil
+	}
+
+	visibility, err := photoService.GetGalleryVisibility(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if visibility == models.GalleryVisibilityPrivate {
+		return fail(http.StatusForbidden, services.ErrGalleryPrivate)
+	}
+
+	return nil
+}
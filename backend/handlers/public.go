@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/infra/storage"
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Response DTOs
+type PublicPhotoResponse struct {
+	ID           string     `json:"id"`
+	UploaderName string     `json:"uploader_name"`
+	ImageURL     string     `json:"image_url"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	Caption      *string    `json:"caption,omitempty"`
+	TakenAt      *time.Time `json:"taken_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type PublicGalleryResponse struct {
+	EventName string                `json:"event_name"`
+	EventDate *time.Time            `json:"event_date,omitempty"`
+	Photos    []PublicPhotoResponse `json:"photos"`
+	Count     int                   `json:"count"`
+}
+
+// publicGalleryHead holds the PublicGalleryResponse fields written before
+// the photos are streamed
+type publicGalleryHead struct {
+	EventName string     `json:"event_name"`
+	EventDate *time.Time `json:"event_date,omitempty"`
+}
+
+// mediaBusyRetryAfter is the Retry-After, in seconds, sent when the media
+// pool turns an image request away
+const mediaBusyRetryAfter = "2"
+
+type PublicHandler struct {
+	photoService *services.PhotoService
+}
+
+func NewPublicHandler(photoService *services.PhotoService) *PublicHandler {
+	return &PublicHandler{
+		photoService: photoService,
+	}
+}
+
+// GetPublicGallery lists the photos of an event whose gallery is public.
+// Image URLs point at watermarked renditions, never at the originals.
+func (h *PublicHandler) GetPublicGallery(c echo.Context) error {
+	code := c.Param("code")
+
+	// Visitors refresh the gallery often; skip loading photos when unchanged
+	modifiedAt, err := h.photoService.GetPublicGalleryModifiedAt(c.Request().Context(), code)
+	if errors.Is(err, services.ErrGalleryNotPublic) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	if notModifiedSince(c, modifiedAt) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	event, err := h.photoService.GetPublicGallery(c.Request().Context(), code)
+	if errors.Is(err, services.ErrGalleryNotPublic) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	// Galleries can hold thousands of photos, so the PublicGalleryResponse is
+	// encoded photo by photo as they are read instead of being built in full.
+	// The count goes last since it is only known at the end.
+	head, err := json.Marshal(publicGalleryHead{EventName: event.Name, EventDate: event.EventDate})
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+	// The head object stays open for the photos and the count
+	head = bytes.TrimSuffix(head, []byte("}"))
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	res.WriteHeader(http.StatusOK)
+	if _, err := res.Write(head); err != nil {
+		return err
+	}
+	if _, err := res.Write([]byte(`,"photos":[`)); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(res)
+	count := 0
+	err = h.photoService.EachPublicPhoto(c.Request().Context(), event.ID, func(photo *models.Photo) error {
+		if count > 0 {
+			if _, err := res.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		count++
+		return enc.Encode(PublicPhotoResponse{
+			ID:           photo.ID.String(),
+			UploaderName: photo.UploaderName,
+			ImageURL:     apiPath("/public/events/%s/photos/%s/image", event.Code, photo.ID),
+			Width:        photo.Width,
+			Height:       photo.Height,
+			Caption:      photo.Caption,
+			TakenAt:      photo.TakenAt,
+			CreatedAt:    photo.CreatedAt,
+		})
+	})
+	if err != nil {
+		// The status is already sent; cutting the body short leaves the
+		// client with invalid JSON rather than a silently partial gallery
+		return err
+	}
+
+	_, err = fmt.Fprintf(res, `],"count":%d}`, count)
+	return err
+}
+
+// GetPublicPhotoImage serves a downscaled, watermarked JPEG of a public gallery photo
+func (h *PublicHandler) GetPublicPhotoImage(c echo.Context) error {
+	code := c.Param("code")
+
+	photoIDStr := c.Param("photo_id")
+	photoID, err := uuid.Parse(photoIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid photo ID")
+	}
+
+	image, err := h.photoService.GetWatermarkedPhoto(c.Request().Context(), code, photoID)
+	switch {
+	case errors.Is(err, services.ErrImageNotRenderable):
+		return fail(http.StatusUnsupportedMediaType, err)
+	case errors.Is(err, services.ErrImageTooLarge):
+		return fail(http.StatusUnprocessableEntity, err)
+	case errors.Is(err, services.ErrMediaBusy):
+		c.Response().Header().Set("Retry-After", mediaBusyRetryAfter)
+		return fail(http.StatusServiceUnavailable, err)
+	case errors.Is(err, storage.ErrUnavailable):
+		return fail(http.StatusServiceUnavailable, err)
+	}
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=3600")
+	return c.Blob(http.StatusOK, "image/jpeg", image)
+}
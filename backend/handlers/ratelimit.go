@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+
+	"snapShare/models"
+)
+
+// RateLimit allows Burst requests at once, then PerMinute requests a minute.
+// A zero PerMinute disables the limit.
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+// GlobalRateLimiter limits requests from all clients together
+func GlobalRateLimiter(limit RateLimit) echo.MiddlewareFunc {
+	return newRateLimiter(limit, nil, func(c echo.Context) (string, error) {
+		return "global", nil
+	})
+}
+
+// IPRateLimiter limits requests per client IP
+func IPRateLimiter(limit RateLimit) echo.MiddlewareFunc {
+	return newRateLimiter(limit, nil, func(c echo.Context) (string, error) {
+		return c.RealIP(), nil
+	})
+}
+
+// TokenRateLimiter limits requests per bearer token, owner or guest alike.
+// Requests without a token are not limited here.
+func TokenRateLimiter(limit RateLimit) echo.MiddlewareFunc {
+	skipper := func(c echo.Context) bool {
+		return c.Request().Header.Get(echo.HeaderAuthorization) == ""
+	}
+	return newRateLimiter(limit, skipper, func(c echo.Context) (string, error) {
+		// Tokens are hashed so the limiter store never holds credentials
+		sum := sha256.Sum256([]byte(c.Request().Header.Get(echo.HeaderAuthorization)))
+		return hex.EncodeToString(sum[:16]), nil
+	})
+}
+
+// SessionRateLimiter limits requests per guest session; it must run after
+// SessionHandler.AuthMiddleware
+func SessionRateLimiter(limit RateLimit) echo.MiddlewareFunc {
+	return newRateLimiter(limit, nil, func(c echo.Context) (string, error) {
+		session, ok := c.Get("session").(*models.Session)
+		if !ok {
+			return "", echo.NewHTTPError(http.StatusUnauthorized, "session required")
+		}
+		return session.ID.String(), nil
+	})
+}
+
+// RouteRateLimiter limits single routes per client IP. Routes are keyed by
+// method and path as registered, e.g. "GET /api/v1/events/:code"; routes
+// without a limit pass through.
+func RouteRateLimiter(routes map[string]RateLimit) echo.MiddlewareFunc {
+	limiters := make(map[string]echo.MiddlewareFunc, len(routes))
+	for route, limit := range routes {
+		limiters[route] = IPRateLimiter(limit)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		limited := make(map[string]echo.HandlerFunc, len(limiters))
+		for route, limiter := range limiters {
+			limited[route] = limiter(next)
+		}
+
+		return func(c echo.Context) error {
+			if handler, ok := limited[c.Request().Method+" "+c.Path()]; ok {
+				return handler(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+func newRateLimiter(limit RateLimit, skipper middleware.Skipper, identify middleware.Extractor) echo.MiddlewareFunc {
+	if limit.PerMinute <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	// A denied client may retry once a token has been refilled
+	retryAfter := strconv.Itoa(int(math.Ceil(60 / float64(limit.PerMinute))))
+
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: skipper,
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:  rate.Limit(float64(limit.PerMinute) / 60),
+			Burst: burst,
+		}),
+		IdentifierExtractor: identify,
+		ErrorHandler: func(c echo.Context, err error) error {
+			return err
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			c.Response().Header().Set("Retry-After", retryAfter)
+			return middleware.ErrRateLimitExceeded
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListResponse is the body of every list endpoint: the items under data,
+// paging details under meta and navigation URLs under links
+type ListResponse struct {
+	Data  any       `json:"data"`
+	Meta  ListMeta  `json:"meta"`
+	Links ListLinks `json:"links"`
+}
+
+// ListMeta describes a page of a listing. Total is only set by listings that
+// count every match; NextCursor is empty on the last page.
+type ListMeta struct {
+	Count      int    `json:"count"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListLinks holds the URL of the current page and, unless it is the last,
+// of the next one
+type ListLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+// newListResponse wraps the slice data in the list envelope of the request.
+// A nil slice is written as an empty list.
+func newListResponse(c echo.Context, data any, nextCursor string) ListResponse {
+	items := reflect.ValueOf(data)
+	if items.IsNil() {
+		data = reflect.MakeSlice(items.Type(), 0, 0).Interface()
+	}
+
+	return ListResponse{
+		Data:  data,
+		Meta:  ListMeta{Count: items.Len(), NextCursor: nextCursor},
+		Links: listLinks(c, nextCursor),
+	}
+}
+
+// listResponse writes the slice data as a list response
+func listResponse(c echo.Context, data any, nextCursor string) error {
+	return c.JSON(http.StatusOK, newListResponse(c, data, nextCursor))
+}
+
+// listLinks points next at the request URL with its cursor replaced
+func listLinks(c echo.Context, nextCursor string) ListLinks {
+	url := *c.Request().URL
+	links := ListLinks{Self: url.RequestURI()}
+	if nextCursor == "" {
+		return links
+	}
+
+	query := url.Query()
+	query.Set("cursor", nextCursor)
+	url.RawQuery = query.Encode()
+	links.Next = url.RequestURI()
+
+	return links
+}
+
+// listBody documents a list response of the items in the slice data
+func listBody(data any) map[string]any {
+	return map[string]any{"data": data, "meta": ListMeta{}, "links": ListLinks{}}
+}
@@ -1,19 +1,38 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
+	"snapShare/infra/captcha"
+	"snapShare/models"
 	"snapShare/services"
+	"snapShare/utils"
 )
 
 // Request DTOs
 type CreateSessionRequest struct {
-	EventCode string `json:"event_code" validate:"required,len=8"`
+	EventCode string `json:"event_code" validate:"required,min=4,max=32"`
 	GuestName string `json:"guest_name" validate:"required,min=1,max=100"`
+	DeviceID  string `json:"device_id,omitempty" validate:"omitempty,max=128"`
+	// CaptchaToken is the Turnstile response, required when the event has require_captcha
+	CaptchaToken string `json:"captcha_token,omitempty" validate:"omitempty,max=2048"`
+	// ConsentVersion is the consent_version of the event's terms the guest accepted
+	ConsentVersion *int `json:"consent_version,omitempty"`
+	// LinkToken is a session token of the guest on another device; the new
+	// session joins that guest instead of creating one
+	LinkToken string `json:"link_token,omitempty" validate:"omitempty,max=128"`
+}
+
+// IssueSessionRequest lets an owner hand out a token with limited scopes
+type IssueSessionRequest struct {
+	GuestName string   `json:"guest_name" validate:"required,min=1,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=upload view download moderate"`
 }
 
 type RefreshSessionRequest struct {
@@ -30,25 +49,62 @@ type SessionResponse struct {
 	EventID      string    `json:"event_id"`
 	GuestName    string    `json:"guest_name"`
 	SessionToken string    `json:"session_token"`
+	Scopes       []string  `json:"scopes"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
 	Event        *EventResponse `json:"event,omitempty"`
 }
 
-type SessionsListResponse struct {
-	Sessions []SessionResponse `json:"sessions"`
-	Count    int              `json:"count"`
+type SessionListQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=active expired all"`
+	PageQuery
+}
+
+// SessionSummaryResponse is the owner-facing view of a session; the raw
+// token is replaced by a fingerprint
+type SessionSummaryResponse struct {
+	ID               string    `json:"id"`
+	EventID          string    `json:"event_id"`
+	GuestName        string    `json:"guest_name"`
+	TokenFingerprint string    `json:"token_fingerprint"`
+	Scopes           []string  `json:"scopes"`
+	Active           bool      `json:"active"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// DeviceID is a short hash of the client's device ID; sessions sharing it
+	// came from the same device
+	DeviceID  string `json:"device_id,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+type GuestSummaryResponse struct {
+	GuestName      string    `json:"guest_name"`
+	SessionCount   int64     `json:"session_count"`
+	ActiveSessions int64     `json:"active_sessions"`
+	FirstJoinedAt  time.Time `json:"first_joined_at"`
+	LastExpiresAt  time.Time `json:"last_expires_at"`
 }
 
+const (
+	defaultSessionListLimit = 50
+	maxSessionListLimit     = 200
+)
+
 type SessionHandler struct {
-	sessionService *services.SessionService
-	eventService   *services.EventService
+	sessionService  *services.SessionService
+	eventService    *services.EventService
+	auditService    *services.AuditService
+	captchaVerifier captcha.Verifier
 }
 
-func NewSessionHandler(sessionService *services.SessionService, eventService *services.EventService) *SessionHandler {
+func NewSessionHandler(sessionService *services.SessionService, eventService *services.EventService, auditService *services.AuditService, captchaVerifier captcha.Verifier) *SessionHandler {
 	return &SessionHandler{
-		sessionService: sessionService,
-		eventService:   eventService,
+		sessionService:  sessionService,
+		eventService:    eventService,
+		auditService:    auditService,
+		captchaVerifier: captchaVerifier,
 	}
 }
 
@@ -56,31 +112,92 @@ func NewSessionHandler(sessionService *services.SessionService, eventService *se
 func (h *SessionHandler) CreateSession(c echo.Context) error {
 	var req CreateSessionRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	// Get the event by code to validate it exists and is active
 	event, err := h.eventService.GetEventByCode(c.Request().Context(), req.EventCode)
+	if errors.Is(err, services.ErrEventNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, "event not found or inactive: "+err.Error())
+		return fail(http.StatusInternalServerError, err)
+	}
+	if event.Status != models.EventStatusActive {
+		return fail(http.StatusForbidden, services.ErrEventNotActive)
+	}
+
+	if event.RequireCaptcha {
+		err := h.captchaVerifier.Verify(c.Request().Context(), req.CaptchaToken, c.RealIP())
+		if errors.Is(err, captcha.ErrVerificationFailed) {
+			return fail(http.StatusForbidden, err)
+		}
+		if err != nil {
+			return fail(http.StatusBadGateway, err)
+		}
 	}
 
 	eventID := event.ID
 
-	session, err := h.sessionService.CreateSession(c.Request().Context(), eventID, req.GuestName)
+	client := services.SessionClient{
+		UserAgent:      c.Request().UserAgent(),
+		IPAddress:      c.RealIP(),
+		ConsentVersion: req.ConsentVersion,
+	}
+	if req.DeviceID != "" {
+		fp := services.ClientFingerprint(req.DeviceID)
+		client.Fingerprint = &fp
+	}
+
+	guestName := req.GuestName
+	if req.LinkToken != "" {
+		linked, err := h.sessionService.ValidateSession(c.Request().Context(), req.LinkToken)
+		if errors.Is(err, services.ErrSessionExpired) {
+			return fail(http.StatusUnauthorized, err)
+		}
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+		if linked.EventID != eventID || linked.GuestID == nil {
+			return fail(http.StatusForbidden, services.ErrSessionNotFound)
+		}
+		client.GuestID = linked.GuestID
+		guestName = linked.GuestName
+	}
+
+	session, err := h.sessionService.CreateSession(c.Request().Context(), eventID, guestName, client)
+	if errors.Is(err, services.ErrGuestBanned) || errors.Is(err, services.ErrEventNotActive) {
+		return fail(http.StatusForbidden, err)
+	}
+	if errors.Is(err, services.ErrConsentRequired) {
+		return fail(http.StatusUnprocessableEntity, err)
+	}
+	if errors.Is(err, services.ErrGuestLimitReached) {
+		return fail(http.StatusTooManyRequests, err)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &session.EventID,
+		Actor:      guestActor(c, session),
+		Action:     "session.create",
+		TargetType: "session",
+		TargetID:   session.ID.String(),
+		Details:    map[string]any{"user_agent": session.UserAgent},
+	})
+
 	response := SessionResponse{
 		ID:           session.ID.String(),
 		EventID:      session.EventID.String(),
 		GuestName:    session.GuestName,
 		SessionToken: session.SessionToken,
+		Scopes:       strings.Split(session.Scopes, ","),
 		ExpiresAt:    session.ExpiresAt,
 		CreatedAt:    session.CreatedAt,
 	}
@@ -97,12 +214,63 @@ func (h *SessionHandler) CreateSession(c echo.Context) error {
 			OwnerEmail:  session.Event.OwnerEmail,
 			CreatedAt:   session.Event.CreatedAt,
 			UpdatedAt:   session.Event.UpdatedAt,
+			Links:       eventLinks(&session.Event, false),
 		}
 	}
 
 	return c.JSON(http.StatusCreated, response)
 }
 
+// IssueSession lets the owner create a session with limited scopes, such as a
+// view-only link or an upload-only photo booth token
+func (h *SessionHandler) IssueSession(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req IssueSessionRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	session, err := h.sessionService.IssueSession(c.Request().Context(), eventID, req.GuestName, req.Scopes)
+	if errors.Is(err, services.ErrInvalidScope) {
+		return fail(http.StatusBadRequest, err)
+	}
+	if errors.Is(err, services.ErrGuestBanned) || errors.Is(err, services.ErrEventNotActive) {
+		return fail(http.StatusForbidden, err)
+	}
+	if errors.Is(err, services.ErrGuestLimitReached) {
+		return fail(http.StatusTooManyRequests, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &session.EventID,
+		Action:     "session.issue",
+		TargetType: "session",
+		TargetID:   session.ID.String(),
+		Details:    map[string]any{"guest_name": session.GuestName, "scopes": session.Scopes},
+	})
+
+	return c.JSON(http.StatusCreated, SessionResponse{
+		ID:           session.ID.String(),
+		EventID:      session.EventID.String(),
+		GuestName:    session.GuestName,
+		SessionToken: session.SessionToken,
+		Scopes:       strings.Split(session.Scopes, ","),
+		ExpiresAt:    session.ExpiresAt,
+		CreatedAt:    session.CreatedAt,
+	})
+}
+
 // ValidateSession validates a session token and returns session info
 func (h *SessionHandler) ValidateSession(c echo.Context) error {
 	token := c.Param("token")
@@ -112,7 +280,7 @@ func (h *SessionHandler) ValidateSession(c echo.Context) error {
 
 	session, err := h.sessionService.ValidateSession(c.Request().Context(), token)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return sessionError(err)
 	}
 
 	response := SessionResponse{
@@ -120,6 +288,7 @@ func (h *SessionHandler) ValidateSession(c echo.Context) error {
 		EventID:      session.EventID.String(),
 		GuestName:    session.GuestName,
 		SessionToken: session.SessionToken,
+		Scopes:       strings.Split(session.Scopes, ","),
 		ExpiresAt:    session.ExpiresAt,
 		CreatedAt:    session.CreatedAt,
 	}
@@ -136,6 +305,7 @@ func (h *SessionHandler) ValidateSession(c echo.Context) error {
 			OwnerEmail:  session.Event.OwnerEmail,
 			CreatedAt:   session.Event.CreatedAt,
 			UpdatedAt:   session.Event.UpdatedAt,
+			Links:       eventLinks(&session.Event, false),
 		}
 	}
 
@@ -146,16 +316,16 @@ func (h *SessionHandler) ValidateSession(c echo.Context) error {
 func (h *SessionHandler) RefreshSession(c echo.Context) error {
 	var req RefreshSessionRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	session, err := h.sessionService.RefreshSession(c.Request().Context(), req.SessionToken)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return sessionError(err)
 	}
 
 	response := SessionResponse{
@@ -163,6 +333,7 @@ func (h *SessionHandler) RefreshSession(c echo.Context) error {
 		EventID:      session.EventID.String(),
 		GuestName:    session.GuestName,
 		SessionToken: session.SessionToken,
+		Scopes:       strings.Split(session.Scopes, ","),
 		ExpiresAt:    session.ExpiresAt,
 		CreatedAt:    session.CreatedAt,
 	}
@@ -174,62 +345,178 @@ func (h *SessionHandler) RefreshSession(c echo.Context) error {
 func (h *SessionHandler) RevokeSession(c echo.Context) error {
 	var req RevokeSessionRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
 	if err := c.Validate(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fail(http.StatusBadRequest, err)
 	}
 
-	if err := h.sessionService.RevokeSession(c.Request().Context(), req.SessionToken); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	session, err := h.sessionService.RevokeSession(c.Request().Context(), req.SessionToken)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
 	}
 
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &session.EventID,
+		Actor:      guestActor(c, session),
+		Action:     "session.revoke",
+		TargetType: "session",
+		TargetID:   session.ID.String(),
+	})
+
 	return c.JSON(http.StatusOK, map[string]string{"message": "session revoked"})
 }
 
-// GetSessionsByEvent retrieves all active sessions for an event (admin only)
+// GetSessionsByEvent lists an event's sessions with the device each was opened from
 func (h *SessionHandler) GetSessionsByEvent(c echo.Context) error {
-	eventIDStr := c.Param("event_id")
-	eventID, err := uuid.Parse(eventIDStr)
+	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
 	}
 
-	// TODO: Add authorization check to ensure only event owner can access
+	var query SessionListQuery
+	if err := c.Bind(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	filter := services.SessionFilter{
+		Status: query.Status,
+		Limit:  query.PageLimit(defaultSessionListLimit, maxSessionListLimit),
+		Cursor: query.Cursor,
+	}
 
-	sessions, err := h.sessionService.GetSessionsByEvent(c.Request().Context(), eventID)
+	page, err := h.sessionService.GetSessionsByEvent(c.Request().Context(), eventID, filter)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
 	// Convert to response DTOs
-	responses := make([]SessionResponse, len(sessions))
-	for i, session := range sessions {
-		responses[i] = SessionResponse{
-			ID:           session.ID.String(),
-			EventID:      session.EventID.String(),
-			GuestName:    session.GuestName,
-			SessionToken: session.SessionToken,
-			ExpiresAt:    session.ExpiresAt,
-			CreatedAt:    session.CreatedAt,
+	now := time.Now()
+	responses := make([]SessionSummaryResponse, len(page.Sessions))
+	for i, session := range page.Sessions {
+		responses[i] = SessionSummaryResponse{
+			ID:               session.ID.String(),
+			EventID:          session.EventID.String(),
+			GuestName:        session.GuestName,
+			TokenFingerprint: services.TokenFingerprint(session.SessionToken),
+			Scopes:           strings.Split(session.Scopes, ","),
+			UserAgent:        session.UserAgent,
+			IPAddress:        session.IPAddress,
+			Active:           session.ExpiresAt.After(now),
+			ExpiresAt:        session.ExpiresAt,
+			CreatedAt:        session.CreatedAt,
+		}
+		if session.ClientFingerprint != nil {
+			responses[i].DeviceID = (*session.ClientFingerprint)[:12]
 		}
 	}
 
-	result := SessionsListResponse{
-		Sessions: responses,
-		Count:    len(responses),
-	}
+	result := newListResponse(c, responses, page.NextCursor)
+	result.Meta.Total = &page.Total
 
 	return c.JSON(http.StatusOK, result)
 }
 
+// GetGuestsByEvent returns per-guest session aggregates for an event
+func (h *SessionHandler) GetGuestsByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	summaries, err := h.sessionService.GetGuestSummariesByEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]GuestSummaryResponse, len(summaries))
+	for i, summary := range summaries {
+		responses[i] = GuestSummaryResponse{
+			GuestName:      summary.GuestName,
+			SessionCount:   summary.SessionCount,
+			ActiveSessions: summary.ActiveSessions,
+			FirstJoinedAt:  summary.FirstJoinedAt,
+			LastExpiresAt:  summary.LastExpiresAt,
+		}
+	}
+
+	return listResponse(c, responses, "")
+}
+
+// RevokeEventSession lets the owner sign out a single guest session
+func (h *SessionHandler) RevokeEventSession(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid session ID")
+	}
+
+	err = h.sessionService.RevokeEventSession(c.Request().Context(), eventID, sessionID)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "session.revoke",
+		TargetType: "session",
+		TargetID:   sessionID.String(),
+	})
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "session revoked"})
+}
+
+// RevokeDevice signs out every session opened from the same device as the given session
+func (h *SessionHandler) RevokeDevice(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid session ID")
+	}
+
+	revoked, err := h.sessionService.RevokeDevice(c.Request().Context(), eventID, sessionID)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "session.revoke_device",
+		TargetType: "session",
+		TargetID:   sessionID.String(),
+		Details:    map[string]any{"revoked_sessions": revoked},
+	})
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "device sessions revoked", "count": revoked})
+}
+
 // CleanupExpiredSessions removes expired sessions (admin/system endpoint)
 func (h *SessionHandler) CleanupExpiredSessions(c echo.Context) error {
-	// TODO: Add proper authorization for admin/system endpoints
-
 	if err := h.sessionService.CleanupExpiredSessions(c.Request().Context()); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return fail(http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "expired sessions cleaned up"})
@@ -253,7 +540,16 @@ func (h *SessionHandler) AuthMiddleware() echo.MiddlewareFunc {
 			token := authHeader[len(bearerPrefix):]
 			session, err := h.sessionService.ValidateSession(c.Request().Context(), token)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired session")
+				return sessionError(err)
+			}
+
+			extended, err := h.sessionService.TouchSession(c.Request().Context(), session)
+			if err != nil {
+				return fail(http.StatusInternalServerError, err)
+			}
+			if extended {
+				// Lets clients keep their stored expiry in sync without calling refresh
+				c.Response().Header().Set("X-Session-Expires-At", session.ExpiresAt.Format(time.RFC3339))
 			}
 
 			// Set session info in context for use by handlers
@@ -261,6 +557,81 @@ func (h *SessionHandler) AuthMiddleware() echo.MiddlewareFunc {
 			c.Set("uploader_name", session.GuestName)
 			c.Set("event_id", session.EventID.String())
 
+			return next(c)
+		}
+	}
+}
+
+// EventViewerMiddleware admits the owner or a collaborator of the event in the :id
+// path parameter, or a guest whose session belongs to it and may view its
+// photos. Handlers apply the event's visibility and download settings to guests.
+func (h *SessionHandler) EventViewerMiddleware(collaboratorService *services.CollaboratorService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			eventID, err := uuid.Parse(c.Param("id"))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+			}
+
+			token, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "authorization header required")
+			}
+
+			if claims, err := utils.ValidateOwnerJWT(token); err == nil {
+				userID, err := uuid.Parse(claims.UserID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired access token")
+				}
+				c.Set("user_id", userID)
+				c.Set("owner_email", claims.Email)
+
+				owner, _ := currentOwner(c)
+				allowed, err := collaboratorService.CanAccessEvent(c.Request().Context(), eventID, owner, services.EventCapabilityView)
+				if err != nil {
+					return fail(http.StatusInternalServerError, err)
+				}
+				if !allowed {
+					return echo.NewHTTPError(http.StatusForbidden, "you do not have access to this event")
+				}
+
+				return next(c)
+			}
+
+			session, err := h.sessionService.ValidateSession(c.Request().Context(), token)
+			if err != nil {
+				return sessionError(err)
+			}
+			if session.EventID != eventID {
+				return echo.NewHTTPError(http.StatusForbidden, "you do not have access to this event")
+			}
+			if !services.HasScope(session, models.SessionScopeView) {
+				return echo.NewHTTPError(http.StatusForbidden, "this session is not allowed to "+models.SessionScopeView)
+			}
+
+			c.Set("session", session)
+			c.Set("uploader_name", session.GuestName)
+			c.Set("event_id", session.EventID.String())
+
+			return next(c)
+		}
+	}
+}
+
+// RequireScope rejects sessions that were not granted scope; it must run
+// after AuthMiddleware
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			session, ok := c.Get("session").(*models.Session)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "session required")
+			}
+
+			if !services.HasScope(session, scope) {
+				return echo.NewHTTPError(http.StatusForbidden, "this session is not allowed to "+scope)
+			}
+
 			return next(c)
 		}
 	}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+	"snapShare/utils"
+)
+
+type SignOutHandler struct {
+	authService    *services.AuthService
+	sessionService *services.SessionService
+	auditService   *services.AuditService
+}
+
+func NewSignOutHandler(authService *services.AuthService, sessionService *services.SessionService, auditService *services.AuditService) *SignOutHandler {
+	return &SignOutHandler{
+		authService:    authService,
+		sessionService: sessionService,
+		auditService:   auditService,
+	}
+}
+
+// SignOutAll signs the caller out on every device. The bearer token may be an
+// owner access token, which revokes all of the owner's refresh tokens, or a
+// guest session token, which revokes all sessions of that guest.
+func (h *SignOutHandler) SignOutAll(c echo.Context) error {
+	token, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authorization header required")
+	}
+
+	if claims, err := utils.ValidateOwnerJWT(token); err == nil {
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired access token")
+		}
+
+		revoked, err := h.authService.LogoutAll(c.Request().Context(), userID)
+		if err != nil {
+			return fail(http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(http.StatusOK, map[string]any{"message": "signed out on all devices", "revoked": revoked})
+	}
+
+	session, err := h.sessionService.ValidateSession(c.Request().Context(), token)
+	if err != nil {
+		return sessionError(err)
+	}
+
+	revoked, err := h.sessionService.RevokeGuestSessions(c.Request().Context(), session)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &session.EventID,
+		Actor:      guestActor(c, session),
+		Action:     "session.revoke_all",
+		TargetType: "session",
+		TargetID:   session.ID.String(),
+		Details:    map[string]any{"revoked_sessions": revoked},
+	})
+
+	return c.JSON(http.StatusOK, map[string]any{"message": "signed out on all devices", "revoked": revoked})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/services"
+)
+
+// Response DTOs
+type EventStatsResponse struct {
+	EventID         string                  `json:"event_id"`
+	PhotoCount      int64                   `json:"photo_count"`
+	TotalBytes      int64                   `json:"total_bytes"`
+	Uploaders       int64                   `json:"uploaders"`
+	InFlightUploads int64                   `json:"in_flight_uploads"`
+	LastActivityAt  *time.Time              `json:"last_activity_at,omitempty"`
+	Timezone        string                  `json:"timezone"`
+	UploadsPerHour  []HourlyUploadsResponse `json:"uploads_per_hour"`
+	UploadsPerDay   []DailyUploadsResponse  `json:"uploads_per_day"`
+}
+
+type HourlyUploadsResponse struct {
+	Hour  time.Time `json:"hour"`
+	Count int64     `json:"count"`
+}
+
+type DailyUploadsResponse struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+type UploaderStatsResponse struct {
+	UploaderName  string    `json:"uploader_name"`
+	PhotoCount    int64     `json:"photo_count"`
+	TotalBytes    int64     `json:"total_bytes"`
+	FirstUploadAt time.Time `json:"first_upload_at"`
+	LastUploadAt  time.Time `json:"last_upload_at"`
+}
+
+type StatsHandler struct {
+	statsService *services.StatsService
+}
+
+func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetEventStats returns upload totals and activity of an event for its owner
+func (h *StatsHandler) GetEventStats(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	stats, err := h.statsService.GetEventStats(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	hourly := make([]HourlyUploadsResponse, len(stats.UploadsPerHour))
+	for i, bucket := range stats.UploadsPerHour {
+		hourly[i] = HourlyUploadsResponse{
+			Hour:  bucket.Hour,
+			Count: bucket.Count,
+		}
+	}
+
+	daily := make([]DailyUploadsResponse, len(stats.UploadsPerDay))
+	for i, bucket := range stats.UploadsPerDay {
+		daily[i] = DailyUploadsResponse{
+			Date:  bucket.Day.Format(time.DateOnly),
+			Count: bucket.Count,
+		}
+	}
+
+	return c.JSON(http.StatusOK, EventStatsResponse{
+		EventID:         eventID.String(),
+		PhotoCount:      stats.PhotoCount,
+		TotalBytes:      stats.TotalBytes,
+		Uploaders:       stats.Uploaders,
+		InFlightUploads: stats.InFlightUploads,
+		LastActivityAt:  stats.LastActivityAt,
+		Timezone:        stats.Location.String(),
+		UploadsPerHour:  hourly,
+		UploadsPerDay:   daily,
+	})
+}
+
+// GetUploaderStats returns how many photos each guest contributed to an event
+func (h *StatsHandler) GetUploaderStats(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	uploaders, err := h.statsService.GetUploaderStats(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]UploaderStatsResponse, len(uploaders))
+	for i, uploader := range uploaders {
+		responses[i] = UploaderStatsResponse{
+			UploaderName:  uploader.UploaderName,
+			PhotoCount:    uploader.PhotoCount,
+			TotalBytes:    uploader.TotalBytes,
+			FirstUploadAt: uploader.FirstUploadAt,
+			LastUploadAt:  uploader.LastUploadAt,
+		}
+	}
+
+	return listResponse(c, responses, "")
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type UpdateThemeRequest struct {
+	PrimaryColor *string `json:"primary_color,omitempty" validate:"omitempty,hexcolor,len=7"`
+	WelcomeText  *string `json:"welcome_text,omitempty" validate:"omitempty,max=1000"`
+	LogoKey      *string `json:"logo_key,omitempty" validate:"omitempty,max=255"`
+}
+
+type LogoUploadURLRequest struct {
+	ContentType string `json:"content_type" validate:"required,oneof=image/png image/jpeg image/webp"`
+}
+
+// Response DTOs
+type EventThemeResponse struct {
+	PrimaryColor *string `json:"primary_color,omitempty"`
+	WelcomeText  *string `json:"welcome_text,omitempty"`
+	LogoURL      *string `json:"logo_url,omitempty"`
+}
+
+type LogoUploadURLResponse struct {
+	UploadURL string `json:"upload_url"`
+	LogoKey   string `json:"logo_key"`
+}
+
+type ThemeHandler struct {
+	themeService *services.ThemeService
+}
+
+func NewThemeHandler(themeService *services.ThemeService) *ThemeHandler {
+	return &ThemeHandler{
+		themeService: themeService,
+	}
+}
+
+// UpdateTheme updates the branding shown on an event's guest landing page
+func (h *ThemeHandler) UpdateTheme(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req UpdateThemeRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	serviceReq := &services.UpdateThemeRequest{
+		PrimaryColor: req.PrimaryColor,
+		WelcomeText:  req.WelcomeText,
+		LogoKey:      req.LogoKey,
+	}
+
+	event, err := h.themeService.UpdateTheme(c.Request().Context(), eventID, serviceReq)
+	if errors.Is(err, services.ErrInvalidLogoKey) {
+		return fail(http.StatusBadRequest, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, toEventThemeResponse(h.themeService, event))
+}
+
+// GenerateLogoUploadURL issues a presigned URL for uploading an event logo
+func (h *ThemeHandler) GenerateLogoUploadURL(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req LogoUploadURLRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	uploadInfo, err := h.themeService.GenerateLogoUploadURL(c.Request().Context(), eventID, req.ContentType)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, LogoUploadURLResponse{
+		UploadURL: uploadInfo.UploadURL,
+		LogoKey:   uploadInfo.ObjectKey,
+	})
+}
+
+func toEventThemeResponse(themeService *services.ThemeService, event *models.Event) *EventThemeResponse {
+	return &EventThemeResponse{
+		PrimaryColor: event.ThemePrimaryColor,
+		WelcomeText:  event.ThemeWelcomeText,
+		LogoURL:      themeService.LogoURL(event),
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"snapShare/i18n"
+)
+
+// fieldErrors translates validator errors into one FieldError per field,
+// explained in lang. Fields are named by their JSON path, e.g.
+// files[0].content_type.
+func fieldErrors(errs validator.ValidationErrors, lang i18n.Lang) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		// The namespace starts with the request type, e.g. CreateEventRequest.name
+		_, path, _ := strings.Cut(fe.Namespace(), ".")
+		if path == "" {
+			path = fe.Field()
+		}
+		fields = append(fields, FieldError{
+			Field:   path,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: validationMessage(fe, path, lang),
+		})
+	}
+	return fields
+}
+
+// validationMessage explains a failed rule of the field at path; min, max
+// and len read as a length for strings, a count for lists and a value for
+// numbers
+func validationMessage(fe validator.FieldError, path string, lang i18n.Lang) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return i18n.Sprintf(lang, "%s is required", path)
+	case "required_without":
+		return i18n.Sprintf(lang, "%s is required when %s is not set", path, param)
+	case "email":
+		return i18n.Sprintf(lang, "%s must be a valid email address", path)
+	case "uuid":
+		return i18n.Sprintf(lang, "%s must be a UUID", path)
+	case "http_url":
+		return i18n.Sprintf(lang, "%s must be an http or https URL", path)
+	case "hexcolor":
+		return i18n.Sprintf(lang, "%s must be a hex color such as #1a2b3c", path)
+	case "timezone":
+		return i18n.Sprintf(lang, "%s must be an IANA timezone such as Asia/Tokyo", path)
+	case "contains":
+		return i18n.Sprintf(lang, "%s must contain %q", path, param)
+	case "oneof":
+		return i18n.Sprintf(lang, "%s must be one of %s", path, strings.Join(strings.Fields(param), ", "))
+	case "min", "max", "len":
+		return i18n.Sprintf(lang, boundFormat(fe.Tag(), fe.Kind()), path, param)
+	}
+	return i18n.Sprintf(lang, "%s is invalid", path)
+}
+
+// boundFormats are the message formats of min, max and len rules by the
+// kind of field they apply to
+var boundFormats = map[string][3]string{
+	"min": {"%s must be at least %s characters long", "%s must have at least %s items", "%s must be at least %s"},
+	"max": {"%s must be at most %s characters long", "%s must have at most %s items", "%s must be at most %s"},
+	"len": {"%s must be exactly %s characters long", "%s must have exactly %s items", "%s must be exactly %s"},
+}
+
+func boundFormat(tag string, kind reflect.Kind) string {
+	formats := boundFormats[tag]
+	switch kind {
+	case reflect.String:
+		return formats[0]
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return formats[1]
+	}
+	return formats[2]
+}
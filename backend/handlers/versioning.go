@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CurrentAPIVersion is the version unversioned /api paths are served by
+const CurrentAPIVersion = "v1"
+
+// Deprecation describes a route or API version that is going away
+type Deprecation struct {
+	// Since is when the route was deprecated
+	Since time.Time
+	// Sunset is when the route stops working; zero when not yet decided
+	Sunset time.Time
+	// Successor is the path clients should move to, if any
+	Successor string
+}
+
+// setHeaders adds the Deprecation (RFC 9745), Sunset (RFC 8594) and
+// successor Link headers
+func (d Deprecation) setHeaders(h http.Header) {
+	if d.Since.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		h.Add("Link", "<"+d.Successor+`>; rel="successor-version"`)
+	}
+}
+
+// Deprecated marks the routes it wraps as deprecated. When a v2 handler
+// replaces a v1 route, wrap the v1 route with it and point Successor at v2.
+func Deprecated(d Deprecation) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			d.setHeaders(c.Response().Header())
+			return next(c)
+		}
+	}
+}
+
+var versionedAPIPath = regexp.MustCompile(`^/api/v[0-9]+(/|$)`)
+
+// UnversionedAPIRewrite serves paths under /api without a version from the
+// current version, marking the responses deprecated. It must be installed
+// with e.Pre so it runs before routing. Paths listed in keep are left alone.
+func UnversionedAPIRewrite(keep ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			path := req.URL.Path
+			if !strings.HasPrefix(path, "/api/") || versionedAPIPath.MatchString(path) {
+				return next(c)
+			}
+			for _, prefix := range keep {
+				if path == prefix || strings.HasPrefix(path, prefix+"/") {
+					return next(c)
+				}
+			}
+
+			req.URL.Path = "/api/" + CurrentAPIVersion + strings.TrimPrefix(path, "/api")
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = "/api/" + CurrentAPIVersion + strings.TrimPrefix(req.URL.RawPath, "/api")
+			}
+			Deprecation{Successor: req.URL.EscapedPath()}.setHeaders(c.Response().Header())
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Request DTOs
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=photo.uploaded photo.deleted event.closed session.created"`
+}
+
+// Response DTOs
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	EventID    string   `json:"event_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// Secret is only returned when the webhook is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	auditService   *services.AuditService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService, auditService *services.AuditService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		auditService:   auditService,
+	}
+}
+
+// CreateWebhook registers a webhook for an event. The response carries the
+// signing secret, which is not shown again.
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	var req CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request().Context(), eventID, req.URL, req.EventTypes)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookEventType) || errors.Is(err, services.ErrInvalidWebhookURL) {
+			return fail(http.StatusBadRequest, err)
+		}
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "webhook.create",
+		TargetType: "webhook",
+		TargetID:   webhook.ID.String(),
+		Details:    map[string]any{"url": webhook.URL, "event_types": webhook.EventTypes},
+	})
+
+	response := toWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetWebhooksByEvent lists the webhooks registered for an event
+func (h *WebhookHandler) GetWebhooksByEvent(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	webhooks, err := h.webhookService.GetWebhooksByEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	responses := make([]WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = toWebhookResponse(&webhooks[i])
+	}
+
+	return listResponse(c, responses, "")
+}
+
+// DeleteWebhook removes a webhook from an event
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	webhookIDStr := c.Param("webhook_id")
+	webhookID, err := uuid.Parse(webhookIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid webhook ID")
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request().Context(), eventID, webhookID); err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			return fail(http.StatusNotFound, err)
+		}
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	recordAudit(c, h.auditService, services.AuditEntry{
+		EventID:    &eventID,
+		Action:     "webhook.delete",
+		TargetType: "webhook",
+		TargetID:   webhookID.String(),
+	})
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "webhook deleted"})
+}
+
+func toWebhookResponse(webhook *models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:         webhook.ID.String(),
+		EventID:    webhook.EventID.String(),
+		URL:        webhook.URL,
+		EventTypes: strings.Split(webhook.EventTypes, ","),
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Response DTOs
+type WrapUpResponse struct {
+	ID           string              `json:"id"`
+	EventID      string              `json:"event_id"`
+	Step         models.WrapUpStep   `json:"step"`
+	Status       models.WrapUpStatus `json:"status"`
+	ArchiveJobID *string             `json:"archive_job_id,omitempty"`
+	Attempts     int                 `json:"attempts"`
+	Error        *string             `json:"error,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	CompletedAt  *time.Time          `json:"completed_at,omitempty"`
+}
+
+type WrapUpHandler struct {
+	wrapUpService *services.WrapUpService
+}
+
+func NewWrapUpHandler(wrapUpService *services.WrapUpService) *WrapUpHandler {
+	return &WrapUpHandler{
+		wrapUpService: wrapUpService,
+	}
+}
+
+// StartWrapUp runs the end-of-event workflow now instead of waiting for the schedule
+func (h *WrapUpHandler) StartWrapUp(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	job, err := h.wrapUpService.StartWrapUp(c.Request().Context(), eventID)
+	if errors.Is(err, services.ErrWrapUpStarted) {
+		return fail(http.StatusConflict, err)
+	}
+	if errors.Is(err, services.ErrEventNotFound) {
+		return fail(http.StatusNotFound, err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusAccepted, toWrapUpResponse(job))
+}
+
+// GetWrapUp returns the progress of an event's wrap-up workflow
+func (h *WrapUpHandler) GetWrapUp(c echo.Context) error {
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event ID")
+	}
+
+	job, err := h.wrapUpService.GetWrapUp(c.Request().Context(), eventID)
+	if err != nil {
+		return fail(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, toWrapUpResponse(job))
+}
+
+func toWrapUpResponse(job *models.WrapUpJob) WrapUpResponse {
+	response := WrapUpResponse{
+		ID:          job.ID.String(),
+		EventID:     job.EventID.String(),
+		Step:        job.Step,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+	if job.ArchiveJobID != nil {
+		archiveJobID := job.ArchiveJobID.String()
+		response.ArchiveJobID = &archiveJobID
+	}
+	return response
+}
@@ -0,0 +1,99 @@
+// Package i18n translates user-facing messages. Messages are written in
+// English in the code and double as the key of their translations, so an
+// untranslated message is still shown in English.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang is a supported language, as a BCP 47 primary language subtag
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+)
+
+// Supported lists the languages messages are translated to
+var Supported = []Lang{Japanese, English}
+
+// Parse returns the supported language matching lang, e.g. ja for ja-JP
+func Parse(lang string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(lang), "-")
+	primary = strings.ToLower(primary)
+	for _, supported := range Supported {
+		if primary == string(supported) {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the supported language the Accept-Language header
+// prefers most. ok is false when the header accepts none of them.
+func Negotiate(header string) (lang Lang, ok bool) {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		parsed, supported := Parse(tag)
+		if !supported {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			var err error
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{parsed, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	// Stable, so equally weighted languages keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang, true
+}
+
+type contextKey struct{}
+
+// WithLang returns a context carrying the language of the user being served
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language stored by WithLang, or fallback
+func FromContext(ctx context.Context, fallback Lang) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return fallback
+}
+
+// T translates msg to lang, returning msg itself when it has no translation
+func T(lang Lang, msg string) string {
+	if translated, ok := catalog[lang][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf translates format to lang and formats it with args. Translations
+// may reorder args with explicit indexes such as %[2]s.
+func Sprintf(lang Lang, format string, args ...any) string {
+	return fmt.Sprintf(T(lang, format), args...)
+}
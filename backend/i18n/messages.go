@@ -0,0 +1,127 @@
+package i18n
+
+// catalog holds the translations of each message by language. English is the
+// source language and needs no entries.
+var catalog = map[Lang]map[string]string{
+	Japanese: {
+		// Service errors
+		"backup not found":                                        "バックアップが見つかりません",
+		"the event's sharing terms must be accepted":              "イベントの共有規約への同意が必要です",
+		"event is not closed":                                     "イベントはまだ終了していません",
+		"invalid timezone":                                        "タイムゾーンが正しくありません",
+		"event not found or closed":                               "イベントが見つからないか、終了しています",
+		"event is not accepting guests":                           "このイベントは現在ゲストを受け付けていません",
+		"invalid cursor":                                          "カーソルが正しくありません",
+		"logo object key does not belong to this event":           "ロゴのファイルがこのイベントのものではありません",
+		"session not found":                                       "セッションが見つかりません",
+		"session not found or expired":                            "セッションが見つからないか、有効期限が切れています",
+		"invalid session scope":                                   "セッションの権限が正しくありません",
+		"photo not found":                                         "写真が見つかりません",
+		"guest downloads are disabled for this event":             "このイベントではゲストのダウンロードが無効になっています",
+		"guest is banned from this event":                         "このイベントへの参加は制限されています",
+		"media type is not allowed for this event":                "このイベントではこの形式のファイルはアップロードできません",
+		"custom media policy requires allowed_mime_types":         "カスタムのメディアポリシーには allowed_mime_types の指定が必要です",
+		"no photos found for event":                               "このイベントには写真がありません",
+		"archive has expired":                                     "アーカイブの有効期限が切れています",
+		"invalid webhook event type":                              "Webhookのイベント種別が正しくありません",
+		"webhook URL must use https and a public host":            "WebhookのURLはhttpsで、公開されたホストを指定してください",
+		"webhook not found":                                       "Webhookが見つかりません",
+		"guest limit reached for this event":                      "このイベントのゲスト数が上限に達しました",
+		"photo limit reached for this event":                      "このイベントの写真の枚数が上限に達しました",
+		"upload exceeds the remaining photo quota for this event": "アップロードする写真がこのイベントの残りの上限を超えています",
+		"email is already a collaborator on this event":           "このメールアドレスはすでに共同ホストです",
+		"wrap-up has already been started for this event":         "このイベントの終了処理はすでに開始されています",
+		"invitation not found or already accepted":                "招待が見つからないか、すでに承認されています",
+		"gallery is not public":                                   "ギャラリーは公開されていません",
+		"gallery is private for this event":                       "このイベントのギャラリーは非公開です",
+		"photo cannot be rendered":                                "この写真は表示できません",
+		"image is too large to process":                           "画像が大きすぎるため処理できません",
+		"media processing is busy, try again shortly":             "画像処理が混み合っています。しばらくしてから再度お試しください",
+		"email is already registered":                             "このメールアドレスはすでに登録されています",
+		"invalid email or password":                               "メールアドレスまたはパスワードが正しくありません",
+		"email is not verified, sign in with a login link":        "メールアドレスが確認されていません。ログインリンクからログインしてください",
+		"invalid or expired refresh token":                        "リフレッシュトークンが無効か、有効期限が切れています",
+		"invalid or expired login link":                           "ログインリンクが無効か、有効期限が切れています",
+		"the provider did not return a verified email":            "認証済みのメールアドレスを取得できませんでした",
+		"unknown webhook source":                                  "不明なWebhookの送信元です",
+		"webhook was already received":                            "このWebhookはすでに受信済みです",
+		"webhook signature is missing":                            "Webhookの署名がありません",
+		"webhook signature is invalid":                            "Webhookの署名が正しくありません",
+		"webhook timestamp is too old or in the future":           "Webhookのタイムスタンプが有効期間外です",
+		"captcha verification failed":                             "CAPTCHA認証に失敗しました",
+		"storage is temporarily unavailable":                      "ストレージが一時的に利用できません",
+
+		// Handler errors
+		"invalid event ID":                     "イベントIDが正しくありません",
+		"invalid photo ID":                     "写真IDが正しくありません",
+		"invalid session ID":                   "セッションIDが正しくありません",
+		"invalid job ID":                       "ジョブIDが正しくありません",
+		"invalid webhook ID":                   "WebhookのIDが正しくありません",
+		"invalid entry ID":                     "ゲストブックのIDが正しくありません",
+		"invalid collaborator ID":              "共同ホストのIDが正しくありません",
+		"invalid batch ID":                     "バッチIDが正しくありません",
+		"invalid alert ID":                     "アラートIDが正しくありません",
+		"session required":                     "セッションが必要です",
+		"session token is required":            "セッショントークンが必要です",
+		"owner authentication required":        "主催者としてのログインが必要です",
+		"invalid or expired access token":      "アクセストークンが無効か、有効期限が切れています",
+		"authorization header required":        "Authorizationヘッダーが必要です",
+		"invalid authorization format":         "Authorizationヘッダーの形式が正しくありません",
+		"invalid API key":                      "APIキーが正しくありません",
+		"admin API is disabled":                "管理APIは無効になっています",
+		"uploader name required":               "投稿者名が必要です",
+		"unknown sign-in provider":             "対応していないログイン方法です",
+		"you do not have access to this event": "このイベントへのアクセス権がありません",
+		"you can only delete your own photos":  "削除できるのは自分が投稿した写真だけです",
+		"only the event owner can do this":     "この操作はイベントの主催者のみ行えます",
+		"format must be json or csv":           "形式には json または csv を指定してください",
+		"event code is required":               "イベントコードが必要です",
+		"close_at must be in the future":       "close_at には未来の日時を指定してください",
+		"webhook payload is too large":         "Webhookのペイロードが大きすぎます",
+		"archive job not found":                "アーカイブのジョブが見つかりません",
+
+		// Validation
+		"request validation failed":                      "入力内容に誤りがあります",
+		"%s is required":                                 "%sは必須です",
+		"%s is required when %s is not set":              "%[2]sを指定しない場合、%[1]sは必須です",
+		"%s must be a valid email address":               "%sには有効なメールアドレスを指定してください",
+		"%s must be a UUID":                              "%sにはUUIDを指定してください",
+		"%s must be an http or https URL":                "%sにはhttpまたはhttpsのURLを指定してください",
+		"%s must be a hex color such as #1a2b3c":         "%sには#1a2b3cのような16進数の色を指定してください",
+		"%s must be an IANA timezone such as Asia/Tokyo": "%sにはAsia/TokyoのようなIANAタイムゾーンを指定してください",
+		"%s must contain %q":                             "%sには%qを含めてください",
+		"%s must be one of %s":                           "%sには%sのいずれかを指定してください",
+		"%s must be at least %s characters long":         "%sは%s文字以上にしてください",
+		"%s must be at most %s characters long":          "%sは%s文字以内にしてください",
+		"%s must be exactly %s characters long":          "%sは%s文字にしてください",
+		"%s must have at least %s items":                 "%sは%s件以上にしてください",
+		"%s must have at most %s items":                  "%sは%s件以内にしてください",
+		"%s must have exactly %s items":                  "%sは%s件にしてください",
+		"%s must be at least %s":                         "%sは%s以上にしてください",
+		"%s must be at most %s":                          "%sは%s以下にしてください",
+		"%s must be exactly %s":                          "%sは%sにしてください",
+		"%s is invalid":                                  "%sが正しくありません",
+
+		// Emails
+		"[SnapShare] Sign-in link":               "[SnapShare] ログインリンク",
+		"[SnapShare] Confirm your email address": "[SnapShare] メールアドレスの確認",
+		"Confirm your email address to finish creating your SnapShare account (valid for %d minutes):\n%s\n\nIf you did not sign up, you can ignore this email.\n": "以下のリンクからメールアドレスを確認し、SnapShareのアカウント登録を完了してください（%d分間有効）:\n%s\n\n登録に心当たりがない場合は無視してください。\n",
+		"Sign in to SnapShare with this link (valid for %d minutes):\n%s\n\nIf you did not request it, you can ignore this email.\n":                               "以下のリンクからSnapShareにログインしてください（%d分間有効）:\n%s\n\nこのメールに心当たりがない場合は無視してください。\n",
+		"[SnapShare] Invitation to %s": "[SnapShare] %s への招待",
+		"You have been invited to co-host \"%s\". Accept the invitation with this link:\n%s\n": "「%s」の共同ホストに招待されました。以下のリンクから招待を承認してください:\n%s\n",
+		"[SnapShare] Usage notice for %s":                           "[SnapShare] %s の利用状況のお知らせ",
+		"\"%s\": %s.\n":                                             "「%s」の%s。\n",
+		"the photo count reached %d (threshold: %d)":                "写真の枚数が%d枚に達しました（しきい値: %d枚）",
+		"storage reached %d bytes (threshold: %d bytes)":            "保存容量が%dバイトに達しました（しきい値: %dバイト）",
+		"%d%% of the photo limit was reached (threshold: %d%%)":     "写真の上限の%d%%に達しました（しきい値: %d%%）",
+		"%d%% of the guest limit was reached (threshold: %d%%)":     "ゲストの上限の%d%%に達しました（しきい値: %d%%）",
+		"[SnapShare] Photo archive of %s":                           "[SnapShare] %s の写真アーカイブ",
+		"Photo uploads to \"%s\" have closed.\n\n":                  "「%s」の写真受付を終了しました。\n\n",
+		"An archive of %d photos can be downloaded until %s:\n%s\n": "%d枚の写真をまとめたアーカイブをダウンロードできます（%sまで有効）:\n%s\n",
+		"No photos were uploaded to this event.\n":                  "このイベントには写真がアップロードされませんでした。\n",
+		"[SnapShare] Photos of %s will be deleted":                  "[SnapShare] %s の写真削除のお知らせ",
+		"The photos of \"%s\" will be permanently deleted after %s when their retention period ends.\nPlease download the photos you need before then.\n": "「%s」の写真は保存期間の終了に伴い、%s以降に完全に削除されます。\n必要な写真は期限までにダウンロードしてください。\n",
+		"[SnapShare] Export of %s": "[SnapShare] %s のエクスポート",
+		"The export of \"%s\" with %d photos and the event details is ready and can be downloaded until %s:\n%s\n": "「%s」の写真%d枚と情報をまとめたエクスポートの準備ができました（%sまで有効）:\n%s\n",
+	},
+}
@@ -0,0 +1,137 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+// uploadBlockSize is the block size used for streamed uploads
+const uploadBlockSize = 16 * 1024 * 1024
+
+// BlobService stores objects in an Azure Blob Storage container. Presigned
+// URLs are service SAS URLs signed with the account key.
+type BlobService struct {
+	client       *container.Client
+	publicDomain string
+}
+
+// NewBlobService opens containerName in the given storage account. endpoint
+// overrides https://<account>.blob.core.windows.net (e.g. for Azurite), and
+// public URLs are built on publicDomain, or on the container URL when empty.
+func NewBlobService(accountName, accountKey, containerName, endpoint, publicDomain string) (*BlobService, error) {
+	cred, err := container.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", accountName)
+	}
+	containerURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(endpoint, "/"), containerName)
+
+	client, err := container.NewClientWithSharedKeyCredential(containerURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure container client: %w", err)
+	}
+
+	if publicDomain == "" {
+		publicDomain = containerURL
+	}
+
+	return &BlobService{
+		client:       client,
+		publicDomain: publicDomain,
+	}, nil
+}
+
+// GeneratePresignedUploadURL returns a SAS URL for a Put Blob request. Clients
+// must send the x-ms-blob-type: BlockBlob header with the upload.
+func (b *BlobService) GeneratePresignedUploadURL(_ context.Context, key string, _ string, duration time.Duration) (string, error) {
+	return b.client.NewBlobClient(key).GetSASURL(sas.BlobPermissions{Create: true, Write: true}, time.Now().Add(duration), nil)
+}
+
+func (b *BlobService) GeneratePresignedDeleteURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return b.client.NewBlobClient(key).GetSASURL(sas.BlobPermissions{Delete: true}, time.Now().Add(duration), nil)
+}
+
+func (b *BlobService) GeneratePresignedDownloadURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return b.client.NewBlobClient(key).GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(duration), nil)
+}
+
+// Ping checks that the container is reachable with the configured credentials
+func (b *BlobService) Ping(ctx context.Context) error {
+	_, err := b.client.GetProperties(ctx, nil)
+	return err
+}
+
+// GetObjectRange opens the inclusive byte range [start, end] of an object
+func (b *BlobService) GetObjectRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	resp, err := b.client.NewBlobClient(key).DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: start, Count: end - start + 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// GetObject opens an object for reading
+func (b *BlobService) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := b.client.NewBlobClient(key).DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// UploadMultipart uploads everything read from body to key as a block blob,
+// staging one block at a time so the object never has to fit in memory
+func (b *BlobService) UploadMultipart(ctx context.Context, key string, contentType string, body io.Reader) error {
+	_, err := b.client.NewBlockBlobClient(key).UploadStream(ctx, body, &blockblob.UploadStreamOptions{
+		BlockSize:   uploadBlockSize,
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	return err
+}
+
+// ListObjectKeys returns every key under prefix
+func (b *BlobService) ListObjectKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pager := b.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			keys = append(keys, *item.Name)
+		}
+	}
+
+	return keys, nil
+}
+
+// DeleteObjects permanently deletes the given keys, ignoring missing ones
+func (b *BlobService) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		_, err := b.client.NewBlobClient(key).Delete(ctx, nil)
+		if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (b *BlobService) GetPublicURL(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(b.publicDomain, "/"), key)
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Bus broadcasts invalidated keys to every API instance, so that lookups an
+// instance keeps in its own memory are dropped everywhere when the data
+// behind them changes. It also carries messages for other instances on
+// named topics.
+type Bus interface {
+	// Publish announces invalidated keys to every instance
+	Publish(ctx context.Context, keys ...string) error
+	// Subscribe calls handle with the keys published by any instance,
+	// including this one, until ctx is done
+	Subscribe(ctx context.Context, handle func(keys []string))
+	// Broadcast sends message, encoded as JSON, to the listeners of topic on
+	// the other instances; this instance delivers its own messages itself
+	Broadcast(ctx context.Context, topic string, message any) error
+	// Listen calls handle with the JSON of every message broadcast on topic
+	// by another instance until ctx is done
+	Listen(ctx context.Context, topic string, handle func(message []byte))
+}
+
+// invalidationChannel is the Redis pub/sub channel carrying invalidated keys
+// and topic messages. Keys are sent as a JSON array, messages as a busMessage
+// object, so each kind of receiver skips the other.
+const invalidationChannel = "snapshare:invalidations"
+
+// busMessage is a message broadcast on a topic
+type busMessage struct {
+	Origin string          `json:"origin"`
+	Topic  string          `json:"topic"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// RedisBus is a Bus over Redis pub/sub
+type RedisBus struct {
+	client *redis.Client
+	// origin tells this instance's broadcasts apart from the others'
+	origin string
+}
+
+func NewRedisBus(client *redis.Client) *RedisBus {
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+	return &RedisBus{client: client, origin: hex.EncodeToString(origin)}
+}
+
+func (b *RedisBus) Publish(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to encode invalidation: %w", err)
+	}
+	if err := b.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe reconnects on its own after Redis outages. Messages published
+// while disconnected are lost, so in-memory copies still need a short TTL.
+func (b *RedisBus) Subscribe(ctx context.Context, handle func(keys []string)) {
+	b.receive(ctx, func(payload []byte) {
+		var keys []string
+		if err := json.Unmarshal(payload, &keys); err != nil {
+			return
+		}
+		handle(keys)
+	})
+}
+
+func (b *RedisBus) Broadcast(ctx context.Context, topic string, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", topic, err)
+	}
+	payload, err := json.Marshal(busMessage{Origin: b.origin, Topic: topic, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", topic, err)
+	}
+	if err := b.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to broadcast %s message: %w", topic, err)
+	}
+	return nil
+}
+
+// Listen, like Subscribe, misses the messages broadcast during Redis outages
+func (b *RedisBus) Listen(ctx context.Context, topic string, handle func(message []byte)) {
+	b.receive(ctx, func(payload []byte) {
+		var msg busMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return
+		}
+		if msg.Topic != topic || msg.Origin == b.origin {
+			return
+		}
+		handle(msg.Data)
+	})
+}
+
+// receive calls handle with every payload on the channel until ctx is done.
+// Only this bus writes to the channel; payloads handle cannot decode are
+// skipped.
+func (b *RedisBus) receive(ctx context.Context, handle func(payload []byte)) {
+	sub := b.client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			handle([]byte(msg.Payload))
+		}
+	}
+}
+
+// NoopBus has no one to notify: without Redis there is a single instance,
+// which drops its own copies and delivers its own messages directly
+type NoopBus struct{}
+
+func (b NoopBus) Publish(_ context.Context, _ ...string) error {
+	return nil
+}
+
+func (b NoopBus) Subscribe(_ context.Context, _ func(keys []string)) {}
+
+func (b NoopBus) Broadcast(_ context.Context, _ string, _ any) error {
+	return nil
+}
+
+func (b NoopBus) Listen(_ context.Context, _ string, _ func(message []byte)) {}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores short-lived copies of hot database lookups. Its Bus carries
+// the keys it invalidates to every instance.
+type Cache interface {
+	Bus
+	// Get decodes the cached value into dst and reports whether there was one
+	Get(ctx context.Context, key string, dst any) (bool, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	// Delete drops keys from the shared cache and publishes them, so that
+	// copies instances keep in memory are dropped as well
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type RedisCache struct {
+	*RedisBus
+	client *redis.Client
+}
+
+// NewCache returns a Redis cache, or a cache that never hits when no Redis
+// URL is configured (local development and single-instance setups)
+func NewCache(redisURL string) (Cache, error) {
+	if redisURL == "" {
+		return &NoopCache{}, nil
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	return &RedisCache{RedisBus: NewRedisBus(client), client: client}, nil
+}
+
+// Values are gob encoded so that fields hidden from JSON survive the round trip
+func (c *RedisCache) Get(ctx context.Context, key string, dst any) (bool, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(dst); err != nil {
+		return false, fmt.Errorf("failed to decode cached value: %w", err)
+	}
+	return true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return fmt.Errorf("failed to encode cache value: %w", err)
+	}
+
+	if err := c.client.Set(ctx, key, buf.Bytes(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate cache: %w", err)
+	}
+	return c.Publish(ctx, keys...)
+}
+
+// NoopCache never stores anything
+type NoopCache struct {
+	NoopBus
+}
+
+func (c *NoopCache) Get(_ context.Context, _ string, _ any) (bool, error) {
+	return false, nil
+}
+
+func (c *NoopCache) Set(_ context.Context, _ string, _ any, _ time.Duration) error {
+	return nil
+}
+
+func (c *NoopCache) Delete(_ context.Context, _ ...string) error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Local is an in-process cache of values that expire after a fixed TTL. It
+// sits in front of the shared cache for the hottest lookups, where even a
+// Redis round trip per request adds up. Other instances learn of changes
+// through Bus.Subscribe; the TTL bounds staleness when a message is lost.
+type Local[V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]localEntry[V]
+}
+
+type localEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewLocal returns an empty in-process cache whose entries live for ttl
+func NewLocal[V any](ttl time.Duration) *Local[V] {
+	return &Local[V]{ttl: ttl, entries: map[string]localEntry[V]{}}
+}
+
+// Get returns the value stored under key, if it has not expired
+func (l *Local[V]) Get(key string) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key, dropping expired entries on the way so that
+// codes looked up once do not pile up
+func (l *Local[V]) Set(key string, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range l.entries {
+		if now.After(entry.expiresAt) {
+			delete(l.entries, k)
+		}
+	}
+	l.entries[key] = localEntry[V]{value: value, expiresAt: now.Add(l.ttl)}
+}
+
+// Delete drops the values stored under keys
+func (l *Local[V]) Delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.entries, key)
+	}
+}
+
+// DeleteFunc drops the values for which match returns true
+func (l *Local[V]) DeleteFunc(match func(key string, value V) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, entry := range l.entries {
+		if match(key, entry.value) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -1,17 +1,86 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"slices"
+	"snapShare/infra/tracing"
 	"snapShare/models"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
-func Connect(databaseURL string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+// Config sizes the connection pool and bounds query time
+type Config struct {
+	URL string
+	// Driver is "stdlib" (default), pooling connections in database/sql, or
+	// "pgxpool", pooling them in pgx, which also health checks idle ones
+	Driver          string
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+	// StatementTimeout aborts queries running longer; 0 disables it
+	StatementTimeout time.Duration
+	// StatementCacheSize is how many prepared statements each connection
+	// keeps, so that repeated queries skip parsing and planning; 0 sends
+	// every query unprepared
+	StatementCacheSize int
+	// LogLevel is one of silent, error, warn or info
+	LogLevel string
+	// CreateBatchSize splits the creation of many records into INSERTs of
+	// at most this many rows
+	CreateBatchSize int
+}
+
+var logLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+func Connect(cfg Config) (*gorm.DB, error) {
+	logLevel, ok := logLevels[cfg.LogLevel]
+	if !ok {
+		return nil, fmt.Errorf("unknown log level %q", cfg.LogLevel)
+	}
+
+	dsn, err := withParams(cfg.URL, connParams(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	dialector := postgres.Open(dsn)
+	switch cfg.Driver {
+	case "", "stdlib":
+	case "pgxpool":
+		conn, err := openPool(dsn, cfg)
+		if err != nil {
+			return nil, err
+		}
+		dialector = postgres.New(postgres.Config{Conn: conn})
+	default:
+		return nil, fmt.Errorf("unknown driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			LogLevel:      logLevel,
+			SlowThreshold: 200 * time.Millisecond,
+		}),
+		CreateBatchSize: cfg.CreateBatchSize,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
@@ -22,23 +91,226 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	if err := db.Use(tracing.NewGORMPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to install tracing: %w", err)
+	}
+
+	// pgxpool sizes itself; database/sql must not keep connections of its own
+	if cfg.Driver != "pgxpool" {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
 
 	return db, nil
 }
 
+// openPool opens a pgx connection pool sized like the database/sql one
+func openPool(dsn string, cfg Config) (*sql.DB, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+	poolConfig.MinConns = int32(cfg.MaxIdleConns)
+	if cfg.ConnMaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	}
+	return stdlib.OpenDBFromPool(pool), nil
+}
+
+// connParams are the connection string parameters derived from cfg. The
+// statement timeout is sent as a startup parameter, so it applies to every
+// pooled connection; the others configure pgx itself.
+func connParams(cfg Config) map[string]string {
+	params := map[string]string{}
+	if cfg.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.StatementCacheSize > 0 {
+		params["default_query_exec_mode"] = "cache_statement"
+		params["statement_cache_capacity"] = strconv.Itoa(cfg.StatementCacheSize)
+	} else {
+		params["default_query_exec_mode"] = "exec"
+	}
+	return params
+}
+
+// withParams adds params to a connection string. Both URL and key=value
+// connection strings are supported.
+func withParams(dsn string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return dsn, nil
+	}
+
+	if !strings.Contains(dsn, "://") {
+		keys := slices.Sorted(maps.Keys(params))
+		for _, key := range keys {
+			dsn += " " + key + "=" + params[key]
+		}
+		return dsn, nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	query := u.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
 func Migrate(db *gorm.DB) error {
 	err := db.AutoMigrate(
+		&models.User{},
+		&models.RefreshToken{},
+		&models.MagicLink{},
+		&models.OAuthIdentity{},
 		&models.Event{},
 		&models.Photo{},
+		&models.Guest{},
 		&models.Session{},
+		&models.ArchiveJob{},
+		&models.AuditLog{},
+		&models.WrapUpJob{},
+		&models.EventCollaborator{},
+		&models.GuestbookEntry{},
+		&models.GuestBan{},
+		&models.ThresholdAlert{},
+		&models.ConsentTerms{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.InboundWebhook{},
+		&models.ObjectDeletion{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to migrate: %w", err)
 	}
 
-	// TODO: indexの追加
+	// Indexes are built CONCURRENTLY so a deploy never blocks writes on large
+	// tables. A build that was interrupted leaves an invalid index behind,
+	// which IF NOT EXISTS would keep, so drop those first to rebuild them.
+	if err := dropInvalidIndexes(db); err != nil {
+		return err
+	}
+
+	// Trigram indexes for gallery search on caption and uploader name
+	searchIndexes := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_caption_trgm ON photos USING gin (caption gin_trgm_ops)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_uploader_name_trgm ON photos USING gin (uploader_name gin_trgm_ops)",
+	}
+	for _, stmt := range searchIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	// Link sessions and uploads from before guests were tracked to a guest per
+	// name. Display names used to be unique per event.
+	guestBackfill := []string{
+		"DROP INDEX IF EXISTS idx_guests_event_name",
+		`INSERT INTO guests (id, event_id, display_name, created_at, updated_at)
+			SELECT gen_random_uuid(), event_id, guest_name, MIN(created_at), MIN(created_at)
+			FROM sessions WHERE guest_id IS NULL
+				AND NOT EXISTS (SELECT 1 FROM guests g WHERE g.event_id = sessions.event_id AND g.display_name = sessions.guest_name)
+			GROUP BY event_id, guest_name`,
+		`UPDATE sessions SET guest_id = guests.id FROM guests
+			WHERE sessions.guest_id IS NULL AND guests.event_id = sessions.event_id AND guests.display_name = sessions.guest_name`,
+		`UPDATE photos SET guest_id = guests.id FROM guests
+			WHERE photos.guest_id IS NULL AND guests.event_id = photos.event_id AND guests.display_name = photos.uploader_name`,
+	}
+	for _, stmt := range guestBackfill {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to backfill guests: %w", err)
+		}
+	}
+
+	// Composite indexes for the gallery, its version token, per-guest listings
+	// and session lookups. Event codes are always matched on UPPER(code) of live events,
+	// so the partial index replaces the older full one.
+	tuningIndexes := []string{
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_event_created ON photos (event_id, created_at)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_event_uploader ON photos (event_id, uploader_name)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_event_updated ON photos (event_id, updated_at)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_sessions_event_expires ON sessions (event_id, expires_at)",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_events_code_active ON events (UPPER(code)) WHERE deleted_at IS NULL",
+		"DROP INDEX CONCURRENTLY IF EXISTS idx_events_code_upper",
+		// Quotas count pending uploads and archive estimates hidden photos on
+		// top of the event counters, both small subsets of a gallery
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_event_pending ON photos (event_id) WHERE confirmed_at IS NULL AND deleted_at IS NULL",
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_photos_event_hidden ON photos (event_id) WHERE hidden_at IS NOT NULL AND deleted_at IS NULL",
+	}
+	for _, stmt := range tuningIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	// Count the photos of events from before the counters existed. Photo
+	// changes keep the counters current from then on.
+	err = runOnce(db, "backfill_event_counters", `UPDATE events SET photo_count = c.photo_count, total_bytes = c.total_bytes
+		FROM (
+			SELECT events.id, COUNT(photos.id) AS photo_count, COALESCE(SUM(photos.size), 0) AS total_bytes
+			FROM events LEFT JOIN photos
+				ON photos.event_id = events.id AND photos.confirmed_at IS NOT NULL AND photos.deleted_at IS NULL
+			GROUP BY events.id
+		) AS c
+		WHERE events.id = c.id AND (events.photo_count, events.total_bytes) IS DISTINCT FROM (c.photo_count, c.total_bytes)`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill event counters: %w", err)
+	}
+
+	return nil
+}
+
+// runOnce applies a data migration the first time Migrate sees its name.
+// The name is recorded in the same transaction, so instances booting
+// together wait for the first one and then skip it.
+func runOnce(db *gorm.DB, name, stmt string) error {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS data_migrations (
+		name varchar(100) PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT NOW()
+	)`).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("INSERT INTO data_migrations (name) VALUES (?) ON CONFLICT DO NOTHING", name)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Exec(stmt).Error
+	})
+}
+
+// dropInvalidIndexes removes the indexes left invalid by an interrupted
+// concurrent build. Builds still running, e.g. from another instance
+// booting, are left alone.
+func dropInvalidIndexes(db *gorm.DB) error {
+	var names []string
+	err := db.Raw(`SELECT c.relname FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT i.indisvalid AND n.nspname = current_schema()
+			AND NOT EXISTS (SELECT 1 FROM pg_stat_progress_create_index p WHERE p.index_relid = i.indexrelid)`).Scan(&names).Error
+	if err != nil {
+		return fmt.Errorf("failed to list invalid indexes: %w", err)
+	}
+	for _, name := range names {
+		if err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS ?", clause.Table{Name: name}).Error; err != nil {
+			return fmt.Errorf("failed to drop invalid index %s: %w", name, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"snapShare/models"
+)
+
+// BenchmarkSessionLookup compares the drivers on the hot request path:
+// a session looked up by token, then its event by ID, as ValidateSession does
+// on a cache miss. It needs a migrated, disposable database and is skipped
+// without one:
+//
+//	BENCH_DATABASE_URL=postgres://... go test -run '^$' -bench SessionLookup ./infra/database
+func BenchmarkSessionLookup(b *testing.B) {
+	dsn := os.Getenv("BENCH_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("BENCH_DATABASE_URL is not set")
+	}
+
+	drivers := []struct {
+		name               string
+		driver             string
+		statementCacheSize int
+	}{
+		{"stdlib", "stdlib", 0},
+		{"stdlib-cached", "stdlib", 512},
+		{"pgxpool-cached", "pgxpool", 512},
+	}
+	for _, d := range drivers {
+		b.Run(d.name, func(b *testing.B) {
+			db, err := Connect(Config{
+				URL:                dsn,
+				Driver:             d.driver,
+				MaxIdleConns:       10,
+				MaxOpenConns:       20,
+				StatementCacheSize: d.statementCacheSize,
+				LogLevel:           "silent",
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			sqlDB, err := db.DB()
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Cleanup(func() { sqlDB.Close() })
+
+			token := seedSession(b, db)
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					var session models.Session
+					if err := db.Where("session_token = ? AND expires_at > ?", token, time.Now()).First(&session).Error; err != nil {
+						b.Error(err)
+						return
+					}
+					var event models.Event
+					if err := db.First(&event, session.EventID).Error; err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+// seedSession creates an event with a session and returns the session's
+// token. Both are removed when the benchmark ends.
+func seedSession(b *testing.B, db *gorm.DB) string {
+	b.Helper()
+
+	id := uuid.New()
+	event := models.Event{
+		ID:         id,
+		Name:       "Benchmark",
+		Code:       fmt.Sprintf("B%08X", id.ID()),
+		OwnerEmail: "bench@example.com",
+	}
+	session := models.Session{
+		ID:           uuid.New(),
+		EventID:      id,
+		GuestName:    "Bench",
+		SessionToken: uuid.NewString(),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	if err := db.Create(&event).Error; err != nil {
+		b.Fatal(err)
+	}
+	if err := db.Create(&session).Error; err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		db.Unscoped().Delete(&session)
+		db.Unscoped().Delete(&event)
+	})
+
+	return session.SessionToken
+}
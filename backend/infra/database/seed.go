@@ -2,7 +2,7 @@ package database
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"snapShare/models"
 	"time"
 
@@ -11,7 +11,7 @@ import (
 )
 
 func Seed(db *gorm.DB) error {
-	log.Println("Starting database seeding...")
+	slog.Info("Starting database seeding")
 
 	// Check if data already exists
 	var eventCount int64
@@ -20,7 +20,7 @@ func Seed(db *gorm.DB) error {
 	}
 
 	if eventCount > 0 {
-		log.Println("Seed data already exists, skipping...")
+		slog.Info("Seed data already exists, skipping")
 		return nil
 	}
 
@@ -160,11 +160,11 @@ func Seed(db *gorm.DB) error {
 		}
 	}
 
-	log.Printf("Seed data created successfully:")
-	log.Printf("- %d events", len(events))
-	log.Printf("- %d sessions", len(sessions))
-	log.Printf("- %d photos", len(photos))
-	log.Println("Sample event codes: WEDDING1, TRAVEL02, REUNION2")
+	slog.Info("Seed data created successfully",
+		"events", len(events),
+		"sessions", len(sessions),
+		"photos", len(photos),
+		"sample_codes", "WEDDING1, TRAVEL02, REUNION2")
 
 	return nil
 }
\ No newline at end of file
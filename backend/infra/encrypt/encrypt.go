@@ -0,0 +1,149 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Streams are split into chunks sealed with AES-256-GCM. A chunk's nonce is a
+// random per-stream prefix followed by the chunk counter, and the last chunk
+// is marked in its additional data, so reordered, dropped or truncated chunks
+// fail to decrypt.
+
+// ErrCorrupted is returned when a stream was tampered with, truncated or
+// encrypted with a different key
+var ErrCorrupted = errors.New("encrypted stream is corrupted or the key is wrong")
+
+const (
+	magic      = "SSENC1"
+	chunkSize  = 64 * 1024
+	prefixSize = 8
+)
+
+// KeySize is the required key length in bytes
+const KeySize = 32
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func nonce(prefix []byte, counter uint32) []byte {
+	n := make([]byte, prefixSize+4)
+	copy(n, prefix)
+	binary.BigEndian.PutUint32(n[prefixSize:], counter)
+	return n
+}
+
+func additionalData(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// Encrypt reads src until EOF and writes it to dst encrypted with key
+func Encrypt(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	if _, err := dst.Write(append([]byte(magic), prefix...)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+	header := make([]byte, 4)
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(src, buf)
+		final := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !final {
+			return err
+		}
+		if counter == ^uint32(0) && !final {
+			return errors.New("stream is too long to encrypt")
+		}
+
+		sealed := aead.Seal(nil, nonce(prefix, counter), buf[:n], additionalData(final))
+		binary.BigEndian.PutUint32(header, uint32(len(sealed)))
+		if _, err := dst.Write(header); err != nil {
+			return err
+		}
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+
+		if final {
+			return nil
+		}
+	}
+}
+
+// Decrypt reads a stream written by Encrypt from src and writes the plaintext
+// to dst. Chunks are written as they are verified, so dst may receive data
+// before ErrCorrupted is returned for a later chunk.
+func Decrypt(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	head := make([]byte, len(magic)+prefixSize)
+	if _, err := io.ReadFull(src, head); err != nil || string(head[:len(magic)]) != magic {
+		return ErrCorrupted
+	}
+	prefix := head[len(magic):]
+
+	header := make([]byte, 4)
+	sealed := make([]byte, chunkSize+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		if _, err := io.ReadFull(src, header); err != nil {
+			return ErrCorrupted
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size > uint32(len(sealed)) {
+			return ErrCorrupted
+		}
+		if _, err := io.ReadFull(src, sealed[:size]); err != nil {
+			return ErrCorrupted
+		}
+
+		final := false
+		plain, err := aead.Open(nil, nonce(prefix, counter), sealed[:size], additionalData(false))
+		if err != nil {
+			final = true
+			plain, err = aead.Open(nil, nonce(prefix, counter), sealed[:size], additionalData(true))
+			if err != nil {
+				return ErrCorrupted
+			}
+		}
+
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+
+		if final {
+			// Nothing may follow the final chunk
+			if _, err := io.ReadFull(src, header[:1]); err != io.EOF {
+				return ErrCorrupted
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GCSService stores objects in a Google Cloud Storage bucket. Credentials come
+// from Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS or the
+// attached service account), which must be able to sign URLs.
+type GCSService struct {
+	client       *storage.Client
+	bucket       *storage.BucketHandle
+	publicDomain string
+}
+
+// NewGCSService opens bucketName. Public URLs are built on publicDomain, or on
+// https://storage.googleapis.com/<bucket> when it is empty.
+func NewGCSService(ctx context.Context, bucketName, publicDomain string) (*GCSService, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	if publicDomain == "" {
+		publicDomain = "https://storage.googleapis.com/" + bucketName
+	}
+
+	return &GCSService{
+		client:       client,
+		bucket:       client.Bucket(bucketName),
+		publicDomain: publicDomain,
+	}, nil
+}
+
+func (g *GCSService) GeneratePresignedUploadURL(_ context.Context, key string, contentType string, duration time.Duration) (string, error) {
+	return g.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      http.MethodPut,
+		ContentType: contentType,
+		Expires:     time.Now().Add(duration),
+	})
+}
+
+func (g *GCSService) GeneratePresignedDeleteURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return g.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodDelete,
+		Expires: time.Now().Add(duration),
+	})
+}
+
+func (g *GCSService) GeneratePresignedDownloadURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return g.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(duration),
+	})
+}
+
+// Ping checks that the bucket is reachable with the configured credentials
+func (g *GCSService) Ping(ctx context.Context) error {
+	_, err := g.bucket.Attrs(ctx)
+	return err
+}
+
+// GetObjectRange opens the inclusive byte range [start, end] of an object
+func (g *GCSService) GetObjectRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	return g.bucket.Object(key).NewRangeReader(ctx, start, end-start+1)
+}
+
+// GetObject opens an object for reading
+func (g *GCSService) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	return g.bucket.Object(key).NewReader(ctx)
+}
+
+// UploadMultipart uploads everything read from body to key using a resumable
+// upload, so the object never has to fit in memory
+func (g *GCSService) UploadMultipart(ctx context.Context, key string, contentType string, body io.Reader) error {
+	// Cancelling the writer's context is the only way to abandon the upload;
+	// closing it would store whatever was written so far
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := g.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+
+	if _, err := io.Copy(w, body); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ListObjectKeys returns every key under prefix
+func (g *GCSService) ListObjectKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	it := g.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+
+	return keys, nil
+}
+
+// DeleteObjects permanently deletes the given keys, ignoring missing ones
+func (g *GCSService) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		err := g.bucket.Object(key).Delete(ctx)
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (g *GCSService) GetPublicURL(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(g.publicDomain, "/"), key)
+}
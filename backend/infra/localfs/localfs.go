@@ -0,0 +1,294 @@
+package localfs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKey is returned for object keys that would escape the storage root
+var ErrInvalidKey = errors.New("invalid object key")
+
+// tempPrefix marks partially written objects, which are hidden from listings
+const tempPrefix = ".upload-"
+
+// LocalStorage keeps objects as files under a root directory. Presigned URLs
+// point at the LocalStorage handler, which checks their HMAC signature, so
+// browsers upload and download the same way they do against R2.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocalStorage stores objects under root. baseURL is the public URL the
+// LocalStorage handler is mounted at, and secret signs presigned URLs.
+func NewLocalStorage(root, baseURL string, secret []byte) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+func (l *LocalStorage) GeneratePresignedUploadURL(_ context.Context, key string, contentType string, duration time.Duration) (string, error) {
+	return l.presign(http.MethodPut, key, contentType, duration)
+}
+
+func (l *LocalStorage) GeneratePresignedDeleteURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return l.presign(http.MethodDelete, key, "", duration)
+}
+
+func (l *LocalStorage) GeneratePresignedDownloadURL(_ context.Context, key string, duration time.Duration) (string, error) {
+	return l.presign(http.MethodGet, key, "", duration)
+}
+
+func (l *LocalStorage) presign(method, key, contentType string, duration time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
+	query := url.Values{
+		"method":    {method},
+		"expires":   {expires},
+		"signature": {l.sign(method, key, contentType, expires)},
+	}
+	if contentType != "" {
+		query.Set("content_type", contentType)
+	}
+
+	return l.objectURL(key) + "?" + query.Encode(), nil
+}
+
+func (l *LocalStorage) sign(method, key, contentType, expires string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte("localfs\n" + method + "\n" + key + "\n" + contentType + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *LocalStorage) objectURL(key string) string {
+	return l.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+// Ping checks that the storage root is still a directory
+func (l *LocalStorage) Ping(_ context.Context) error {
+	info, err := os.Stat(l.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", l.root)
+	}
+	return nil
+}
+
+// GetObjectRange opens the inclusive byte range [start, end] of an object
+func (l *LocalStorage) GetObjectRange(_ context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	file, err := l.open(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, end-start+1), file}, nil
+}
+
+// GetObject opens an object for reading
+func (l *LocalStorage) GetObject(_ context.Context, key string) (io.ReadCloser, error) {
+	return l.open(key)
+}
+
+func (l *LocalStorage) open(key string) (*os.File, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// UploadMultipart writes everything read from body to key. The object only
+// appears once it is complete.
+func (l *LocalStorage) UploadMultipart(_ context.Context, key string, _ string, body io.Reader) error {
+	return l.write(key, body)
+}
+
+func (l *LocalStorage) write(key string, body io.Reader) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// ListObjectKeys returns every key under prefix
+func (l *LocalStorage) ListObjectKeys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// DeleteObjects permanently deletes the given keys, ignoring missing ones
+func (l *LocalStorage) DeleteObjects(_ context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := l.remove(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LocalStorage) remove(key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalStorage) GetPublicURL(key string) string {
+	return l.objectURL(strings.TrimPrefix(key, "/"))
+}
+
+// path maps an object key to a file under the storage root
+func (l *LocalStorage) path(key string) (string, error) {
+	if key == "" || key != path.Clean("/" + key)[1:] || strings.HasPrefix(path.Base(key), tempPrefix) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// ServeHTTP serves objects at the URLs built by LocalStorage, with the base
+// URL prefix already stripped. Downloads are public like an R2 public bucket;
+// uploads and deletes need a valid presigned URL.
+func (l *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	p, err := l.path(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		l.serve(w, r, p)
+	case http.MethodPut:
+		if !l.verify(r, key, r.Header.Get("Content-Type")) {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+		if err := l.write(key, r.Body); err != nil {
+			http.Error(w, "failed to store object", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if !l.verify(r, key, "") {
+			http.Error(w, "invalid or expired signature", http.StatusForbidden)
+			return
+		}
+		if err := l.remove(key); err != nil {
+			http.Error(w, "failed to delete object", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (l *LocalStorage) serve(w http.ResponseWriter, r *http.Request, p string) {
+	file, err := os.Open(p)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// verify checks the presigned URL signature, method, content type and expiry
+func (l *LocalStorage) verify(r *http.Request, key, contentType string) bool {
+	query := r.URL.Query()
+	if query.Get("method") != r.Method || query.Get("content_type") != contentType {
+		return false
+	}
+
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+
+	expected := l.sign(r.Method, key, contentType, expires)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
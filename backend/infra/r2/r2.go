@@ -1,16 +1,39 @@
 package r2
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"golang.org/x/sync/errgroup"
+
+	"snapShare/infra/storage"
 )
 
+// MultipartPartSize is the part size used for multipart uploads (S3 minimum is 5MiB)
+const MultipartPartSize = 16 * 1024 * 1024
+
+// RetryPolicy bounds how calls failing with transient errors (5xx responses,
+// timeouts, dropped connections) are retried. Waits grow exponentially with
+// full jitter up to MaxBackoff and end early when the request's context does.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 disables retries
+	MaxAttempts int
+	MaxBackoff  time.Duration
+}
+
 type R2Service struct {
 	client       *s3.Client
 	presigner    *s3.PresignClient
@@ -18,7 +41,7 @@ type R2Service struct {
 	publicDomain string
 }
 
-func NewR2Service(accountID, accessKeyID, secretAccessKey, bucketName, publicDomain string) (*R2Service, error) {
+func NewR2Service(accountID, accessKeyID, secretAccessKey, bucketName, publicDomain string, policy RetryPolicy) (*R2Service, error) {
 	var endpoint string
 	var presignerEndpoint string
 
@@ -34,7 +57,15 @@ func NewR2Service(accountID, accessKeyID, secretAccessKey, bucketName, publicDom
 	cfg := aws.Config{
 		Region:      "auto",
 		Credentials: credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = policy.MaxAttempts
+				o.MaxBackoff = policy.MaxBackoff
+			})
+		},
 	}
+	// Presigning makes no calls, so only this client is traced
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
 
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
 		o.BaseEndpoint = aws.String(endpoint)
@@ -102,8 +133,247 @@ func (r *R2Service) GeneratePresignedDownloadURL(ctx context.Context, key string
 	return req.URL, nil
 }
 
+// Ping checks that the bucket is reachable with the configured credentials
+func (r *R2Service) Ping(ctx context.Context) error {
+	_, err := r.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucketName),
+	})
+	return unavailable(err)
+}
+
+// GetObjectRange opens the inclusive byte range [start, end] of an object
+func (r *R2Service) GetObjectRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	out, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	})
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	return out.Body, nil
+}
+
+// GetObject opens an object for reading
+func (r *R2Service) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, unavailable(err)
+	}
+	return out.Body, nil
+}
+
+// UploadMultipart uploads everything read from body to key using a multipart
+// upload, so objects larger than a single PUT allows can be stored
+func (r *R2Service) UploadMultipart(ctx context.Context, key string, contentType string, body io.Reader) error {
+	created, err := r.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(r.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", unavailable(err))
+	}
+
+	parts, err := r.uploadParts(ctx, key, created.UploadId, body)
+	if err != nil {
+		_, _ = r.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(r.bucketName),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		})
+		return err
+	}
+
+	_, err = r.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(r.bucketName),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", unavailable(err))
+	}
+	return nil
+}
+
+func (r *R2Service) uploadParts(ctx context.Context, key string, uploadID *string, body io.Reader) ([]types.CompletedPart, error) {
+	var parts []types.CompletedPart
+	buf := make([]byte, MultipartPartSize)
+
+	for partNumber := int32(1); ; partNumber++ {
+		n, readErr := io.ReadFull(body, buf)
+		if n == 0 && readErr != nil && partNumber > 1 {
+			break
+		}
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("failed to read part %d: %w", partNumber, readErr)
+		}
+
+		out, err := r.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(r.bucketName),
+			Key:        aws.String(key),
+			UploadId:   uploadID,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, unavailable(err))
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:       out.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	return parts, nil
+}
+
+// ListObjectKeys returns every key under prefix
+func (r *R2Service) ListObjectKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, unavailable(err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+
+	return keys, nil
+}
+
+// deleteObjectsBatchSize is the maximum number of keys per DeleteObjects call
+const deleteObjectsBatchSize = 1000
+
+// deleteObjectsConcurrency bounds the DeleteObjects calls in flight
+const deleteObjectsConcurrency = 4
+
+// DeleteObjects permanently deletes the given keys in batches, several
+// batches at a time
+func (r *R2Service) DeleteObjects(ctx context.Context, keys []string) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(deleteObjectsConcurrency)
+	for start := 0; start < len(keys); start += deleteObjectsBatchSize {
+		batch := keys[start:min(start+deleteObjectsBatchSize, len(keys))]
+		g.Go(func() error {
+			return r.deleteBatch(gctx, batch)
+		})
+	}
+	return g.Wait()
+}
+
+func (r *R2Service) deleteBatch(ctx context.Context, keys []string) error {
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+
+	out, err := r.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(r.bucketName),
+		Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return unavailable(err)
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("failed to delete %d objects, first %s: %s",
+			len(out.Errors), aws.ToString(out.Errors[0].Key), aws.ToString(out.Errors[0].Message))
+	}
+	return nil
+}
+
+// unavailable marks errors that outlasted the retry policy as
+// storage.ErrUnavailable, keeping the cause
+func unavailable(err error) error {
+	var exhausted *retry.MaxAttemptsError
+	var throttled ratelimit.QuotaExceededError
+	if errors.As(err, &exhausted) || errors.As(err, &throttled) {
+		return fmt.Errorf("%w: %w", storage.ErrUnavailable, err)
+	}
+	return err
+}
+
 func (r *R2Service) GetPublicURL(key string) string {
 	// Remove leading slash if present
 	key = strings.TrimPrefix(key, "/")
 	return fmt.Sprintf("%s/%s", strings.TrimSuffix(r.publicDomain, "/"), key)
 }
+
+// LifecycleRules configures what the bucket cleans up on its own
+type LifecycleRules struct {
+	// ArchivePrefix and ArchiveExpiryDays expire archive zips after a number of days
+	ArchivePrefix     string
+	ArchiveExpiryDays int32
+	// AbortMultipartDays aborts multipart uploads left incomplete this long
+	AbortMultipartDays int32
+}
+
+// PutLifecycleRules adds rules to the bucket's lifecycle configuration,
+// replacing earlier versions of them by rule ID. Rules that others added to
+// the bucket are kept, as the configuration can only be put as a whole.
+func (r *R2Service) PutLifecycleRules(ctx context.Context, rules LifecycleRules) error {
+	ours := []types.LifecycleRule{
+		{
+			ID:         aws.String("expire-archives"),
+			Status:     types.ExpirationStatusEnabled,
+			Filter:     &types.LifecycleRuleFilter{Prefix: aws.String(rules.ArchivePrefix)},
+			Expiration: &types.LifecycleExpiration{Days: aws.Int32(rules.ArchiveExpiryDays)},
+		},
+		{
+			ID:     aws.String("abort-incomplete-multipart-uploads"),
+			Status: types.ExpirationStatusEnabled,
+			Filter: &types.LifecycleRuleFilter{Prefix: aws.String("")},
+			AbortIncompleteMultipartUpload: &types.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int32(rules.AbortMultipartDays),
+			},
+		},
+	}
+
+	var existing []types.LifecycleRule
+	current, err := r.client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(r.bucketName),
+	})
+	var apiErr smithy.APIError
+	switch {
+	case err == nil:
+		existing = current.Rules
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration":
+	default:
+		return fmt.Errorf("failed to get lifecycle rules: %w", err)
+	}
+
+	merged := make([]types.LifecycleRule, 0, len(existing)+len(ours))
+	for _, rule := range existing {
+		replaced := false
+		for _, own := range ours {
+			if aws.ToString(rule.ID) == aws.ToString(own.ID) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, rule)
+		}
+	}
+	merged = append(merged, ours...)
+
+	_, err = r.client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(r.bucketName),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: merged},
+	})
+	return err
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CircuitOpenError is returned without calling the store while the breaker
+// is open. It matches ErrUnavailable.
+type CircuitOpenError struct {
+	// RetryAfter is how long until calls are let through again
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v: circuit open for another %v", ErrUnavailable, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// Breaker is a Storage that stops calling the store after threshold calls in
+// a row failed with ErrUnavailable or timed out, so that requests fail at once instead of
+// each waiting out every retry. Presigned URLs are refused too while it is
+// open, as browsers could not use them anyway. After cooldown a single call
+// is let through as a probe; its success closes the breaker again.
+type Breaker struct {
+	Storage
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// NewBreaker wraps store in a circuit breaker
+func NewBreaker(store Storage, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{Storage: store, threshold: threshold, cooldown: cooldown}
+}
+
+// check returns a CircuitOpenError while the breaker is open
+func (b *Breaker) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return &CircuitOpenError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// call runs fn unless the breaker is open and records its outcome
+func (b *Breaker) call(fn func() error) error {
+	b.mu.Lock()
+	if wait := time.Until(b.openUntil); wait > 0 {
+		b.mu.Unlock()
+		return &CircuitOpenError{RetryAfter: wait}
+	}
+	if b.failures >= b.threshold {
+		// Half open: hold everyone else back while this call probes the store
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+	b.mu.Unlock()
+
+	err := fn()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = time.Now().Add(b.cooldown)
+		}
+	case errors.Is(err, context.Canceled):
+		// The caller gave up; that says nothing about the store
+	default:
+		// Any answer, even a missing key, shows the store is back
+		b.failures = 0
+		b.openUntil = time.Time{}
+	}
+	return err
+}
+
+func (b *Breaker) GeneratePresignedUploadURL(ctx context.Context, key string, contentType string, duration time.Duration) (string, error) {
+	if err := b.check(); err != nil {
+		return "", err
+	}
+	return b.Storage.GeneratePresignedUploadURL(ctx, key, contentType, duration)
+}
+
+func (b *Breaker) GeneratePresignedDeleteURL(ctx context.Context, key string, duration time.Duration) (string, error) {
+	if err := b.check(); err != nil {
+		return "", err
+	}
+	return b.Storage.GeneratePresignedDeleteURL(ctx, key, duration)
+}
+
+func (b *Breaker) GeneratePresignedDownloadURL(ctx context.Context, key string, duration time.Duration) (string, error) {
+	if err := b.check(); err != nil {
+		return "", err
+	}
+	return b.Storage.GeneratePresignedDownloadURL(ctx, key, duration)
+}
+
+func (b *Breaker) Ping(ctx context.Context) error {
+	return b.call(func() error {
+		return b.Storage.Ping(ctx)
+	})
+}
+
+func (b *Breaker) GetObjectRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := b.call(func() (err error) {
+		body, err = b.Storage.GetObjectRange(ctx, key, start, end)
+		return err
+	})
+	return body, err
+}
+
+func (b *Breaker) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := b.call(func() (err error) {
+		body, err = b.Storage.GetObject(ctx, key)
+		return err
+	})
+	return body, err
+}
+
+func (b *Breaker) UploadMultipart(ctx context.Context, key string, contentType string, body io.Reader) error {
+	return b.call(func() error {
+		return b.Storage.UploadMultipart(ctx, key, contentType, body)
+	})
+}
+
+func (b *Breaker) ListObjectKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := b.call(func() (err error) {
+		keys, err = b.Storage.ListObjectKeys(ctx, prefix)
+		return err
+	})
+	return keys, err
+}
+
+func (b *Breaker) DeleteObjects(ctx context.Context, keys []string) error {
+	return b.call(func() error {
+		return b.Storage.DeleteObjects(ctx, keys)
+	})
+}
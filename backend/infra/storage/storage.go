@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrUnavailable is returned when the store keeps failing with transient
+// errors after every retry
+var ErrUnavailable = errors.New("storage is temporarily unavailable")
+
+// Storage is an object store holding photos, theme images and archives.
+// Browsers upload and download objects directly through presigned URLs.
+type Storage interface {
+	GeneratePresignedUploadURL(ctx context.Context, key string, contentType string, duration time.Duration) (string, error)
+	GeneratePresignedDeleteURL(ctx context.Context, key string, duration time.Duration) (string, error)
+	GeneratePresignedDownloadURL(ctx context.Context, key string, duration time.Duration) (string, error)
+	// Ping checks that the store is reachable
+	Ping(ctx context.Context) error
+	// GetObjectRange reads the inclusive byte range [start, end] of an object
+	GetObjectRange(ctx context.Context, key string, start, end int64) (io.ReadCloser, error)
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	// UploadMultipart streams body into an object without buffering it whole
+	UploadMultipart(ctx context.Context, key string, contentType string, body io.Reader) error
+	ListObjectKeys(ctx context.Context, prefix string) ([]string, error)
+	DeleteObjects(ctx context.Context, keys []string) error
+	GetPublicURL(key string) string
+}
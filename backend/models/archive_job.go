@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ArchiveJobStatus string
+
+const (
+	ArchiveJobStatusPending   ArchiveJobStatus = "pending"
+	ArchiveJobStatusRunning   ArchiveJobStatus = "running"
+	ArchiveJobStatusCompleted ArchiveJobStatus = "completed"
+	ArchiveJobStatusFailed    ArchiveJobStatus = "failed"
+)
+
+type ArchiveJob struct {
+	ID           uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID      uuid.UUID        `json:"event_id" gorm:"type:uuid;not null;index"`
+	UploaderName *string          `json:"uploader_name,omitempty" gorm:"size:100"`
+	PhotoSetHash string           `json:"photo_set_hash" gorm:"size:64"`
+	Status       ArchiveJobStatus `json:"status" gorm:"not null;default:'pending';size:20"`
+	ObjectKey    string           `json:"object_key" gorm:"not null;size:255"`
+	PhotoCount   int              `json:"photo_count" gorm:"not null;default:0"`
+	Error        *string          `json:"error,omitempty" gorm:"type:text"`
+	NotifyEmail  *string          `json:"notify_email,omitempty" gorm:"size:255"`
+	NotifiedAt   *time.Time       `json:"notified_at,omitempty"`
+	Attempts     int              `json:"attempts" gorm:"not null;default:0"`
+	HeartbeatAt  *time.Time       `json:"-" gorm:"index"`
+	CompletedAt  *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt    time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actor types
+const (
+	AuditActorAdmin = "admin"
+	AuditActorOwner = "owner"
+	AuditActorGuest = "guest"
+)
+
+type AuditLog struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID    *uuid.UUID `json:"event_id,omitempty" gorm:"type:uuid;index"`
+	ActorType  string     `json:"actor_type" gorm:"not null;size:20;default:'admin'"`
+	Actor      string     `json:"actor" gorm:"not null;size:255"`
+	Action     string     `json:"action" gorm:"not null;size:100;index"`
+	TargetType string     `json:"target_type" gorm:"not null;size:50"`
+	TargetID   string     `json:"target_id" gorm:"not null;size:64;index"`
+	Details    *string    `json:"details,omitempty" gorm:"type:text"`
+	IPAddress  string     `json:"ip_address" gorm:"size:64"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentTerms keeps every version of an event's consent text, so accepted
+// versions recorded on sessions can be traced back to the exact wording
+type ConsentTerms struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID   uuid.UUID `json:"event_id" gorm:"type:uuid;not null;uniqueIndex:idx_consent_terms_event_version"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_consent_terms_event_version"`
+	Text      string    `json:"text" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
@@ -15,17 +15,80 @@ const (
 	EventStatusClosed   EventStatus = "closed"
 )
 
+type MediaPolicy string
+
+const (
+	MediaPolicyImages       MediaPolicy = "images"
+	MediaPolicyImagesVideos MediaPolicy = "images_videos"
+	MediaPolicyCustom       MediaPolicy = "custom"
+)
+
+type GalleryVisibility string
+
+const (
+	// GalleryVisibilityPrivate limits guests to their own uploads
+	GalleryVisibilityPrivate GalleryVisibility = "private"
+	// GalleryVisibilityGuests shows the gallery to every guest with a session
+	GalleryVisibilityGuests GalleryVisibility = "guests"
+	// GalleryVisibilityPublic also serves a watermarked gallery without a session
+	GalleryVisibilityPublic GalleryVisibility = "public"
+)
+
 type Event struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	Name        string         `json:"name" gorm:"size:255;not null"`
-	Code        string         `json:"code" gorm:"uniqueIndex;size:8;not null"`
-	Description *string        `json:"description,omitempty" gorm:"type:text"`
-	EventDate   *time.Time     `json:"event_date,omitempty" gorm:"type:date"`
-	Status      EventStatus    `json:"status" gorm:"not null;default:'active'"`
-	OwnerEmail  string         `json:"owner_email" gorm:"not null;size:255"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty"`
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name            string         `json:"name" gorm:"size:255;not null"`
+	Code            string         `json:"code" gorm:"uniqueIndex;size:16;not null"`
+	Description     *string        `json:"description,omitempty" gorm:"type:text"`
+	EventDate       *time.Time     `json:"event_date,omitempty" gorm:"type:date"`
+	Timezone        string         `json:"timezone" gorm:"size:64;not null;default:'UTC'"`
+	Status          EventStatus    `json:"status" gorm:"not null;default:'active'"`
+	OwnerEmail      string         `json:"owner_email" gorm:"not null;size:255"`
+	UserID          *uuid.UUID     `json:"user_id,omitempty" gorm:"type:uuid;index"`
+	MaxGuests       *int           `json:"max_guests,omitempty"`
+	MaxPhotos       *int           `json:"max_photos,omitempty"`
+	CloseAt         *time.Time     `json:"close_at,omitempty" gorm:"index"`
+	RetentionDays   *int           `json:"retention_days,omitempty"`
+	SessionTTLHours *int           `json:"session_ttl_hours,omitempty"`
+	GuestbookPublic bool           `json:"guestbook_public" gorm:"not null;default:false"`
+	PurgeAt         *time.Time     `json:"purge_at,omitempty" gorm:"index"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty"`
+
+	// Guest access; when AllowGuestDownloads is false, guests can browse the
+	// gallery but not fetch original files
+	AllowGuestDownloads bool              `json:"allow_guest_downloads" gorm:"not null;default:true"`
+	GalleryVisibility   GalleryVisibility `json:"gallery_visibility" gorm:"size:10;not null;default:'guests'"`
+	// RequireCaptcha makes guests pass a Turnstile challenge before joining
+	RequireCaptcha bool `json:"require_captcha" gorm:"not null;default:false"`
+
+	// Sharing terms guests must accept to join; ConsentVersion is bumped
+	// whenever the text changes, see ConsentTerms
+	ConsentText    *string `json:"consent_text,omitempty" gorm:"type:text"`
+	ConsentVersion int     `json:"consent_version" gorm:"not null;default:0"`
+
+	// Upload restrictions; AllowedMimeTypes is a comma-separated list used by MediaPolicyCustom
+	MediaPolicy      MediaPolicy `json:"media_policy" gorm:"size:20;not null;default:'images_videos'"`
+	AllowedMimeTypes *string     `json:"-" gorm:"type:text"`
+
+	// Branding of the guest landing page
+	ThemePrimaryColor *string `json:"theme_primary_color,omitempty" gorm:"size:7"`
+	ThemeWelcomeText  *string `json:"theme_welcome_text,omitempty" gorm:"type:text"`
+	ThemeLogoKey      *string `json:"theme_logo_key,omitempty" gorm:"size:255"`
+
+	// Cached full-event archive, reused while the photo set hash still matches
+	ArchiveJobID        *uuid.UUID `json:"-" gorm:"type:uuid"`
+	ArchivePhotoSetHash *string    `json:"-" gorm:"size:64"`
+
+	// Confirmed photos, hidden ones included, and their total size. They are
+	// adjusted in the transactions confirming and deleting photos so that
+	// stats and quotas read them instead of scanning the photos table.
+	PhotoCount int64 `json:"photo_count" gorm:"not null;default:0"`
+	TotalBytes int64 `json:"total_bytes" gorm:"not null;default:0"`
+
+	// Retention bookkeeping, see RetentionService
+	RetentionWarnedAt *time.Time `json:"-"`
+	PurgedAt          *time.Time `json:"purged_at,omitempty"`
 
 	Photos []Photo `json:"photos,omitempty" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleCoHost    CollaboratorRole = "co_host"
+	CollaboratorRoleModerator CollaboratorRole = "moderator"
+)
+
+type EventCollaborator struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID     uuid.UUID        `json:"event_id" gorm:"type:uuid;not null;uniqueIndex:idx_event_collaborators_event_email"`
+	Email       string           `json:"email" gorm:"not null;size:255;uniqueIndex:idx_event_collaborators_event_email"`
+	Role        CollaboratorRole `json:"role" gorm:"not null;size:20"`
+	InviteToken *string          `json:"-" gorm:"unique;size:128"`
+	AcceptedAt  *time.Time       `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
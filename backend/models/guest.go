@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Guest is a person taking part in an event. Every session and upload of the
+// guest references it, so rejoining from another device keeps their photos
+// grouped. Display names are not unique: two people may pick the same name,
+// and a name alone never joins an existing guest.
+type Guest struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID     uuid.UUID `json:"event_id" gorm:"type:uuid;not null;index:idx_guests_event_display_name"`
+	DisplayName string    `json:"display_name" gorm:"not null;size:100;index:idx_guests_event_display_name"`
+	// Fingerprint of the device the guest first joined from
+	ClientFingerprint *string   `json:"-" gorm:"size:64;index"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuestBan blocks a guest name, and optionally a client fingerprint, from
+// joining an event. One ban creates a row per fingerprint the guest used.
+type GuestBan struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID           uuid.UUID `json:"event_id" gorm:"type:uuid;not null;index"`
+	GuestName         string    `json:"guest_name" gorm:"not null;size:100"`
+	ClientFingerprint *string   `json:"-" gorm:"size:64"`
+	Reason            *string   `json:"reason,omitempty" gorm:"type:text"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
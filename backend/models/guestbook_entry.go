@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GuestbookEntry struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID   uuid.UUID      `json:"event_id" gorm:"type:uuid;not null;index"`
+	SessionID uuid.UUID      `json:"session_id" gorm:"type:uuid;not null"`
+	GuestName string         `json:"guest_name" gorm:"not null;size:100"`
+	Message   string         `json:"message" gorm:"type:text;not null"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
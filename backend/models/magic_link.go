@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLink is a single-use passwordless login link sent to an owner's email.
+// Registrations are confirmed with one too: PasswordHash and Name then hold
+// the account details, applied once the link proves the email is the owner's.
+type MagicLink struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Email     string     `json:"email" gorm:"size:255;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// Registration details, empty for sign-in links
+	PasswordHash string  `json:"-" gorm:"size:255"`
+	Name         *string `json:"-" gorm:"size:100"`
+}
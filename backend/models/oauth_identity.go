@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthIdentity links an external sign-in account (e.g. Google, Apple) to an owner
+type OAuthIdentity struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Provider  string    `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_oauth_identities_provider_subject"`
+	Subject   string    `json:"-" gorm:"size:255;not null;uniqueIndex:idx_oauth_identities_provider_subject"`
+	Email     string    `json:"email" gorm:"size:255"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+}
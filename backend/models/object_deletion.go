@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObjectDeletion queues a storage object whose record was deleted. Objects
+// are removed in batches after the deleting transaction commits, and stay
+// queued until storage confirms the deletion.
+type ObjectDeletion struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ObjectKey string    `json:"object_key" gorm:"not null;size:255"`
+	// NextAttemptAt is pushed out while a worker holds the entry
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"not null;index"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -11,9 +11,17 @@ type Photo struct {
 	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	EventID      uuid.UUID      `json:"event_id" gorm:"type:uuid;not null;index"`
 	UploaderName string         `json:"uploader_name" gorm:"not null;size:100;index"`
+	GuestID      *uuid.UUID     `json:"guest_id,omitempty" gorm:"type:uuid;index"`
 	ObjectKey    string         `json:"object_key" gorm:"not null;size:255;index"`
 	Size         int64          `json:"file_size" gorm:"not null"`
 	MimeType     string         `json:"mime_type" gorm:"not null;size:50"`
+	Width        *int           `json:"width,omitempty"`
+	Height       *int           `json:"height,omitempty"`
+	Caption      *string        `json:"caption,omitempty" gorm:"type:text"`
+	TakenAt      *time.Time     `json:"taken_at,omitempty" gorm:"index"`
+	ConfirmedAt  *time.Time     `json:"confirmed_at,omitempty" gorm:"index"`
+	BatchID      *uuid.UUID     `json:"batch_id,omitempty" gorm:"type:uuid;index"`
+	HiddenAt     *time.Time     `json:"hidden_at,omitempty" gorm:"index"`
 	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty"`
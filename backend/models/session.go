@@ -7,15 +7,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// Session scopes limit what a session token may be used for
+const (
+	SessionScopeUpload   = "upload"
+	SessionScopeView     = "view"
+	SessionScopeDownload = "download"
+	// SessionScopeModerate also shows hidden photos and other guests' uploads
+	SessionScopeModerate = "moderate"
+)
+
+// DefaultSessionScopes are granted to guests joining with the event code
+const DefaultSessionScopes = "upload,view,download"
+
 type Session struct {
 	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	EventID      uuid.UUID      `json:"event_id" gorm:"type:uuid;not null;index"`
 	GuestName    string         `json:"guest_name" gorm:"not null;size:255;index"`
+	GuestID      *uuid.UUID     `json:"guest_id,omitempty" gorm:"type:uuid;index"`
 	SessionToken string         `json:"session_token" gorm:"not null;unique;size:128"`
 	ExpiresAt    time.Time      `json:"expires_at" gorm:"not null;index"`
+	Scopes       string         `json:"scopes" gorm:"size:100;not null;default:'upload,view,download'"`
 	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty"`
 
+	// Hash of the client's device ID, used to enforce guest bans
+	ClientFingerprint *string `json:"-" gorm:"size:64;index"`
+
+	// Consent version the guest accepted when joining, if the event asks for consent
+	ConsentVersion    *int       `json:"consent_version,omitempty"`
+	ConsentAcceptedAt *time.Time `json:"consent_accepted_at,omitempty"`
+
+	// Client details recorded when the session is opened, for abuse investigation
+	UserAgent string `json:"-" gorm:"size:512"`
+	IPAddress string `json:"-" gorm:"size:64"`
+
 	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
 }
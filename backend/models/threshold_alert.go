@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AlertMetric string
+
+const (
+	AlertMetricPhotoCount        AlertMetric = "photo_count"
+	AlertMetricStorageBytes      AlertMetric = "storage_bytes"
+	AlertMetricPhotoQuotaPercent AlertMetric = "photo_quota_percent"
+	AlertMetricGuestQuotaPercent AlertMetric = "guest_quota_percent"
+)
+
+// ThresholdAlert notifies the owner once Metric reaches Threshold. It re-arms
+// when the value falls back below the threshold.
+type ThresholdAlert struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID     uuid.UUID   `json:"event_id" gorm:"type:uuid;not null;index"`
+	Metric      AlertMetric `json:"metric" gorm:"not null;size:50"`
+	Threshold   int64       `json:"threshold" gorm:"not null"`
+	TriggeredAt *time.Time  `json:"triggered_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
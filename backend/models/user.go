@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// User is an event owner account. Email is stored lowercased.
+type User struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Email        string    `json:"email" gorm:"uniqueIndex;size:255;not null"`
+	Name         *string   `json:"name,omitempty" gorm:"size:100"`
+	PasswordHash string    `json:"-" gorm:"size:255"`
+	// EmailVerifiedAt is set once the owner proved they receive mail at
+	// Email; only then are events created under the email linked
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// Locale is the language of emails to the owner, taken from the browser
+	// the account was created with
+	Locale    string         `json:"locale" gorm:"size:10;not null;default:ja"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	WebhookEventPhotoUploaded  = "photo.uploaded"
+	WebhookEventPhotoDeleted   = "photo.deleted"
+	WebhookEventEventClosed    = "event.closed"
+	WebhookEventSessionCreated = "session.created"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook posts signed JSON payloads to URL whenever one of EventTypes
+// happens in the event
+type Webhook struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID uuid.UUID `json:"event_id" gorm:"type:uuid;not null;index"`
+	URL     string    `json:"url" gorm:"not null;size:2048"`
+	// Secret signs every payload; it is only shown when the webhook is created
+	Secret string `json:"-" gorm:"not null;size:128"`
+	// EventTypes is a comma-separated list of webhook event types
+	EventTypes string    `json:"event_types" gorm:"not null;size:255"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// WebhookDelivery is one payload queued for a webhook, retried with backoff
+// until it is delivered or runs out of attempts
+type WebhookDelivery struct {
+	ID            uuid.UUID             `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	WebhookID     uuid.UUID             `json:"webhook_id" gorm:"type:uuid;not null;index"`
+	EventType     string                `json:"event_type" gorm:"not null;size:50"`
+	Payload       string                `json:"payload" gorm:"type:text;not null"`
+	Status        WebhookDeliveryStatus `json:"status" gorm:"not null;size:20;default:'pending';index:idx_webhook_deliveries_due"`
+	Attempts      int                   `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time             `json:"next_attempt_at" gorm:"not null;index:idx_webhook_deliveries_due"`
+	LastError     *string               `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time             `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Webhook Webhook `json:"webhook,omitempty" gorm:"foreignKey:WebhookID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+// InboundWebhook is a verified callback received from an external service.
+// DeliveryID is unique per source, so a replayed callback is rejected.
+type InboundWebhook struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Source     string    `json:"source" gorm:"not null;size:50;uniqueIndex:idx_inbound_webhooks_delivery"`
+	DeliveryID string    `json:"delivery_id" gorm:"not null;size:255;uniqueIndex:idx_inbound_webhooks_delivery"`
+	Payload    string    `json:"payload" gorm:"type:text;not null"`
+	SignedAt   time.Time `json:"signed_at" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WrapUpStep string
+
+// Wrap-up steps, executed in this order
+const (
+	WrapUpStepCloseUploads      WrapUpStep = "close_uploads"
+	WrapUpStepModerationSweep   WrapUpStep = "moderation_sweep"
+	WrapUpStepBuildArchive      WrapUpStep = "build_archive"
+	WrapUpStepNotifyOwner       WrapUpStep = "notify_owner"
+	WrapUpStepScheduleRetention WrapUpStep = "schedule_retention"
+	WrapUpStepDone              WrapUpStep = "done"
+)
+
+type WrapUpStatus string
+
+const (
+	WrapUpStatusInProgress WrapUpStatus = "in_progress"
+	WrapUpStatusCompleted  WrapUpStatus = "completed"
+	WrapUpStatusFailed     WrapUpStatus = "failed"
+)
+
+type WrapUpJob struct {
+	ID           uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EventID      uuid.UUID    `json:"event_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Step         WrapUpStep   `json:"step" gorm:"not null;size:50"`
+	Status       WrapUpStatus `json:"status" gorm:"not null;size:20;index"`
+	ArchiveJobID *uuid.UUID   `json:"archive_job_id,omitempty" gorm:"type:uuid"`
+	Attempts     int          `json:"attempts" gorm:"not null;default:0"`
+	Error        *string      `json:"error,omitempty" gorm:"type:text"`
+	LockedUntil  *time.Time   `json:"-" gorm:"index"`
+	NotifiedAt   *time.Time   `json:"notified_at,omitempty"`
+	CompletedAt  *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt    time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID;references:ID;constraint:OnDelete:CASCADE"`
+}
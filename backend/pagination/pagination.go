@@ -0,0 +1,136 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// List endpoints page with keyset cursors: a cursor holds the sort values of
+// the last row of a page, and the next page selects the rows after it. Unlike
+// offsets, pages stay stable while rows are inserted.
+
+// ErrInvalidCursor is returned for a cursor that was not produced by Encode
+// for the same listing
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ClampLimit bounds a requested page size to [1, max], using def when no
+// size was requested
+func ClampLimit(limit, def, max int) int {
+	switch {
+	case limit <= 0:
+		return def
+	case limit > max:
+		return max
+	default:
+		return limit
+	}
+}
+
+// Encode builds an opaque cursor from the sort values of the last row of a page
+func Encode(values ...any) string {
+	raw, err := json.Marshal(values)
+	if err != nil {
+		// Sort values are times, IDs, strings and numbers, which always marshal
+		panic(fmt.Sprintf("pagination: cannot encode cursor: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode reads the values of a cursor built by Encode into dst, which must be
+// pointers of the same types in the same order
+func Decode(cursor string, dst ...any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || len(values) != len(dst) {
+		return ErrInvalidCursor
+	}
+	for i, value := range values {
+		if err := json.Unmarshal(value, dst[i]); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+
+	return nil
+}
+
+// Trim cuts rows fetched with a limit of limit+1 down to limit, reporting
+// whether another page follows
+func Trim[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) > limit {
+		return rows[:limit], true
+	}
+	return rows, false
+}
+
+// Keyset describes the sort order of a listing. Columns must end with a
+// unique column, usually the primary key, so that every row has its own
+// position.
+type Keyset struct {
+	Columns []string
+	Desc    bool
+	// NullableFirst sorts NULLs in the first column last; the remaining
+	// columns must not be nullable
+	NullableFirst bool
+}
+
+// Order returns the ORDER BY clause of the listing
+func (k Keyset) Order() string {
+	direction := " ASC"
+	if k.Desc {
+		direction = " DESC"
+	}
+
+	terms := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		terms[i] = column + direction
+	}
+	if k.NullableFirst {
+		terms[0] += " NULLS LAST"
+	}
+	return strings.Join(terms, ", ")
+}
+
+// After returns the WHERE condition selecting the rows that follow the row
+// whose sort values are values
+func (k Keyset) After(values ...any) (string, []any) {
+	op := ">"
+	if k.Desc {
+		op = "<"
+	}
+
+	if !k.NullableFirst {
+		return rowCompare(k.Columns, op), values
+	}
+
+	first, rest := k.Columns[0], k.Columns[1:]
+	if isNull(values[0]) {
+		// Past the last non-NULL value only NULL rows remain
+		return first + " IS NULL AND " + rowCompare(rest, op), values[1:]
+	}
+
+	condition := fmt.Sprintf("(%s %s ? OR (%s = ? AND %s) OR %s IS NULL)", first, op, first, rowCompare(rest, op), first)
+	args := append([]any{values[0], values[0]}, values[1:]...)
+	return condition, args
+}
+
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// rowCompare compares columns as a row, e.g. (created_at, id) < (?, ?)
+func rowCompare(columns []string, op string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, placeholders)
+}
@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"snapShare/config"
+	"snapShare/handlers"
+	"snapShare/infra/localfs"
+	"snapShare/models"
+	"snapShare/services"
+)
+
+// Handlers are the HTTP handlers served by the API
+type Handlers struct {
+	Auth           *handlers.AuthHandler
+	Session        *handlers.SessionHandler
+	Event          *handlers.EventHandler
+	Photo          *handlers.PhotoHandler
+	GalleryStream  *handlers.GalleryStreamHandler
+	Archive        *handlers.ArchiveHandler
+	Metrics        *handlers.MetricsHandler
+	Admin          *handlers.AdminHandler
+	WrapUp         *handlers.WrapUpHandler
+	Collaborator   *handlers.CollaboratorHandler
+	Stats          *handlers.StatsHandler
+	Analytics      *handlers.AnalyticsHandler
+	Theme          *handlers.ThemeHandler
+	Guestbook      *handlers.GuestbookHandler
+	Ban            *handlers.BanHandler
+	Alert          *handlers.AlertHandler
+	Webhook        *handlers.WebhookHandler
+	Audit          *handlers.AuditHandler
+	GraphQL        *handlers.GraphQLHandler
+	Consent        *handlers.ConsentHandler
+	SignOut        *handlers.SignOutHandler
+	Public         *handlers.PublicHandler
+	Health         *handlers.HealthHandler
+	InboundWebhook *handlers.InboundWebhookHandler
+}
+
+// Register wires the API routes with their auth groups and rate limits.
+// localStorage serves stored objects when the local storage provider is used.
+func Register(e *echo.Echo, cfg *config.Config, h Handlers, collaboratorService *services.CollaboratorService, localStorage *localfs.LocalStorage) {
+	// Routes. Every API request counts against the global, per-IP and
+	// per-token limits; some routes have their own limits on top.
+	routeRateLimits := make(map[string]handlers.RateLimit, len(cfg.RouteRateLimits))
+	for route, limit := range cfg.RouteRateLimits {
+		routeRateLimits[route] = handlers.RateLimit(limit)
+	}
+	api := e.Group("/api/"+handlers.CurrentAPIVersion,
+		handlers.GlobalRateLimiter(handlers.RateLimit(cfg.GlobalRateLimit)),
+		handlers.IPRateLimiter(handlers.RateLimit(cfg.IPRateLimit)),
+		handlers.TokenRateLimiter(handlers.RateLimit(cfg.TokenRateLimit)),
+		handlers.RouteRateLimiter(routeRateLimits),
+	)
+
+	// Owner account routes
+	ownerAuth := h.Auth.OwnerAuthMiddleware()
+	// Registration and login links are emailed, so throttle them per client IP:
+	// 3 at once, then one every 20s
+	magicLinkLimiter := handlers.IPRateLimiter(handlers.RateLimit{PerMinute: 3, Burst: 3})
+	api.POST("/auth/register", h.Auth.Register, magicLinkLimiter)
+	api.POST("/auth/login", h.Auth.Login)
+	api.POST("/auth/magic-link", h.Auth.SendMagicLink, magicLinkLimiter)
+	api.POST("/auth/magic-link/verify", h.Auth.VerifyMagicLink)
+	api.GET("/auth/oauth/:provider", h.Auth.StartOAuth)
+	api.GET("/auth/oauth/:provider/callback", h.Auth.OAuthCallback)
+	api.POST("/auth/oauth/:provider/callback", h.Auth.OAuthCallback)
+	api.POST("/auth/refresh", h.Auth.Refresh)
+	api.POST("/auth/logout", h.Auth.Logout)
+	api.GET("/auth/me", h.Auth.GetMe, ownerAuth)
+	api.GET("/owners/me/overview", h.Analytics.GetOwnerOverview, ownerAuth)
+
+	// Owner dashboard queries over events, photos and stats in one request
+	api.GET("/graphql", h.GraphQL.Query, ownerAuth)
+	api.POST("/graphql", h.GraphQL.Query, ownerAuth)
+
+	// Session routes
+	api.POST("/sessions", h.Session.CreateSession,
+		handlers.IPRateLimiter(handlers.RateLimit{PerMinute: cfg.SessionRateLimit, Burst: cfg.SessionRateBurst}))
+	api.POST("/sessions/refresh", h.Session.RefreshSession)
+	api.DELETE("/sessions", h.Session.RevokeSession)
+	// Signs an owner or guest out on every device
+	api.DELETE("/sessions/all", h.SignOut.SignOutAll)
+	api.GET("/sessions/:token", h.Session.ValidateSession)
+
+	// Event routes
+	api.GET("/events", h.Event.GetEventsByOwner, ownerAuth)
+	api.POST("/events", h.Event.CreateEvent, ownerAuth)
+	api.GET("/events/:code", h.Event.GetEventByCode)
+	// Upload progress and slideshows are for the owner, collaborators and the event's guests
+	eventViewer := h.Session.EventViewerMiddleware(collaboratorService)
+	api.GET("/events/:id/uploading", h.Photo.GetInFlightUploads, eventViewer)
+	api.GET("/events/:id/slideshow", h.Photo.GetSlideshow, eventViewer)
+	api.GET("/events/:id/stream", h.GalleryStream.StreamGallery, eventViewer)
+
+	// Event management routes - require the owner or a collaborator. Every
+	// collaborator may view; moderators may also moderate, co-hosts manage too.
+	// Deleting the event, webhooks and collaborators are left to the owner.
+	eventAPI := api.Group("/events/:id", ownerAuth, handlers.EventAccessMiddleware(collaboratorService, services.EventCapabilityView))
+	canModerate := handlers.EventAccessMiddleware(collaboratorService, services.EventCapabilityModerate)
+	canManage := handlers.EventAccessMiddleware(collaboratorService, services.EventCapabilityManage)
+	ownerOnly := handlers.EventOwnerMiddleware(collaboratorService)
+	// GET /events/:id would clash with the guest lookup by code
+	eventAPI.GET("/details", h.Event.GetEventByID)
+	eventAPI.PUT("", h.Event.UpdateEvent, canManage)
+	eventAPI.DELETE("", h.Event.DeleteEvent, ownerOnly)
+	eventAPI.POST("/close", h.Event.CloseEvent, canManage)
+	eventAPI.GET("/photos", h.Photo.GetPhotosByEvent)
+	eventAPI.POST("/photos/delete", h.Photo.DeleteBulkPhotos, canModerate)
+	eventAPI.GET("/guests", h.Session.GetGuestsByEvent)
+	eventAPI.POST("/duplicate", h.Event.DuplicateEvent, canManage)
+	eventAPI.POST("/reopen", h.Event.ReopenEvent, canManage)
+	eventAPI.PUT("/theme", h.Theme.UpdateTheme, canManage)
+	eventAPI.POST("/theme/logo-upload-url", h.Theme.GenerateLogoUploadURL, canManage)
+	eventAPI.GET("/qr", h.Event.GetEventQRCode)
+	eventAPI.GET("/stats", h.Stats.GetEventStats)
+	eventAPI.GET("/stats/uploaders", h.Stats.GetUploaderStats)
+	eventAPI.GET("/guestbook", h.Guestbook.GetGuestbookByEvent)
+	eventAPI.DELETE("/guestbook/:entry_id", h.Guestbook.DeleteEntry, canModerate)
+	eventAPI.GET("/sessions", h.Session.GetSessionsByEvent)
+	eventAPI.POST("/sessions", h.Session.IssueSession, canManage)
+	eventAPI.DELETE("/sessions/:session_id", h.Session.RevokeEventSession, canModerate)
+	eventAPI.DELETE("/sessions/:session_id/device", h.Session.RevokeDevice, canModerate)
+	eventAPI.GET("/bans", h.Ban.GetBansByEvent)
+	eventAPI.POST("/bans", h.Ban.BanGuest, canModerate)
+	eventAPI.DELETE("/bans/:guest_name", h.Ban.UnbanGuest, canModerate)
+	eventAPI.GET("/audit-log", h.Audit.GetAuditLogByEvent, canManage)
+	eventAPI.GET("/consents", h.Consent.ExportConsents, canManage)
+	eventAPI.GET("/alerts", h.Alert.GetAlertsByEvent, canManage)
+	eventAPI.POST("/alerts", h.Alert.CreateAlert, canManage)
+	eventAPI.DELETE("/alerts/:alert_id", h.Alert.DeleteAlert, canManage)
+	eventAPI.GET("/webhooks", h.Webhook.GetWebhooksByEvent, ownerOnly)
+	eventAPI.POST("/webhooks", h.Webhook.CreateWebhook, ownerOnly)
+	eventAPI.DELETE("/webhooks/:webhook_id", h.Webhook.DeleteWebhook, ownerOnly)
+	eventAPI.POST("/download", h.Archive.StartArchive, canManage)
+	eventAPI.GET("/download/status/:job_id", h.Archive.GetArchiveStatus, canManage)
+	eventAPI.GET("/download/estimate", h.Archive.EstimateArchive, canManage)
+	eventAPI.POST("/export", h.Archive.StartExport, canManage)
+	eventAPI.POST("/wrap-up", h.WrapUp.StartWrapUp, canManage)
+	eventAPI.GET("/wrap-up", h.WrapUp.GetWrapUp, canManage)
+	eventAPI.GET("/collaborators", h.Collaborator.GetCollaboratorsByEvent, ownerOnly)
+	eventAPI.POST("/collaborators", h.Collaborator.InviteCollaborator, ownerOnly)
+	eventAPI.DELETE("/collaborators/:collaborator_id", h.Collaborator.RemoveCollaborator, ownerOnly)
+
+	// Collaborator routes
+	api.POST("/collaborators/accept", h.Collaborator.AcceptInvitation)
+
+	// Photo routes (using actual handler methods) - require authentication
+	photoAPI := api.Group("/photos", h.Session.AuthMiddleware())
+	canUpload := handlers.RequireScope(models.SessionScopeUpload)
+	canDownload := handlers.RequireScope(models.SessionScopeDownload)
+	canView := handlers.RequireScope(models.SessionScopeView)
+	// Single and bulk upload URLs count against the same limits
+	issueUploadURLs := []echo.MiddlewareFunc{
+		canUpload,
+		handlers.IPRateLimiter(handlers.RateLimit{PerMinute: cfg.UploadURLIPRateLimit, Burst: cfg.UploadURLRateBurst}),
+		handlers.SessionRateLimiter(handlers.RateLimit{PerMinute: cfg.UploadURLSessionRateLimit, Burst: cfg.UploadURLRateBurst}),
+	}
+	photoAPI.GET("", h.Photo.GetPhotosByEvent, canView)
+	photoAPI.POST("/upload-url", h.Photo.GenerateUploadURL, issueUploadURLs...)
+	photoAPI.POST("/bulk-upload-urls", h.Photo.GenerateBulkUploadURLs, issueUploadURLs...)
+	photoAPI.POST("/confirm/:id", h.Photo.ConfirmUpload, canUpload)
+	photoAPI.POST("/confirm", h.Photo.ConfirmBulkUpload, canUpload)
+	photoAPI.POST("/batch-get", h.Photo.BatchGetPhotos, canView)
+	photoAPI.POST("/archive", h.Archive.StartMyArchive, canDownload)
+	photoAPI.GET("/archive/:job_id", h.Archive.GetMyArchiveStatus, canDownload)
+	photoAPI.GET("/:id", h.Photo.GetPhoto, canView)
+	photoAPI.DELETE("/:id", h.Photo.DeletePhoto)
+
+	// Guestbook routes - require authentication
+	guestbookAPI := api.Group("/guestbook", h.Session.AuthMiddleware())
+	guestbookAPI.POST("", h.Guestbook.CreateEntry)
+	guestbookAPI.GET("", h.Guestbook.GetMyGuestbook)
+
+	// Error codes for SDK generators and clients
+	metaHandler := handlers.NewMetaHandler()
+	api.GET("/meta/errors", metaHandler.GetErrorCodes)
+
+	// Signed callbacks from integrations; the signature authenticates them
+	api.POST("/webhooks/inbound/:source", h.InboundWebhook.ReceiveWebhook)
+
+	// Public gallery routes - no session, rate limited per client IP
+	publicAPI := api.Group("/public", handlers.IPRateLimiter(handlers.RateLimit{PerMinute: 300, Burst: 5}))
+	publicAPI.GET("/events/:code/photos", h.Public.GetPublicGallery)
+	publicAPI.GET("/events/:code/photos/:photo_id/image", h.Public.GetPublicPhotoImage)
+
+	// Admin routes - require an admin API key; also used by cron jobs
+	adminAPI := api.Group("/admin", handlers.AdminAuthMiddleware(cfg.AdminAPIKeys))
+	adminAPI.POST("/sessions/cleanup", h.Session.CleanupExpiredSessions)
+	adminAPI.POST("/photos/:id/reassign-uploader", h.Admin.ReassignUploader)
+	adminAPI.POST("/photos/:id/move", h.Admin.MovePhoto)
+	adminAPI.POST("/photos/:id/reprocess", h.Admin.ReprocessPhoto)
+	adminAPI.POST("/batches/:id/expire", h.Admin.ExpireBatch)
+
+	// Health checks: liveness only proves the process serves requests,
+	// readiness also requires the database and object storage to be reachable
+	e.GET("/healthz", h.Health.Liveness)
+	e.GET("/readyz", h.Health.Readiness)
+	e.GET("/version", h.Health.Version)
+
+	// Metrics - labelled with event IDs, so scrapers need an admin API key
+	e.GET("/metrics", h.Metrics.GetMetrics, handlers.AdminAuthMiddleware(cfg.AdminAPIKeys))
+
+	// API documentation, generated from the routes and handler DTOs
+	openAPIHandler := handlers.NewOpenAPIHandler(e, "SnapShare API", "1.0.0")
+	e.GET("/api/docs", openAPIHandler.GetSwaggerUI)
+	e.GET("/api/docs/openapi.json", openAPIHandler.GetSpec)
+
+	// Objects of the local storage provider; uploads and deletes need a presigned URL
+	if localStorage != nil {
+		e.Any("/storage/*", echo.WrapHandler(http.StripPrefix("/storage", localStorage)))
+	}
+}
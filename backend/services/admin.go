@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"snapShare/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdminService implements audited data repairs for support staff
+type AdminService struct {
+	db           *gorm.DB
+	photoService *PhotoService
+	auditService *AuditService
+}
+
+func NewAdminService(db *gorm.DB, photoService *PhotoService, auditService *AuditService) *AdminService {
+	return &AdminService{
+		db:           db,
+		photoService: photoService,
+		auditService: auditService,
+	}
+}
+
+// ReassignUploader changes the uploader name recorded on a photo
+func (s *AdminService) ReassignUploader(ctx context.Context, actor AuditActor, photoID uuid.UUID, uploaderName, reason string) (*models.Photo, error) {
+	var photo models.Photo
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&photo, photoID).Error; err != nil {
+			return fmt.Errorf("photo not found: %w", err)
+		}
+
+		guestID, err := guestIDByName(tx, photo.EventID, uploaderName)
+		if err != nil {
+			return err
+		}
+
+		previous := photo.UploaderName
+		if err := tx.Model(&photo).Updates(map[string]any{
+			"uploader_name": uploaderName,
+			"guest_id":      guestID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to reassign uploader: %w", err)
+		}
+
+		return s.auditService.Record(ctx, tx, AuditEntry{
+			EventID:    &photo.EventID,
+			Actor:      actor,
+			Action:     "admin.photo.reassign_uploader",
+			TargetType: "photo",
+			TargetID:   photo.ID.String(),
+			Details:    map[string]any{"from": previous, "to": uploaderName, "reason": reason},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+// MovePhotoToEvent fixes a photo that was recorded against the wrong event.
+// The object is copied under the new event's prefix and the old one queued
+// for deletion, so that purging either event leaves the photo consistent.
+func (s *AdminService) MovePhotoToEvent(ctx context.Context, actor AuditActor, photoID, eventID uuid.UUID, reason string) (*models.Photo, error) {
+	var photo models.Photo
+	if err := s.db.WithContext(ctx).First(&photo, photoID).Error; err != nil {
+		return nil, fmt.Errorf("photo not found: %w", err)
+	}
+	if photo.EventID == eventID {
+		return &photo, nil
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+
+	previousKey := photo.ObjectKey
+	objectKey, err := photoObjectKey(eventID, photo.ID, path.Ext(previousKey))
+	if err != nil {
+		return nil, err
+	}
+	if err := copyObject(ctx, s.photoService.storage, previousKey, objectKey, photo.MimeType); err != nil {
+		return nil, fmt.Errorf("failed to copy photo object: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Re-read under lock in case the photo changed during the copy
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&photo, photoID).Error; err != nil {
+			return fmt.Errorf("photo not found: %w", err)
+		}
+		if photo.ObjectKey != previousKey {
+			return fmt.Errorf("photo changed while it was being moved")
+		}
+
+		// Guests belong to one event; re-link to the guest of the same name, if any
+		guestID, err := guestIDByName(tx, eventID, photo.UploaderName)
+		if err != nil {
+			return err
+		}
+
+		previous := photo.EventID
+		if err := tx.Model(&photo).Updates(map[string]any{
+			"event_id":   eventID,
+			"guest_id":   guestID,
+			"object_key": objectKey,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to move photo: %w", err)
+		}
+
+		// Confirmed photos count against the event they are in
+		if photo.ConfirmedAt != nil {
+			if err := adjustEventCounters(tx, previous, -1, -photo.Size); err != nil {
+				return err
+			}
+			if err := adjustEventCounters(tx, eventID, 1, photo.Size); err != nil {
+				return err
+			}
+		}
+
+		if err := queueObjectDeletions(tx, previousKey); err != nil {
+			return err
+		}
+
+		if err := InvalidateEventArchive(tx, previous, eventID); err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, tx, AuditEntry{
+			EventID:    &eventID,
+			Actor:      actor,
+			Action:     "admin.photo.move_event",
+			TargetType: "photo",
+			TargetID:   photo.ID.String(),
+			Details:    map[string]any{"from": previous.String(), "to": eventID.String(), "reason": reason},
+		})
+	})
+	if err != nil {
+		// The copy is not referenced by any photo; remove it
+		if queueErr := queueObjectDeletions(s.db.WithContext(ctx), objectKey); queueErr != nil {
+			slog.ErrorContext(ctx, "failed to queue deletion of moved photo copy", "object_key", objectKey, "error", queueErr)
+		}
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+// ReprocessPhoto re-extracts derived metadata (currently dimensions) from the stored object
+func (s *AdminService) ReprocessPhoto(ctx context.Context, actor AuditActor, photoID uuid.UUID, reason string) (*models.Photo, error) {
+	var photo models.Photo
+	if err := s.db.WithContext(ctx).First(&photo, photoID).Error; err != nil {
+		return nil, fmt.Errorf("photo not found: %w", err)
+	}
+
+	width, height, err := s.photoService.pooledImageDimensions(ctx, photo.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reprocess photo: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&photo).Updates(map[string]any{"width": width, "height": height}).Error; err != nil {
+			return fmt.Errorf("failed to update photo: %w", err)
+		}
+
+		return s.auditService.Record(ctx, tx, AuditEntry{
+			EventID:    &photo.EventID,
+			Actor:      actor,
+			Action:     "admin.photo.reprocess",
+			TargetType: "photo",
+			TargetID:   photo.ID.String(),
+			Details:    map[string]any{"width": width, "height": height, "reason": reason},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+// ExpireBatch removes the unconfirmed photos of a stuck bulk upload batch,
+// along with any objects that were uploaded without being confirmed
+func (s *AdminService) ExpireBatch(ctx context.Context, actor AuditActor, batchID uuid.UUID, reason string) (int64, error) {
+	var expired int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted, err := deletePhotos(tx, "batch_id = ? AND confirmed_at IS NULL", batchID)
+		if err != nil {
+			return fmt.Errorf("failed to expire batch: %w", err)
+		}
+		expired = int64(len(deleted))
+
+		return s.auditService.Record(ctx, tx, AuditEntry{
+			Actor:      actor,
+			Action:     "admin.batch.expire",
+			TargetType: "batch",
+			TargetID:   batchID.String(),
+			Details:    map[string]any{"expired_photos": expired, "reason": reason},
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"snapShare/i18n"
+	"snapShare/infra/mail"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AlertService emails owners when an event crosses a configured threshold,
+// so they can raise a quota before uploads start failing
+type AlertService struct {
+	db     *gorm.DB
+	mailer mail.Mailer
+}
+
+func NewAlertService(db *gorm.DB, mailer mail.Mailer) *AlertService {
+	return &AlertService{
+		db:     db,
+		mailer: mailer,
+	}
+}
+
+// eventUsage is the current value of every alert metric of an event
+type eventUsage struct {
+	EventID      uuid.UUID
+	PhotoCount   int64
+	StorageBytes int64
+	GuestCount   int64
+}
+
+// CreateAlert adds a threshold alert to an event
+func (s *AlertService) CreateAlert(ctx context.Context, eventID uuid.UUID, metric models.AlertMetric, threshold int64) (*models.ThresholdAlert, error) {
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+
+	alert := &models.ThresholdAlert{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Metric:    metric,
+		Threshold: threshold,
+	}
+	if err := s.db.WithContext(ctx).Create(alert).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// GetAlertsByEvent lists the alerts configured for an event
+func (s *AlertService) GetAlertsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.ThresholdAlert, error) {
+	var alerts []models.ThresholdAlert
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// DeleteAlert removes an alert from an event
+func (s *AlertService) DeleteAlert(ctx context.Context, eventID, alertID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", alertID, eventID).Delete(&models.ThresholdAlert{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete alert: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("alert not found")
+	}
+
+	return nil
+}
+
+// Run checks alerts every interval until ctx is cancelled
+func (s *AlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "alerts", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "alert check failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick evaluates every alert against the current usage of its event
+func (s *AlertService) Tick(ctx context.Context) error {
+	var alerts []models.ThresholdAlert
+	if err := s.db.WithContext(ctx).Preload("Event").Find(&alerts).Error; err != nil {
+		return fmt.Errorf("failed to get alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	usage, err := s.collectUsage(alerts)
+	if err != nil {
+		return err
+	}
+
+	for i := range alerts {
+		alert := &alerts[i]
+		value, ok := metricValue(alert, usage[alert.EventID])
+		if !ok {
+			continue
+		}
+
+		switch {
+		case value >= alert.Threshold && alert.TriggeredAt == nil:
+			if err := s.notify(ctx, alert, value); err != nil {
+				slog.ErrorContext(ctx, "failed to send alert", "alert_id", alert.ID, "error", err)
+				continue
+			}
+			if err := s.db.WithContext(ctx).Model(alert).Update("triggered_at", time.Now()).Error; err != nil {
+				slog.ErrorContext(ctx, "failed to record alert trigger", "alert_id", alert.ID, "error", err)
+			}
+		case value < alert.Threshold && alert.TriggeredAt != nil:
+			if err := s.db.WithContext(ctx).Model(alert).Update("triggered_at", nil).Error; err != nil {
+				slog.ErrorContext(ctx, "failed to re-arm alert", "alert_id", alert.ID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *AlertService) collectUsage(alerts []models.ThresholdAlert) (map[uuid.UUID]eventUsage, error) {
+	eventIDs := make([]uuid.UUID, 0, len(alerts))
+	for _, alert := range alerts {
+		eventIDs = append(eventIDs, alert.EventID)
+	}
+
+	var photoUsage []eventUsage
+	if err := s.db.Model(&models.Event{}).
+		Select("id AS event_id, photo_count, total_bytes AS storage_bytes").
+		Where("id IN ?", eventIDs).
+		Scan(&photoUsage).Error; err != nil {
+		return nil, fmt.Errorf("failed to collect photo usage: %w", err)
+	}
+
+	var guestUsage []eventUsage
+	if err := s.db.Model(&models.Session{}).
+		Select("event_id, COUNT(DISTINCT guest_name) AS guest_count").
+		Where("event_id IN ?", eventIDs).
+		Group("event_id").
+		Scan(&guestUsage).Error; err != nil {
+		return nil, fmt.Errorf("failed to collect guest usage: %w", err)
+	}
+
+	usage := map[uuid.UUID]eventUsage{}
+	for _, u := range photoUsage {
+		usage[u.EventID] = u
+	}
+	for _, u := range guestUsage {
+		current := usage[u.EventID]
+		current.GuestCount = u.GuestCount
+		usage[u.EventID] = current
+	}
+
+	return usage, nil
+}
+
+// metricValue returns the alert's metric for the event; quota percentages
+// are unavailable when the event has no such quota
+func metricValue(alert *models.ThresholdAlert, usage eventUsage) (int64, bool) {
+	switch alert.Metric {
+	case models.AlertMetricPhotoCount:
+		return usage.PhotoCount, true
+	case models.AlertMetricStorageBytes:
+		return usage.StorageBytes, true
+	case models.AlertMetricPhotoQuotaPercent:
+		if alert.Event.MaxPhotos == nil || *alert.Event.MaxPhotos == 0 {
+			return 0, false
+		}
+		return usage.PhotoCount * 100 / int64(*alert.Event.MaxPhotos), true
+	case models.AlertMetricGuestQuotaPercent:
+		if alert.Event.MaxGuests == nil || *alert.Event.MaxGuests == 0 {
+			return 0, false
+		}
+		return usage.GuestCount * 100 / int64(*alert.Event.MaxGuests), true
+	}
+	return 0, false
+}
+
+func (s *AlertService) notify(ctx context.Context, alert *models.ThresholdAlert, value int64) error {
+	lang := emailLang(ctx, s.db, alert.Event.OwnerEmail)
+
+	var description string
+	switch alert.Metric {
+	case models.AlertMetricPhotoCount:
+		description = i18n.Sprintf(lang, "the photo count reached %d (threshold: %d)", value, alert.Threshold)
+	case models.AlertMetricStorageBytes:
+		description = i18n.Sprintf(lang, "storage reached %d bytes (threshold: %d bytes)", value, alert.Threshold)
+	case models.AlertMetricPhotoQuotaPercent:
+		description = i18n.Sprintf(lang, "%d%% of the photo limit was reached (threshold: %d%%)", value, alert.Threshold)
+	case models.AlertMetricGuestQuotaPercent:
+		description = i18n.Sprintf(lang, "%d%% of the guest limit was reached (threshold: %d%%)", value, alert.Threshold)
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      alert.Event.OwnerEmail,
+		Subject: i18n.Sprintf(lang, "[SnapShare] Usage notice for %s", alert.Event.Name),
+		Body:    i18n.Sprintf(lang, "\"%s\": %s.\n", alert.Event.Name, description),
+	})
+}
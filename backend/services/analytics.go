@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"snapShare/infra/cache"
+	"snapShare/models"
+)
+
+const (
+	// overviewDays is how many days of uploads the owner overview charts
+	overviewDays = 30
+	// overviewActivityLimit caps the recent activity of the owner overview
+	overviewActivityLimit = 20
+)
+
+// Owner overview activity types
+const (
+	ActivityPhotoUploaded = "photo_uploaded"
+	ActivityGuestJoined   = "guest_joined"
+)
+
+// AnalyticsService summarises activity across all the events of an owner
+type AnalyticsService struct {
+	db    *gorm.DB
+	cache cache.Cache
+}
+
+func NewAnalyticsService(db *gorm.DB, c cache.Cache) *AnalyticsService {
+	return &AnalyticsService{
+		db:    db,
+		cache: c,
+	}
+}
+
+// OwnerOverview sums up the events an owner owns or co-hosts. Photos and
+// storage count confirmed photos, hidden ones included; active guests are
+// guests with a live session.
+type OwnerOverview struct {
+	EventCount   int64
+	ActiveEvents int64
+	PhotoCount   int64
+	StorageBytes int64
+	ActiveGuests int64
+	// UploadsPerDay covers the last overviewDays UTC days that had uploads
+	UploadsPerDay  []DailyUploads
+	RecentActivity []Activity
+}
+
+// Activity is a photo upload or a guest joining one of the owner's events
+type Activity struct {
+	Type      string
+	EventID   uuid.UUID
+	EventName string
+	GuestName string
+	At        time.Time
+}
+
+func ownerOverviewCacheKey(owner Owner) string {
+	return "overview:" + owner.UserID.String()
+}
+
+// GetOwnerOverview summarises the events of owner. Overviews are cached
+// for cacheTTL, so they may lag recent uploads by that much.
+func (s *AnalyticsService) GetOwnerOverview(ctx context.Context, owner Owner) (*OwnerOverview, error) {
+	key := ownerOverviewCacheKey(owner)
+	var overview OwnerOverview
+	if hit, err := s.cache.Get(ctx, key, &overview); err != nil {
+		slog.WarnContext(ctx, "overview cache lookup failed", "error", err)
+	} else if hit {
+		return &overview, nil
+	}
+
+	// Co-hosted events are included, as in the event list
+	var events []models.Event
+	if err := s.db.WithContext(ctx).Select("id", "name", "status", "photo_count", "total_bytes").
+		Where("user_id = ? OR id IN (?)", owner.UserID, coHostedEvents(s.db.WithContext(ctx), owner)).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	eventIDs := make([]uuid.UUID, len(events))
+	eventNames := make(map[uuid.UUID]string, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+		eventNames[event.ID] = event.Name
+		overview.EventCount++
+		if event.Status == models.EventStatusActive {
+			overview.ActiveEvents++
+		}
+		overview.PhotoCount += event.PhotoCount
+		overview.StorageBytes += event.TotalBytes
+	}
+
+	if len(events) > 0 {
+		if err := s.aggregateActivity(ctx, eventIDs, eventNames, &overview); err != nil {
+			return nil, err
+		}
+	}
+
+	setCache(ctx, s.cache, key, &overview)
+	return &overview, nil
+}
+
+// aggregateActivity fills in the guest, upload and activity figures of the
+// events in eventIDs
+func (s *AnalyticsService) aggregateActivity(ctx context.Context, eventIDs []uuid.UUID, eventNames map[uuid.UUID]string, overview *OwnerOverview) error {
+	now := time.Now()
+
+	err := s.db.WithContext(ctx).Model(&models.Session{}).
+		Select("COUNT(DISTINCT (event_id, guest_name))").
+		Where("event_id IN ? AND expires_at > ?", eventIDs, now).
+		Scan(&overview.ActiveGuests).Error
+	if err != nil {
+		return fmt.Errorf("failed to count active guests: %w", err)
+	}
+
+	since := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(overviewDays - 1))
+	err = s.db.WithContext(ctx).Model(&models.Photo{}).
+		Select("date_trunc('day', confirmed_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS count").
+		Where("event_id IN ? AND confirmed_at >= ?", eventIDs, since).
+		Group("day").
+		Order("day").
+		Scan(&overview.UploadsPerDay).Error
+	if err != nil {
+		return fmt.Errorf("failed to aggregate uploads per day: %w", err)
+	}
+	for i, bucket := range overview.UploadsPerDay {
+		overview.UploadsPerDay[i].Day = inLocation(bucket.Day, time.UTC)
+	}
+
+	// Each side is limited on its own first, so both can use their index
+	err = s.db.WithContext(ctx).Raw(`SELECT * FROM (
+			(SELECT CAST(? AS text) AS type, event_id, uploader_name AS guest_name, confirmed_at AS at FROM photos
+				WHERE event_id IN ? AND confirmed_at IS NOT NULL AND deleted_at IS NULL
+				ORDER BY confirmed_at DESC LIMIT ?)
+			UNION ALL
+			(SELECT CAST(? AS text) AS type, event_id, guest_name, created_at AS at FROM sessions
+				WHERE event_id IN ? AND deleted_at IS NULL
+				ORDER BY created_at DESC LIMIT ?)
+		) AS activity ORDER BY at DESC LIMIT ?`,
+		ActivityPhotoUploaded, eventIDs, overviewActivityLimit,
+		ActivityGuestJoined, eventIDs, overviewActivityLimit,
+		overviewActivityLimit).
+		Scan(&overview.RecentActivity).Error
+	if err != nil {
+		return fmt.Errorf("failed to get recent activity: %w", err)
+	}
+	for i := range overview.RecentActivity {
+		overview.RecentActivity[i].EventName = eventNames[overview.RecentActivity[i].EventID]
+	}
+
+	return nil
+}
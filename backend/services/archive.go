@@ -0,0 +1,749 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"snapShare/i18n"
+	"snapShare/infra/mail"
+	"snapShare/infra/storage"
+	"snapShare/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoPhotos is returned when an archive is requested for an event without photos
+var ErrNoPhotos = errors.New("no photos found for event")
+
+// ErrArchiveExpired is returned for archives older than the retention period,
+// whose zip has been removed by the bucket lifecycle rules
+var ErrArchiveExpired = errors.New("archive has expired")
+
+// ArchivePrefix holds every archive zip, so a single lifecycle rule expires them
+const ArchivePrefix = "archives/"
+
+const (
+	// archiveDownloadExpiry is how long a presigned archive download URL stays valid
+	archiveDownloadExpiry = 1 * time.Hour
+	// emailedDownloadExpiry is the lifetime of archive links sent by email (SigV4 maximum)
+	emailedDownloadExpiry = 7 * 24 * time.Hour
+	// archiveHeartbeatInterval is how often a building archive marks itself alive
+	archiveHeartbeatInterval = time.Minute
+	// archiveStaleAfter is how long a pending or running archive may go
+	// without a heartbeat before it is considered lost with its instance
+	archiveStaleAfter = 5 * time.Minute
+	// maxArchiveAttempts is how many times a lost archive is built before it fails
+	maxArchiveAttempts = 3
+)
+
+type ArchiveService struct {
+	db        *gorm.DB
+	storage   storage.Storage
+	mailer    mail.Mailer
+	retention time.Duration
+}
+
+func NewArchiveService(db *gorm.DB, store storage.Storage, mailer mail.Mailer, retentionDays int) *ArchiveService {
+	return &ArchiveService{
+		db:        db,
+		storage:   store,
+		mailer:    mailer,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// archiveManifest is written to manifest.json inside every archive
+type archiveManifest struct {
+	Event struct {
+		ID        uuid.UUID  `json:"id"`
+		Name      string     `json:"name"`
+		Code      string     `json:"code"`
+		EventDate *time.Time `json:"event_date,omitempty"`
+	} `json:"event"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Photos      []archiveManifestPhoto `json:"photos"`
+	Guests      []archiveManifestGuest `json:"guests,omitempty"`
+}
+
+type archiveManifestPhoto struct {
+	ID           uuid.UUID  `json:"id"`
+	File         string     `json:"file"`
+	UploaderName string     `json:"uploader_name"`
+	Caption      *string    `json:"caption,omitempty"`
+	MimeType     string     `json:"mime_type"`
+	Size         int64      `json:"file_size"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	TakenAt      *time.Time `json:"taken_at,omitempty"`
+	UploadedAt   time.Time  `json:"uploaded_at"`
+}
+
+// archiveManifestGuest is only included in full-event archives, which are owner-only
+type archiveManifestGuest struct {
+	GuestName     string    `json:"guest_name"`
+	FirstJoinedAt time.Time `json:"first_joined_at"`
+	PhotoCount    int       `json:"photo_count"`
+}
+
+// archiveFormatVersion is mixed into the photo set hash so archives cached
+// before a change to the archive layout are rebuilt
+const archiveFormatVersion = "2"
+
+type ArchiveEstimate struct {
+	PhotoCount   int64
+	TotalBytes   int64
+	ArchiveBytes int64
+}
+
+// zipEntryOverhead approximates the per-file header bytes of a stored zip entry
+// (local header, central directory record and the file name twice)
+const zipEntryOverhead = 30 + 46 + 2*128
+
+type ArchiveStatus struct {
+	Job         *models.ArchiveJob
+	DownloadURL string
+	ExpiresAt   *time.Time
+}
+
+// GuestDownloadsAllowed reports whether guests may download archives of an event
+func (s *ArchiveService) GuestDownloadsAllowed(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	return guestDownloadsAllowed(s.db.WithContext(ctx), eventID)
+}
+
+// StartArchive queues a background job that zips every confirmed photo of an
+// event, or only those of uploaderName when it is set
+func (s *ArchiveService) StartArchive(ctx context.Context, eventID uuid.UUID, uploaderName *string) (*models.ArchiveJob, error) {
+	var count int64
+	if err := s.archivePhotos(eventID, uploaderName).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count photos: %w", err)
+	}
+
+	if count == 0 {
+		return nil, ErrNoPhotos
+	}
+
+	hash, err := s.photoSetHash(eventID, uploaderName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Full-event archives are cached on the event and reused until the photo set changes
+	if uploaderName == nil {
+		if cached := s.cachedArchive(eventID, hash); cached != nil {
+			return cached, nil
+		}
+	}
+
+	jobID := uuid.New()
+	now := time.Now()
+	job := &models.ArchiveJob{
+		ID:           jobID,
+		EventID:      eventID,
+		UploaderName: uploaderName,
+		PhotoSetHash: hash,
+		Status:       models.ArchiveJobStatusPending,
+		ObjectKey:    fmt.Sprintf("%s%s/%s.zip", ArchivePrefix, eventID, jobID),
+		Attempts:     1,
+		HeartbeatAt:  &now,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("failed to create archive job: %w", err)
+		}
+		if uploaderName != nil {
+			return nil
+		}
+		return tx.Model(&models.Event{}).
+			Where("id = ?", eventID).
+			Updates(map[string]any{"archive_job_id": job.ID, "archive_photo_set_hash": hash}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The job outlives the request, so it must not inherit its cancellation
+	go s.runArchive(context.WithoutCancel(ctx), job.ID)
+
+	return job, nil
+}
+
+// StartExport builds (or reuses) the full-event archive, which bundles the
+// photos with JSON and CSV manifests of photo metadata and guests. When
+// notifyEmail is set, a download link is emailed once the export is ready.
+func (s *ArchiveService) StartExport(ctx context.Context, eventID uuid.UUID, notifyEmail string) (*models.ArchiveJob, error) {
+	job, err := s.StartArchive(ctx, eventID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifyEmail == "" {
+		return job, nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(job).Update("notify_email", notifyEmail).Error; err != nil {
+		return nil, fmt.Errorf("failed to set export recipient: %w", err)
+	}
+	job.NotifyEmail = &notifyEmail
+
+	// The archive may already be complete (cached, or it finished before the
+	// recipient was saved); notifyIfRequested sends at most once either way
+	s.notifyIfRequested(context.WithoutCancel(ctx), job.ID)
+
+	return job, nil
+}
+
+// EstimateArchive returns the number and total size of the photos an archive
+// would contain, so clients can warn before downloading on mobile data
+func (s *ArchiveService) EstimateArchive(ctx context.Context, eventID uuid.UUID, uploaderName *string) (*ArchiveEstimate, error) {
+	var estimate ArchiveEstimate
+	if uploaderName != nil {
+		err := s.archivePhotos(eventID, uploaderName).
+			Select("COUNT(*) AS photo_count, COALESCE(SUM(size), 0) AS total_bytes").
+			Scan(&estimate).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate archive: %w", err)
+		}
+	} else {
+		// Full archives start from the event's counters and leave out the
+		// few hidden photos, rather than summing the whole gallery
+		var event models.Event
+		if err := s.db.WithContext(ctx).Select("id", "photo_count", "total_bytes").First(&event, eventID).Error; err != nil {
+			return nil, fmt.Errorf("event not found: %w", err)
+		}
+		var hidden ArchiveEstimate
+		err := s.db.WithContext(ctx).Model(&models.Photo{}).
+			Select("COUNT(*) AS photo_count, COALESCE(SUM(size), 0) AS total_bytes").
+			Where("event_id = ? AND confirmed_at IS NOT NULL AND hidden_at IS NOT NULL", eventID).
+			Scan(&hidden).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate archive: %w", err)
+		}
+		estimate.PhotoCount = event.PhotoCount - hidden.PhotoCount
+		estimate.TotalBytes = event.TotalBytes - hidden.TotalBytes
+	}
+
+	// End of central directory record is 22 bytes
+	estimate.ArchiveBytes = estimate.TotalBytes + estimate.PhotoCount*zipEntryOverhead + 22
+
+	return &estimate, nil
+}
+
+// GetArchiveStatus returns a job's state and, once completed, a download URL
+func (s *ArchiveService) GetArchiveStatus(ctx context.Context, eventID, jobID uuid.UUID) (*ArchiveStatus, error) {
+	var job models.ArchiveJob
+	if err := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", jobID, eventID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("archive job not found")
+		}
+		return nil, fmt.Errorf("failed to get archive job: %w", err)
+	}
+
+	status := &ArchiveStatus{Job: &job}
+	if job.Status != models.ArchiveJobStatusCompleted {
+		return status, nil
+	}
+	if s.expired(&job) {
+		return nil, ErrArchiveExpired
+	}
+
+	downloadURL, expiresAt, err := s.DownloadURL(ctx, &job, archiveDownloadExpiry)
+	if err != nil {
+		return nil, err
+	}
+	status.DownloadURL = downloadURL
+	status.ExpiresAt = &expiresAt
+
+	return status, nil
+}
+
+// GetJob retrieves an archive job by ID
+func (s *ArchiveService) GetJob(ctx context.Context, jobID uuid.UUID) (*models.ArchiveJob, error) {
+	var job models.ArchiveJob
+	if err := s.db.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get archive job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// DownloadURL presigns a download URL for a completed archive
+func (s *ArchiveService) DownloadURL(ctx context.Context, job *models.ArchiveJob, expiry time.Duration) (string, time.Time, error) {
+	downloadURL, err := s.storage.GeneratePresignedDownloadURL(ctx, job.ObjectKey, expiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate download URL: %w", err)
+	}
+
+	return downloadURL, time.Now().Add(expiry), nil
+}
+
+func (s *ArchiveService) runArchive(ctx context.Context, jobID uuid.UUID) {
+	var job models.ArchiveJob
+	if err := s.db.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to load archive job", "job_id", jobID, "error", err)
+		return
+	}
+
+	s.updateJob(ctx, &job, map[string]any{"status": models.ArchiveJobStatusRunning, "heartbeat_at": time.Now()})
+
+	stopHeartbeat := s.heartbeat(ctx, job.ID)
+	photoCount, err := s.buildArchive(ctx, &job)
+	stopHeartbeat()
+	if err != nil {
+		slog.ErrorContext(ctx, "archive job failed", "job_id", jobID, "error", err)
+		s.updateJob(ctx, &job, map[string]any{
+			"status": models.ArchiveJobStatusFailed,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	s.updateJob(ctx, &job, map[string]any{
+		"status":       models.ArchiveJobStatusCompleted,
+		"photo_count":  photoCount,
+		"completed_at": time.Now(),
+	})
+
+	s.notifyIfRequested(ctx, job.ID)
+}
+
+// heartbeat marks the job alive every archiveHeartbeatInterval until stopped
+func (s *ArchiveService) heartbeat(ctx context.Context, jobID uuid.UUID) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(archiveHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := s.db.WithContext(ctx).Model(&models.ArchiveJob{}).
+				Where("id = ?", jobID).
+				Update("heartbeat_at", time.Now()).Error; err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to record archive heartbeat", "job_id", jobID, "error", err)
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// Run recovers archives lost with the instance building them every interval
+// until ctx is cancelled
+func (s *ArchiveService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "archive_recovery", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "archive recovery run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick claims pending and running archives whose heartbeat stopped and
+// builds them again on this instance, or fails them after maxArchiveAttempts
+func (s *ArchiveService) Tick(ctx context.Context) error {
+	now := time.Now()
+	stale := s.db.WithContext(ctx).Model(&models.ArchiveJob{}).
+		Select("id").
+		Where("status IN ? AND COALESCE(heartbeat_at, created_at) <= ?",
+			[]models.ArchiveJobStatus{models.ArchiveJobStatusPending, models.ArchiveJobStatusRunning}, now.Add(-archiveStaleAfter)).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var jobs []models.ArchiveJob
+	if err := s.db.WithContext(ctx).Model(&jobs).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", stale).
+		Updates(map[string]any{
+			"status":       models.ArchiveJobStatusPending,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to claim stale archive jobs: %w", err)
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Attempts > maxArchiveAttempts {
+			slog.ErrorContext(ctx, "archive job stalled", "job_id", job.ID, "attempts", job.Attempts-1)
+			s.updateJob(ctx, job, map[string]any{
+				"status": models.ArchiveJobStatusFailed,
+				"error":  "archive job stalled",
+			})
+			continue
+		}
+
+		slog.WarnContext(ctx, "restarting stale archive job", "job_id", job.ID, "attempts", job.Attempts)
+		go s.runArchive(context.WithoutCancel(ctx), job.ID)
+	}
+
+	return nil
+}
+
+// notifyIfRequested emails the download link of a completed archive to its
+// recipient. The notified_at claim guarantees a single email per job.
+func (s *ArchiveService) notifyIfRequested(ctx context.Context, jobID uuid.UUID) {
+	var job models.ArchiveJob
+	if err := s.db.WithContext(ctx).First(&job, jobID).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to load archive job for notification", "job_id", jobID, "error", err)
+		return
+	}
+	if job.Status != models.ArchiveJobStatusCompleted || job.NotifyEmail == nil || job.NotifiedAt != nil {
+		return
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.ArchiveJob{}).
+		Where("id = ? AND notified_at IS NULL", jobID).
+		Update("notified_at", time.Now())
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, job.EventID).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to load archive event", "job_id", jobID, "error", err)
+		return
+	}
+
+	downloadURL, expiresAt, err := s.DownloadURL(ctx, &job, min(emailedDownloadExpiry, s.retention))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to sign archive download URL", "job_id", jobID, "error", err)
+		return
+	}
+
+	lang := emailLang(ctx, s.db, *job.NotifyEmail)
+	body := i18n.Sprintf(lang, "The export of \"%s\" with %d photos and the event details is ready and can be downloaded until %s:\n%s\n",
+		event.Name, job.PhotoCount, expiresAt.Format("2006-01-02 15:04"), downloadURL)
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      *job.NotifyEmail,
+		Subject: i18n.Sprintf(lang, "[SnapShare] Export of %s", event.Name),
+		Body:    body,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to send archive notification", "job_id", jobID, "error", err)
+	}
+}
+
+// buildArchive streams the zip straight into a multipart upload: photos are
+// read from storage and zipped into a pipe whose other end feeds the upload,
+// so an archive of any size only ever holds one upload part in memory and
+// never touches the disk
+func (s *ArchiveService) buildArchive(ctx context.Context, job *models.ArchiveJob) (int, error) {
+	var photos []models.Photo
+	if err := s.archivePhotos(job.EventID, job.UploaderName).
+		Order("created_at ASC").
+		Find(&photos).Error; err != nil {
+		return 0, fmt.Errorf("failed to get photos: %w", err)
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, job.EventID).Error; err != nil {
+		return 0, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	// Guest details are only exported with full-event archives
+	var guests []archiveManifestGuest
+	if job.UploaderName == nil {
+		var err error
+		if guests, err = s.archiveGuests(job.EventID, photos); err != nil {
+			return 0, err
+		}
+	}
+
+	// A failed photo ends the upload with an error instead of EOF, so a
+	// truncated archive is never stored
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.writeArchive(ctx, pw, &event, photos, guests))
+	}()
+
+	if err := s.storage.UploadMultipart(ctx, job.ObjectKey, "application/zip", pr); err != nil {
+		pr.CloseWithError(err)
+		return 0, fmt.Errorf("failed to upload archive: %w", err)
+	}
+
+	return len(photos), nil
+}
+
+// writeArchive writes the zip of photos with their manifest and CSV to w
+func (s *ArchiveService) writeArchive(ctx context.Context, w io.Writer, event *models.Event, photos []models.Photo, guests []archiveManifestGuest) error {
+	zw := zip.NewWriter(w)
+	for _, photo := range photos {
+		if err := s.addPhotoToArchive(ctx, zw, photo); err != nil {
+			return err
+		}
+	}
+	if err := writeArchiveManifest(zw, event, photos, guests); err != nil {
+		return err
+	}
+	if err := writeArchiveCSV(zw, photos, guests); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize zip: %w", err)
+	}
+	return nil
+}
+
+// cachedArchive returns the event's cached archive job when it was built from
+// the same photo set and has not failed
+func (s *ArchiveService) cachedArchive(eventID uuid.UUID, hash string) *models.ArchiveJob {
+	var event models.Event
+	if err := s.db.Select("id", "archive_job_id", "archive_photo_set_hash").First(&event, eventID).Error; err != nil {
+		return nil
+	}
+	if event.ArchiveJobID == nil || event.ArchivePhotoSetHash == nil || *event.ArchivePhotoSetHash != hash {
+		return nil
+	}
+
+	var job models.ArchiveJob
+	if err := s.db.First(&job, *event.ArchiveJobID).Error; err != nil {
+		return nil
+	}
+	if job.Status == models.ArchiveJobStatusFailed || s.expired(&job) {
+		return nil
+	}
+
+	return &job
+}
+
+// expired reports whether a completed archive is past the retention period
+func (s *ArchiveService) expired(job *models.ArchiveJob) bool {
+	return job.CompletedAt != nil && time.Since(*job.CompletedAt) >= s.retention
+}
+
+// photoSetHash fingerprints the photos that would go into an archive
+func (s *ArchiveService) photoSetHash(eventID uuid.UUID, uploaderName *string) (string, error) {
+	var hash string
+	err := s.archivePhotos(eventID, uploaderName).
+		Select("md5(?::text || string_agg(id::text || ':' || size::text, ',' ORDER BY id))", archiveFormatVersion+";").
+		Scan(&hash).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to hash photo set: %w", err)
+	}
+
+	return hash, nil
+}
+
+// InvalidateEventArchive drops the cached archive of an event after its photos change
+func InvalidateEventArchive(db *gorm.DB, eventIDs ...uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	err := db.Model(&models.Event{}).
+		Where("id IN ?", eventIDs).
+		Updates(map[string]any{"archive_job_id": nil, "archive_photo_set_hash": nil}).Error
+	if err != nil {
+		return fmt.Errorf("failed to invalidate archive cache: %w", err)
+	}
+
+	return nil
+}
+
+// archivePhotos selects the confirmed, visible photos that belong in an archive
+func (s *ArchiveService) archivePhotos(eventID uuid.UUID, uploaderName *string) *gorm.DB {
+	query := s.db.Model(&models.Photo{}).Where("event_id = ? AND confirmed_at IS NOT NULL AND hidden_at IS NULL", eventID)
+	if uploaderName != nil {
+		query = query.Where("uploader_name = ?", *uploaderName)
+	}
+	return query
+}
+
+func (s *ArchiveService) addPhotoToArchive(ctx context.Context, zw *zip.Writer, photo models.Photo) error {
+	body, err := s.storage.GetObject(ctx, photo.ObjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to read photo %s: %w", photo.ID, err)
+	}
+	defer body.Close()
+
+	// Photos are already compressed, so store them as-is
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     archiveEntryName(photo),
+		Method:   zip.Store,
+		Modified: photo.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add zip entry: %w", err)
+	}
+
+	if _, err := io.Copy(entry, body); err != nil {
+		return fmt.Errorf("failed to write photo %s: %w", photo.ID, err)
+	}
+
+	return nil
+}
+
+// archiveGuests lists everyone who joined the event with their confirmed photo count
+func (s *ArchiveService) archiveGuests(eventID uuid.UUID, photos []models.Photo) ([]archiveManifestGuest, error) {
+	var guests []archiveManifestGuest
+	err := s.db.Model(&models.Session{}).
+		Select("guest_name, MIN(created_at) AS first_joined_at").
+		Where("event_id = ?", eventID).
+		Group("guest_name").
+		Order("first_joined_at ASC").
+		Scan(&guests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guests: %w", err)
+	}
+
+	photoCounts := map[string]int{}
+	for _, photo := range photos {
+		photoCounts[photo.UploaderName]++
+	}
+	for i := range guests {
+		guests[i].PhotoCount = photoCounts[guests[i].GuestName]
+	}
+
+	return guests, nil
+}
+
+// writeArchiveManifest adds manifest.json describing the event, its photos and guests
+func writeArchiveManifest(zw *zip.Writer, event *models.Event, photos []models.Photo, guests []archiveManifestGuest) error {
+	var manifest archiveManifest
+	manifest.Event.ID = event.ID
+	manifest.Event.Name = event.Name
+	manifest.Event.Code = event.Code
+	manifest.Event.EventDate = event.EventDate
+	manifest.GeneratedAt = time.Now()
+	manifest.Photos = make([]archiveManifestPhoto, len(photos))
+	for i, photo := range photos {
+		manifest.Photos[i] = archiveManifestPhoto{
+			ID:           photo.ID,
+			File:         archiveEntryName(photo),
+			UploaderName: photo.UploaderName,
+			Caption:      photo.Caption,
+			MimeType:     photo.MimeType,
+			Size:         photo.Size,
+			Width:        photo.Width,
+			Height:       photo.Height,
+			TakenAt:      photo.TakenAt,
+			UploadedAt:   photo.CreatedAt,
+		}
+	}
+
+	manifest.Guests = guests
+
+	entry, err := zw.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("failed to add manifest: %w", err)
+	}
+
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// writeArchiveCSV adds photos.csv, and guests.csv when guests are given,
+// for owners who open the export in a spreadsheet
+func writeArchiveCSV(zw *zip.Writer, photos []models.Photo, guests []archiveManifestGuest) error {
+	rows := [][]string{{"id", "file", "uploader_name", "caption", "mime_type", "file_size", "width", "height", "taken_at", "uploaded_at"}}
+	for _, photo := range photos {
+		rows = append(rows, []string{
+			photo.ID.String(),
+			archiveEntryName(photo),
+			photo.UploaderName,
+			csvString(photo.Caption),
+			photo.MimeType,
+			strconv.FormatInt(photo.Size, 10),
+			csvInt(photo.Width),
+			csvInt(photo.Height),
+			csvTime(photo.TakenAt),
+			photo.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if err := writeCSVEntry(zw, "photos.csv", rows); err != nil {
+		return err
+	}
+
+	if guests == nil {
+		return nil
+	}
+
+	rows = [][]string{{"guest_name", "first_joined_at", "photo_count"}}
+	for _, guest := range guests {
+		rows = append(rows, []string{
+			guest.GuestName,
+			guest.FirstJoinedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(guest.PhotoCount),
+		})
+	}
+	return writeCSVEntry(zw, "guests.csv", rows)
+}
+
+func writeCSVEntry(zw *zip.Writer, name string, rows [][]string) error {
+	entry, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+
+	w := csv.NewWriter(entry)
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func csvString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func csvInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func csvTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
+
+// archiveEntryName groups photos by uploader inside the zip
+func archiveEntryName(photo models.Photo) string {
+	uploader := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(photo.UploaderName)
+	if uploader == "" {
+		uploader = "unknown"
+	}
+	return uploader + "/" + photo.ID.String() + path.Ext(photo.ObjectKey)
+}
+
+func (s *ArchiveService) updateJob(ctx context.Context, job *models.ArchiveJob, updates map[string]any) {
+	if err := s.db.WithContext(ctx).Model(job).Updates(updates).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to update archive job", "job_id", job.ID, "error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"snapShare/models"
+	"snapShare/pagination"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{
+		db: db,
+	}
+}
+
+// AuditActor identifies who performed an audited action
+type AuditActor struct {
+	Type      string // one of the models.AuditActor* constants, admin when empty
+	Name      string
+	IPAddress string
+}
+
+// AuditEntry describes a single audited action
+type AuditEntry struct {
+	EventID    *uuid.UUID
+	Actor      AuditActor
+	Action     string
+	TargetType string
+	TargetID   string
+	Details    map[string]any
+}
+
+// Record persists an audit entry using the given transaction (or the default connection when nil)
+func (s *AuditService) Record(ctx context.Context, tx *gorm.DB, entry AuditEntry) error {
+	if tx == nil {
+		tx = s.db
+	}
+
+	actorType := entry.Actor.Type
+	if actorType == "" {
+		actorType = models.AuditActorAdmin
+	}
+
+	log := models.AuditLog{
+		ID:         uuid.New(),
+		EventID:    entry.EventID,
+		ActorType:  actorType,
+		Actor:      entry.Actor.Name,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IPAddress:  entry.Actor.IPAddress,
+	}
+
+	if len(entry.Details) > 0 {
+		details, err := json.Marshal(entry.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+		detailsStr := string(details)
+		log.Details = &detailsStr
+	}
+
+	if err := tx.Create(&log).Error; err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+
+	return nil
+}
+
+// AuditFilter narrows down and paginates an event's audit log
+type AuditFilter struct {
+	// Action matches exactly, or a whole prefix when it ends in "." (e.g. "session.")
+	Action string
+	Limit  int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+	// Before only returns entries older than this time
+	Before *time.Time
+}
+
+// AuditPage is one page of an event's audit log, newest first
+type AuditPage struct {
+	Entries    []models.AuditLog
+	NextCursor string
+}
+
+// auditKeyset orders the audit log newest first
+var auditKeyset = pagination.Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+
+// GetAuditLogByEvent returns a page of an event's audit entries, newest first
+func (s *AuditService) GetAuditLogByEvent(ctx context.Context, eventID uuid.UUID, filter AuditFilter) (*AuditPage, error) {
+	query := s.db.WithContext(ctx).Where("event_id = ?", eventID)
+
+	if strings.HasSuffix(filter.Action, ".") {
+		query = query.Where("action LIKE ?", escapeLikePattern(filter.Action)+"%")
+	} else if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+	if filter.Cursor != "" {
+		var createdAt time.Time
+		var id uuid.UUID
+		if err := pagination.Decode(filter.Cursor, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		condition, args := auditKeyset.After(createdAt, id)
+		query = query.Where(condition, args...)
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order(auditKeyset.Order()).Limit(filter.Limit + 1).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	page := &AuditPage{}
+	var more bool
+	page.Entries, more = pagination.Trim(logs, filter.Limit)
+	if more {
+		last := page.Entries[len(page.Entries)-1]
+		page.NextCursor = pagination.Encode(last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"snapShare/i18n"
+	"snapShare/infra/auth"
+	"snapShare/infra/mail"
+	"snapShare/models"
+	"snapShare/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrEmailTaken is returned when registering an email that already has an account
+	ErrEmailTaken = errors.New("email is already registered")
+	// ErrInvalidCredentials is returned for an unknown email or a wrong password
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrEmailUnverified is returned when a password login's email has not been verified yet
+	ErrEmailUnverified = errors.New("email is not verified, sign in with a login link")
+	// ErrInvalidRefreshToken is returned for unknown, expired or already used refresh tokens
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrInvalidMagicLink is returned for unknown, expired or already used login links
+	ErrInvalidMagicLink = errors.New("invalid or expired login link")
+	// ErrOAuthEmailUnverified is returned when a new OAuth sign-in has no verified email to link by
+	ErrOAuthEmailUnverified = errors.New("the provider did not return a verified email")
+)
+
+const (
+	// accessTokenTTL is how long an owner access JWT is valid
+	accessTokenTTL = 15 * time.Minute
+	// refreshTokenTTL is how long an owner refresh token is valid
+	refreshTokenTTL = 30 * 24 * time.Hour
+	// magicLinkTTL is how long an emailed login link is valid
+	magicLinkTTL = 15 * time.Minute
+)
+
+// dummyPasswordHash is compared against when the email is unknown, so a login
+// takes as long for a missing account as for a wrong password
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("snapshare-dummy-password"), bcrypt.DefaultCost)
+
+// AuthTokens is an owner access token with the refresh token that renews it
+type AuthTokens struct {
+	AccessToken     string
+	AccessExpiresAt time.Time
+	RefreshToken    string
+}
+
+type AuthService struct {
+	db           *gorm.DB
+	eventService *EventService
+	mailer       mail.Mailer
+}
+
+func NewAuthService(db *gorm.DB, eventService *EventService, mailer mail.Mailer) *AuthService {
+	return &AuthService{
+		db:           db,
+		eventService: eventService,
+		mailer:       mailer,
+	}
+}
+
+// Register starts an owner account by emailing a confirmation link. The
+// account is only created, and events already created under the email only
+// linked to it, once the link is used, so nobody can claim an email they do
+// not receive mail at.
+func (s *AuthService) Register(ctx context.Context, email, password string, name *string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	// Passwordless accounts may register to add a password
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("email = ? AND password_hash <> ''", email).Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if existing > 0 {
+		return ErrEmailTaken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	token, err := generateAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate confirmation link: %w", err)
+	}
+
+	link := &models.MagicLink{
+		ID:           uuid.New(),
+		Email:        email,
+		TokenHash:    hashAuthToken(token),
+		ExpiresAt:    time.Now().Add(magicLinkTTL),
+		PasswordHash: string(hash),
+		Name:         name,
+	}
+	if err := s.db.WithContext(ctx).Create(link).Error; err != nil {
+		return fmt.Errorf("failed to create confirmation link: %w", err)
+	}
+
+	lang := i18n.FromContext(ctx, i18n.Japanese)
+	body := i18n.Sprintf(lang, "Confirm your email address to finish creating your SnapShare account (valid for %d minutes):\n%s\n\nIf you did not sign up, you can ignore this email.\n",
+		int(magicLinkTTL.Minutes()), s.eventService.AppURL("/auth/magic-link?token="+url.QueryEscape(token)))
+	if err := s.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: i18n.T(lang, "[SnapShare] Confirm your email address"),
+		Body:    body,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to send registration confirmation", "magic_link_id", link.ID, "error", err)
+		return fmt.Errorf("failed to send confirmation link: %w", err)
+	}
+
+	return nil
+}
+
+// Login verifies an owner's email and password
+func (s *AuthService) Login(ctx context.Context, email, password string) (*models.User, error) {
+	var user models.User
+	err := s.db.WithContext(ctx).Where("email = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err != nil || user.PasswordHash == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Accounts registered before confirmation was required verify their
+	// email with a login link first
+	if user.EmailVerifiedAt == nil {
+		return nil, ErrEmailUnverified
+	}
+
+	return &user, nil
+}
+
+// SendMagicLink emails a single-use login link. Unknown emails get a link
+// too; the account is created when the link is used.
+func (s *AuthService) SendMagicLink(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	token, err := generateAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate login link: %w", err)
+	}
+
+	link := &models.MagicLink{
+		ID:        uuid.New(),
+		Email:     email,
+		TokenHash: hashAuthToken(token),
+		ExpiresAt: time.Now().Add(magicLinkTTL),
+	}
+	if err := s.db.WithContext(ctx).Create(link).Error; err != nil {
+		return fmt.Errorf("failed to create login link: %w", err)
+	}
+
+	// The requester is the recipient, so their browser's language wins
+	lang := i18n.FromContext(ctx, "")
+	if lang == "" {
+		lang = emailLang(ctx, s.db, email)
+	}
+	body := i18n.Sprintf(lang, "Sign in to SnapShare with this link (valid for %d minutes):\n%s\n\nIf you did not request it, you can ignore this email.\n",
+		int(magicLinkTTL.Minutes()), s.eventService.AppURL("/auth/magic-link?token="+url.QueryEscape(token)))
+	if err := s.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: i18n.T(lang, "[SnapShare] Sign-in link"),
+		Body:    body,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to send magic link", "magic_link_id", link.ID, "error", err)
+		return fmt.Errorf("failed to send login link: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyMagicLink consumes a login or registration confirmation link and
+// returns its owner, creating the account on first use. Using the link
+// verifies the email.
+func (s *AuthService) VerifyMagicLink(ctx context.Context, token string) (*models.User, error) {
+	var user *models.User
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var link models.MagicLink
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashAuthToken(token), time.Now()).
+			First(&link).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMagicLink
+			}
+			return fmt.Errorf("failed to get login link: %w", err)
+		}
+
+		// The used_at guard makes concurrent uses of the same link fail
+		result := tx.Model(&models.MagicLink{}).
+			Where("id = ? AND used_at IS NULL", link.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return fmt.Errorf("failed to consume login link: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidMagicLink
+		}
+
+		var err error
+		user, err = findOrCreateUser(tx, link.Email)
+		if err != nil {
+			return err
+		}
+
+		// A registration only sets the password of an account that has none,
+		// e.g. not of one created by an earlier login link in the meantime
+		if link.PasswordHash != "" && user.PasswordHash == "" {
+			user.PasswordHash = link.PasswordHash
+			if user.Name == nil {
+				user.Name = link.Name
+			}
+			if err := tx.Model(user).Select("password_hash", "name").Updates(user).Error; err != nil {
+				return fmt.Errorf("failed to set password: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// OAuthLogin returns the owner signed in through an OAuth provider. A new
+// provider account is linked to the owner with the same verified email,
+// creating a passwordless account when there is none.
+func (s *AuthService) OAuthLogin(ctx context.Context, identity *auth.Identity) (*models.User, error) {
+	var user *models.User
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var linked models.OAuthIdentity
+		err := tx.Preload("User").
+			Where("provider = ? AND subject = ?", identity.Provider, identity.Subject).
+			First(&linked).Error
+		if err == nil {
+			user = &linked.User
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get oauth identity: %w", err)
+		}
+
+		if identity.Email == "" || !identity.EmailVerified {
+			return ErrOAuthEmailUnverified
+		}
+
+		email := strings.ToLower(identity.Email)
+		user, err = findOrCreateUser(tx, email)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.OAuthIdentity{
+			ID:       uuid.New(),
+			UserID:   user.ID,
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    email,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to link oauth identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// AppURL returns an absolute frontend URL for path
+func (s *AuthService) AppURL(path string) string {
+	return s.eventService.AppURL(path)
+}
+
+// findOrCreateUser returns the account for email, whose ownership the caller
+// has just verified, creating a passwordless one when there is none. The
+// owner's existing events are linked when the email is first verified.
+func findOrCreateUser(tx *gorm.DB, email string) (*models.User, error) {
+	now := time.Now()
+
+	var user models.User
+	err := tx.Where("email = ?", email).First(&user).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user = models.User{
+			ID:              uuid.New(),
+			Email:           email,
+			EmailVerifiedAt: &now,
+			Locale:          string(i18n.FromContext(tx.Statement.Context, i18n.Japanese)),
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	case user.EmailVerifiedAt != nil:
+		return &user, nil
+	default:
+		// A password set before the email was verified may be someone else's
+		user.EmailVerifiedAt = &now
+		user.PasswordHash = ""
+		if err := tx.Model(&user).Select("email_verified_at", "password_hash").Updates(&user).Error; err != nil {
+			return nil, fmt.Errorf("failed to verify email: %w", err)
+		}
+	}
+
+	if err := linkOwnerEvents(tx, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// linkOwnerEvents assigns events created under the user's email to the
+// account. It must only run once the email is verified: events are
+// authorized through user_id.
+func linkOwnerEvents(tx *gorm.DB, user *models.User) error {
+	if err := tx.Model(&models.Event{}).
+		Where("LOWER(owner_email) = ? AND user_id IS NULL", user.Email).
+		Update("user_id", user.ID).Error; err != nil {
+		return fmt.Errorf("failed to link events: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserByID retrieves an owner account
+func (s *AuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// IssueTokens starts a new refresh token family for the user, i.e. a new login
+func (s *AuthService) IssueTokens(ctx context.Context, user *models.User) (*AuthTokens, error) {
+	return s.issueTokens(s.db.WithContext(ctx), user, uuid.New())
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented token
+// is revoked; if it had already been used, the whole family is revoked since
+// the token has most likely leaked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	var tokens *AuthTokens
+	reused := false
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Preload("User").
+			Where("token_hash = ?", hashAuthToken(refreshToken)).
+			First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		if stored.RevokedAt != nil {
+			reused = true
+			return ErrInvalidRefreshToken
+		}
+		if time.Now().After(stored.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		// The revoked_at guard makes concurrent refreshes of the same token fail
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", stored.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidRefreshToken
+		}
+
+		var err error
+		tokens, err = s.issueTokens(tx, &stored.User, stored.FamilyID)
+		return err
+	})
+
+	if reused {
+		if err := s.revokeFamilyOf(refreshToken); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes every token in the refresh token's family
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	return s.revokeFamilyOf(refreshToken)
+}
+
+// LogoutAll revokes every refresh token of the user, signing them out on all
+// devices. Access tokens already issued stay valid until they expire, at most
+// accessTokenTTL later. It returns the number of signed-out devices.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := s.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *AuthService) revokeFamilyOf(refreshToken string) error {
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("family_id IN (?) AND revoked_at IS NULL",
+			s.db.Model(&models.RefreshToken{}).
+				Select("family_id").
+				Where("token_hash = ?", hashAuthToken(refreshToken))).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) issueTokens(tx *gorm.DB, user *models.User, familyID uuid.UUID) (*AuthTokens, error) {
+	accessExpiresAt := time.Now().Add(accessTokenTTL)
+	accessToken, err := utils.GenerateOwnerJWT(user.ID, user.Email, accessExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := generateAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	if err := tx.Create(&models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashAuthToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &AuthTokens{
+		AccessToken:     accessToken,
+		AccessExpiresAt: accessExpiresAt,
+		RefreshToken:    refreshToken,
+	}, nil
+}
+
+func generateAuthToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// hashAuthToken is how refresh tokens and login links are stored, so a
+// database leak does not leak usable tokens
+func hashAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
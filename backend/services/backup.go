@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"snapShare/infra/encrypt"
+	"snapShare/infra/storage"
+)
+
+// BackupPrefix holds the encrypted database backups
+const BackupPrefix = "backups/"
+
+// backupTimeFormat names backups so that keys sort chronologically
+const backupTimeFormat = "20060102T150405Z"
+
+// ErrBackupNotFound is returned when restoring a key that is not a backup
+var ErrBackupNotFound = errors.New("backup not found")
+
+// BackupService writes encrypted pg_dump snapshots to object storage and
+// restores them with pg_restore. Both tools must be on PATH.
+type BackupService struct {
+	storage storage.Storage
+	// databaseURL has the password removed, as command lines are visible
+	// to every user of the host; the password is passed in the environment
+	databaseURL string
+	dbPassword  string
+	key         []byte
+	interval    time.Duration
+	keep        int
+}
+
+func NewBackupService(store storage.Storage, databaseURL string, key []byte, interval time.Duration, keep int) *BackupService {
+	databaseURL, dbPassword := splitDatabasePassword(databaseURL)
+	return &BackupService{
+		storage:     store,
+		databaseURL: databaseURL,
+		dbPassword:  dbPassword,
+		key:         key,
+		interval:    interval,
+		keep:        keep,
+	}
+}
+
+// Run takes a backup whenever the latest one is older than the backup
+// interval, checking every checkInterval until ctx is cancelled. db only
+// keeps instances from taking the same backup; backups never go through it.
+func (s *BackupService) Run(ctx context.Context, db *gorm.DB, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, db, "backup", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "backup run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick takes a backup if one is due, then removes all but the newest backups
+func (s *BackupService) Tick(ctx context.Context) error {
+	backups, err := s.ListBackups(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) > 0 {
+		latest, err := backupTime(backups[len(backups)-1])
+		if err == nil && time.Since(latest) < s.interval {
+			return nil
+		}
+	}
+
+	key, err := s.CreateBackup(ctx)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "database backup written", "key", key)
+
+	backups = append(backups, key)
+	if len(backups) <= s.keep {
+		return nil
+	}
+	if err := s.storage.DeleteObjects(ctx, backups[:len(backups)-s.keep]); err != nil {
+		return fmt.Errorf("failed to delete old backups: %w", err)
+	}
+
+	return nil
+}
+
+// ListBackups returns the keys of every backup, oldest first
+func (s *BackupService) ListBackups(ctx context.Context) ([]string, error) {
+	keys, err := s.storage.ListObjectKeys(ctx, BackupPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, err := backupTime(key); err == nil {
+			backups = append(backups, key)
+		}
+	}
+	slices.Sort(backups)
+
+	return backups, nil
+}
+
+// CreateBackup streams pg_dump output through encryption into a new backup
+// object and returns its key
+func (s *BackupService) CreateBackup(ctx context.Context) (string, error) {
+	// The random suffix keeps keys unguessable on buckets with public access
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate backup name: %w", err)
+	}
+	key := fmt.Sprintf("%s%s-%s.dump.enc", BackupPrefix, time.Now().UTC().Format(backupTimeFormat), hex.EncodeToString(suffix))
+
+	dumpCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	dump := s.pgCommand(dumpCtx, "pg_dump", "--format=custom", "--no-owner", "--no-privileges", "--dbname="+s.databaseURL)
+	dump.Stderr = &stderr
+	stdout, err := dump.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to start pg_dump: %w", err)
+	}
+	if err := dump.Start(); err != nil {
+		return "", fmt.Errorf("failed to start pg_dump: %w", err)
+	}
+
+	// A failed dump ends the upload with an error instead of EOF, so a
+	// truncated backup is never stored
+	pr, pw := io.Pipe()
+	go func() {
+		err := encrypt.Encrypt(pw, stdout, s.key)
+		if err != nil {
+			cancel()
+		}
+		if waitErr := dump.Wait(); err == nil && waitErr != nil {
+			err = fmt.Errorf("pg_dump failed: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+		}
+		pw.CloseWithError(err)
+	}()
+
+	if err := s.storage.UploadMultipart(ctx, key, "application/octet-stream", pr); err != nil {
+		pr.CloseWithError(err)
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	return key, nil
+}
+
+// Restore replaces the database contents with a backup. pg_restore runs in a
+// single transaction, so a failed or corrupted restore changes nothing.
+func (s *BackupService) Restore(ctx context.Context, key string) error {
+	if _, err := backupTime(key); err != nil {
+		return ErrBackupNotFound
+	}
+
+	body, err := s.storage.GetObject(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	defer body.Close()
+
+	var stderr bytes.Buffer
+	restore := s.pgCommand(ctx, "pg_restore", "--clean", "--if-exists", "--no-owner", "--no-privileges",
+		"--single-transaction", "--dbname="+s.databaseURL)
+	restore.Stderr = &stderr
+	stdin, err := restore.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start pg_restore: %w", err)
+	}
+	if err := restore.Start(); err != nil {
+		return fmt.Errorf("failed to start pg_restore: %w", err)
+	}
+
+	decryptErr := encrypt.Decrypt(stdin, body, s.key)
+	stdin.Close()
+	waitErr := restore.Wait()
+
+	// pg_restore exiting early also fails the decryption with a broken pipe
+	if errors.Is(decryptErr, encrypt.ErrCorrupted) {
+		return fmt.Errorf("failed to decrypt backup: %w", decryptErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("pg_restore failed: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	}
+	if decryptErr != nil {
+		return fmt.Errorf("failed to decrypt backup: %w", decryptErr)
+	}
+
+	return nil
+}
+
+// pgCommand prepares a PostgreSQL client tool, passing the database password
+// through PGPASSWORD
+func (s *BackupService) pgCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if s.dbPassword != "" {
+		cmd.Env = append(os.Environ(), "PGPASSWORD="+s.dbPassword)
+	}
+	return cmd
+}
+
+// splitDatabasePassword removes the password from a connection string, in
+// either URL or keyword/value form, and returns it separately
+func splitDatabasePassword(databaseURL string) (string, string) {
+	if u, err := url.Parse(databaseURL); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		var password string
+		if u.User != nil {
+			password, _ = u.User.Password()
+			u.User = url.User(u.User.Username())
+		}
+		if query := u.Query(); query.Has("password") {
+			password = query.Get("password")
+			query.Del("password")
+			u.RawQuery = query.Encode()
+		}
+		return u.String(), password
+	}
+
+	var password string
+	fields := strings.Fields(databaseURL)
+	kept := fields[:0]
+	for _, field := range fields {
+		if value, ok := strings.CutPrefix(field, "password="); ok {
+			password = strings.Trim(value, "'")
+			continue
+		}
+		kept = append(kept, field)
+	}
+	return strings.Join(kept, " "), password
+}
+
+// backupTime parses the creation time out of a backup key
+func backupTime(key string) (time.Time, error) {
+	name, ok := strings.CutPrefix(key, BackupPrefix)
+	if !ok {
+		return time.Time{}, ErrBackupNotFound
+	}
+	name, ok = strings.CutSuffix(name, ".dump.enc")
+	if !ok {
+		return time.Time{}, ErrBackupNotFound
+	}
+	timestamp, _, _ := strings.Cut(name, "-")
+	return time.Parse(backupTimeFormat, timestamp)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrGuestBanned is returned when a banned guest tries to join an event
+var ErrGuestBanned = errors.New("guest is banned from this event")
+
+// What happens to a banned guest's photos
+const (
+	BanUploadsKeep   = "keep"
+	BanUploadsHide   = "hide"
+	BanUploadsDelete = "delete"
+)
+
+type BanService struct {
+	db       *gorm.DB
+	sessions *SessionService
+}
+
+func NewBanService(db *gorm.DB, sessions *SessionService) *BanService {
+	return &BanService{
+		db:       db,
+		sessions: sessions,
+	}
+}
+
+// BanRequest identifies the guest by session or by name
+type BanRequest struct {
+	SessionID *uuid.UUID
+	GuestName string
+	Uploads   string // keep (default), hide or delete
+	Reason    *string
+}
+
+// BanResult summarises what a ban changed
+type BanResult struct {
+	GuestName       string
+	RevokedSessions int64
+	AffectedPhotos  int64
+}
+
+// BanGuest bans a guest from an event in one transaction: their name and
+// every client fingerprint they joined from are blocked, their sessions are
+// revoked, and their photos are optionally hidden or deleted
+func (s *BanService) BanGuest(ctx context.Context, eventID uuid.UUID, req *BanRequest) (*BanResult, error) {
+	result := &BanResult{GuestName: req.GuestName}
+
+	var revoked []models.Session
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if req.SessionID != nil {
+			var session models.Session
+			if err := tx.Where("id = ? AND event_id = ?", *req.SessionID, eventID).First(&session).Error; err != nil {
+				return fmt.Errorf("session not found: %w", err)
+			}
+			result.GuestName = session.GuestName
+		}
+		if result.GuestName == "" {
+			return fmt.Errorf("guest name or session is required")
+		}
+
+		var fingerprints []string
+		if err := tx.Model(&models.Session{}).Unscoped().
+			Where("event_id = ? AND guest_name = ? AND client_fingerprint IS NOT NULL", eventID, result.GuestName).
+			Distinct().
+			Pluck("client_fingerprint", &fingerprints).Error; err != nil {
+			return fmt.Errorf("failed to get guest fingerprints: %w", err)
+		}
+
+		bans := []models.GuestBan{{ID: uuid.New(), EventID: eventID, GuestName: result.GuestName, Reason: req.Reason}}
+		for _, fingerprint := range fingerprints {
+			bans = append(bans, models.GuestBan{
+				ID:                uuid.New(),
+				EventID:           eventID,
+				GuestName:         result.GuestName,
+				ClientFingerprint: &fingerprint,
+				Reason:            req.Reason,
+			})
+		}
+		if err := tx.Create(&bans).Error; err != nil {
+			return fmt.Errorf("failed to create ban: %w", err)
+		}
+
+		revocation := tx.Clauses(clause.Returning{}).Where("event_id = ? AND guest_name = ?", eventID, result.GuestName).Delete(&revoked)
+		if revocation.Error != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", revocation.Error)
+		}
+		result.RevokedSessions = revocation.RowsAffected
+
+		switch req.Uploads {
+		case "", BanUploadsKeep:
+			return nil
+		case BanUploadsHide:
+			hidden := tx.Model(&models.Photo{}).
+				Where("event_id = ? AND uploader_name = ? AND hidden_at IS NULL", eventID, result.GuestName).
+				Update("hidden_at", time.Now())
+			if hidden.Error != nil {
+				return fmt.Errorf("failed to update photos: %w", hidden.Error)
+			}
+			result.AffectedPhotos = hidden.RowsAffected
+		case BanUploadsDelete:
+			deleted, err := deletePhotos(tx, "event_id = ? AND uploader_name = ?", eventID, result.GuestName)
+			if err != nil {
+				return err
+			}
+			result.AffectedPhotos = int64(len(deleted))
+		default:
+			return fmt.Errorf("invalid uploads action: %s", req.Uploads)
+		}
+
+		return InvalidateEventArchive(tx, eventID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Cached validations would keep the revoked sessions usable until they expire
+	s.sessions.invalidateSessions(ctx, revoked...)
+
+	return result, nil
+}
+
+// GetBansByEvent lists the banned guest names of an event
+func (s *BanService) GetBansByEvent(ctx context.Context, eventID uuid.UUID) ([]models.GuestBan, error) {
+	var bans []models.GuestBan
+	if err := s.db.WithContext(ctx).Where("event_id = ? AND client_fingerprint IS NULL", eventID).
+		Order("created_at DESC").
+		Find(&bans).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// UnbanGuest lifts every ban on a guest name. Hidden photos stay hidden.
+func (s *BanService) UnbanGuest(ctx context.Context, eventID uuid.UUID, guestName string) error {
+	result := s.db.WithContext(ctx).Where("event_id = ? AND guest_name = ?", eventID, guestName).Delete(&models.GuestBan{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove ban: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("ban not found")
+	}
+
+	return nil
+}
+
+// ClientFingerprint hashes the device identifier a client keeps in local
+// storage. IP addresses are deliberately not used: guests on venue Wi-Fi
+// share one address, and a ban must not lock all of them out.
+func ClientFingerprint(deviceID string) string {
+	sum := sha256.Sum256([]byte(deviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkGuestBan verifies neither the guest name nor the client is banned
+func checkGuestBan(tx *gorm.DB, eventID uuid.UUID, guestName string, fingerprint *string) error {
+	query := tx.Model(&models.GuestBan{}).Where("event_id = ?", eventID)
+	if fingerprint != nil {
+		query = query.Where("(guest_name = ? OR client_fingerprint = ?)", guestName, *fingerprint)
+	} else {
+		query = query.Where("guest_name = ?", guestName)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check bans: %w", err)
+	}
+	if count > 0 {
+		return ErrGuestBanned
+	}
+
+	return nil
+}
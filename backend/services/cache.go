@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"snapShare/infra/cache"
+	"snapShare/models"
+)
+
+// cacheTTL bounds how stale a cached lookup can get when a change is not
+// explicitly invalidated (scheduled closes, bans, cleanup)
+const cacheTTL = 30 * time.Second
+
+const eventIDCacheKeyPrefix = "event:id:"
+
+func eventIDCacheKey(eventID uuid.UUID) string {
+	return eventIDCacheKeyPrefix + eventID.String()
+}
+
+func eventCodeCacheKey(code string) string {
+	return "event:code:" + NormalizeEventCode(code)
+}
+
+// sessionCacheKey hashes the token so bearer secrets never end up in the cache
+func sessionCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "session:" + hex.EncodeToString(sum[:])
+}
+
+// cachedEventByID loads an event through the cache
+func cachedEventByID(ctx context.Context, db *gorm.DB, c cache.Cache, eventID uuid.UUID) (*models.Event, error) {
+	var event models.Event
+	if hit, err := c.Get(ctx, eventIDCacheKey(eventID), &event); err != nil {
+		slog.WarnContext(ctx, "event cache lookup failed", "event_id", eventID, "error", err)
+	} else if hit {
+		return &event, nil
+	}
+
+	if err := db.First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	setCache(ctx, c, eventIDCacheKey(eventID), &event)
+	return &event, nil
+}
+
+// setCache stores a value, logging failures since the database stays the source of truth
+func setCache(ctx context.Context, c cache.Cache, key string, value any) {
+	if err := c.Set(ctx, key, value, cacheTTL); err != nil {
+		slog.WarnContext(ctx, "failed to write cache", "key", key, "error", err)
+	}
+}
+
+// invalidateCache drops cached entries, logging failures
+func invalidateCache(ctx context.Context, c cache.Cache, keys ...string) {
+	if err := c.Delete(ctx, keys...); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache", "error", err)
+	}
+}
+
+// invalidateEvent drops the cached lookups of an event
+func invalidateEvent(ctx context.Context, c cache.Cache, event *models.Event) {
+	invalidateCache(ctx, c, eventIDCacheKey(event.ID), eventCodeCacheKey(event.Code))
+}
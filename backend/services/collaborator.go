@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"snapShare/i18n"
+	"snapShare/infra/mail"
+	"snapShare/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyCollaborator = errors.New("email is already a collaborator on this event")
+	ErrInvitationNotFound  = errors.New("invitation not found or already accepted")
+)
+
+// Owner is a signed-in owner account. Events are theirs through
+// events.user_id, never through the owner_email they were created with.
+type Owner struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+// CollaboratorService manages co-hosts and moderators who share some of the
+// owner's rights on an event
+type CollaboratorService struct {
+	db           *gorm.DB
+	eventService *EventService
+	mailer       mail.Mailer
+}
+
+func NewCollaboratorService(db *gorm.DB, eventService *EventService, mailer mail.Mailer) *CollaboratorService {
+	return &CollaboratorService{
+		db:           db,
+		eventService: eventService,
+		mailer:       mailer,
+	}
+}
+
+// InviteCollaborator creates (or renews) an invitation and emails the accept link.
+// Re-inviting a pending email issues a fresh token and updates the role.
+func (s *CollaboratorService) InviteCollaborator(ctx context.Context, eventID uuid.UUID, email string, role models.CollaboratorRole) (*models.EventCollaborator, error) {
+	event, err := s.eventService.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if strings.EqualFold(event.OwnerEmail, email) {
+		return nil, ErrAlreadyCollaborator
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+
+	var collaborator models.EventCollaborator
+	err = s.db.WithContext(ctx).Where("event_id = ? AND email = ?", eventID, email).First(&collaborator).Error
+	switch {
+	case err == nil:
+		if collaborator.AcceptedAt != nil {
+			return nil, ErrAlreadyCollaborator
+		}
+		collaborator.Role = role
+		collaborator.InviteToken = &token
+		if err := s.db.WithContext(ctx).Model(&collaborator).Updates(map[string]any{
+			"role":         role,
+			"invite_token": token,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("failed to renew invitation: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		collaborator = models.EventCollaborator{
+			ID:          uuid.New(),
+			EventID:     eventID,
+			Email:       email,
+			Role:        role,
+			InviteToken: &token,
+		}
+		if err := s.db.WithContext(ctx).Create(&collaborator).Error; err != nil {
+			return nil, fmt.Errorf("failed to create invitation: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to get collaborator: %w", err)
+	}
+
+	// The invitation stays valid even if the email fails; the owner can re-invite
+	lang := emailLang(ctx, s.db, email)
+	body := i18n.Sprintf(lang, "You have been invited to co-host \"%s\". Accept the invitation with this link:\n%s\n",
+		event.Name, s.eventService.AppURL("/invitations/"+token))
+	if err := s.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: i18n.Sprintf(lang, "[SnapShare] Invitation to %s", event.Name),
+		Body:    body,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to send collaborator invitation", "collaborator_id", collaborator.ID, "error", err)
+	}
+
+	return &collaborator, nil
+}
+
+// AcceptInvitation marks the invitation as accepted and invalidates its token
+func (s *CollaboratorService) AcceptInvitation(ctx context.Context, token string) (*models.EventCollaborator, error) {
+	var collaborator models.EventCollaborator
+	if err := s.db.WithContext(ctx).Where("invite_token = ? AND accepted_at IS NULL", token).First(&collaborator).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.db.WithContext(ctx).Model(&collaborator).Updates(map[string]any{
+		"accepted_at":  now,
+		"invite_token": nil,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	collaborator.AcceptedAt = &now
+	collaborator.InviteToken = nil
+
+	return &collaborator, nil
+}
+
+// GetCollaboratorsByEvent lists accepted and pending collaborators of an event
+func (s *CollaboratorService) GetCollaboratorsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.EventCollaborator, error) {
+	var collaborators []models.EventCollaborator
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&collaborators).Error; err != nil {
+		return nil, fmt.Errorf("failed to get collaborators: %w", err)
+	}
+
+	return collaborators, nil
+}
+
+// RemoveCollaborator revokes a collaborator or a pending invitation
+func (s *CollaboratorService) RemoveCollaborator(ctx context.Context, eventID, collaboratorID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", collaboratorID, eventID).Delete(&models.EventCollaborator{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove collaborator: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("collaborator not found")
+	}
+
+	return nil
+}
+
+// EventCapability is a group of actions on an event. Each collaborator role
+// grants capabilities up to a level; the owner has all of them.
+type EventCapability int
+
+const (
+	// EventCapabilityView covers reading the event, its photos, guests and stats
+	EventCapabilityView EventCapability = iota
+	// EventCapabilityModerate covers hiding and deleting photos and guestbook
+	// entries, banning guests and revoking their sessions
+	EventCapabilityModerate
+	// EventCapabilityManage covers the event's settings, closing and reopening
+	// it, downloads and sessions issued by hand
+	EventCapabilityManage
+)
+
+// roleCapabilities is the highest capability each collaborator role grants.
+// Webhooks, collaborators and deleting the event are left to the owner.
+var roleCapabilities = map[models.CollaboratorRole]EventCapability{
+	models.CollaboratorRoleCoHost:    EventCapabilityManage,
+	models.CollaboratorRoleModerator: EventCapabilityModerate,
+}
+
+// CanAccessEvent reports whether owner owns the event, or collaborates on it
+// as an accepted collaborator whose role grants capability
+func (s *CollaboratorService) CanAccessEvent(ctx context.Context, eventID uuid.UUID, owner Owner, capability EventCapability) (bool, error) {
+	isOwner, err := s.IsEventOwner(ctx, eventID, owner)
+	if err != nil || isOwner {
+		return isOwner, err
+	}
+
+	var collaborator models.EventCollaborator
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN events ON events.id = event_collaborators.event_id AND events.deleted_at IS NULL").
+		Where("event_collaborators.event_id = ? AND event_collaborators.email = ? AND event_collaborators.accepted_at IS NOT NULL",
+			eventID, strings.ToLower(owner.Email)).
+		Limit(1).
+		Find(&collaborator).Error; err != nil {
+		return false, fmt.Errorf("failed to check event access: %w", err)
+	}
+	if collaborator.ID == uuid.Nil {
+		return false, nil
+	}
+
+	granted, ok := roleCapabilities[collaborator.Role]
+	return ok && capability <= granted, nil
+}
+
+// IsEventOwner reports whether owner owns the event itself, rather than co-hosting it
+func (s *CollaboratorService) IsEventOwner(ctx context.Context, eventID uuid.UUID, owner Owner) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Where("id = ? AND user_id = ?", eventID, owner.UserID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check event ownership: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// coHostedEvents selects the IDs of the events owner co-hosts. Invitations
+// are accepted through a link sent to the collaborator's email, so they are
+// matched by the account's verified email.
+func coHostedEvents(db *gorm.DB, owner Owner) *gorm.DB {
+	return db.Model(&models.EventCollaborator{}).
+		Select("event_id").
+		Where("email = ? AND accepted_at IS NOT NULL", strings.ToLower(owner.Email))
+}
+
+func generateInviteToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
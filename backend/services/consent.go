@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrConsentRequired is returned when a guest joins without accepting the current consent text
+var ErrConsentRequired = errors.New("the event's sharing terms must be accepted")
+
+type ConsentService struct {
+	db *gorm.DB
+}
+
+func NewConsentService(db *gorm.DB) *ConsentService {
+	return &ConsentService{
+		db: db,
+	}
+}
+
+// ConsentRecord is one guest's acceptance of an event's consent text
+type ConsentRecord struct {
+	SessionID      uuid.UUID
+	GuestName      string
+	ConsentVersion int
+	AcceptedAt     time.Time
+	IPAddress      string
+	UserAgent      string
+}
+
+// ConsentExport is the proof of consent for an event: every version of the
+// text and every acceptance, including those of revoked sessions
+type ConsentExport struct {
+	Terms   []models.ConsentTerms
+	Records []ConsentRecord
+}
+
+// GetConsentExport returns the consent terms and acceptances of an event
+func (s *ConsentService) GetConsentExport(ctx context.Context, eventID uuid.UUID) (*ConsentExport, error) {
+	var export ConsentExport
+
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Order("version ASC").Find(&export.Terms).Error; err != nil {
+		return nil, fmt.Errorf("failed to get consent terms: %w", err)
+	}
+
+	err := s.db.WithContext(ctx).Model(&models.Session{}).Unscoped().
+		Select("id AS session_id, guest_name, consent_version, consent_accepted_at AS accepted_at, ip_address, user_agent").
+		Where("event_id = ? AND consent_accepted_at IS NOT NULL", eventID).
+		Order("consent_accepted_at ASC").
+		Scan(&export.Records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consent records: %w", err)
+	}
+
+	return &export, nil
+}
+
+// recordConsentTerms stores a new version of an event's consent text
+func recordConsentTerms(tx *gorm.DB, eventID uuid.UUID, version int, text string) error {
+	terms := models.ConsentTerms{
+		ID:      uuid.New(),
+		EventID: eventID,
+		Version: version,
+		Text:    text,
+	}
+	if err := tx.Create(&terms).Error; err != nil {
+		return fmt.Errorf("failed to record consent terms: %w", err)
+	}
+	return nil
+}
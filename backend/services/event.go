@@ -3,69 +3,268 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
+	"mime"
+	"path"
+	"snapShare/infra/cache"
+	"snapShare/infra/storage"
 	"snapShare/models"
+	"snapShare/pagination"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrInvalidCursor   = pagination.ErrInvalidCursor
+	ErrEventNotClosed  = errors.New("event is not closed")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrEventNotFound   = errors.New("event not found or closed")
+	// ErrEventNotActive is returned when guests try to join an event that is paused
+	ErrEventNotActive = errors.New("event is not accepting guests")
+)
+
+// hotCodeTTL bounds how long an instance serves an event code from memory
+// when it misses an invalidation published by another instance.
+const hotCodeTTL = 5 * time.Second
+
 type EventService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	storage     storage.Storage
+	appBaseURL  string
+	codeLength  int
+	codeCharset string
+	cache       cache.Cache
+	webhooks    *WebhookService
+
+	// hotCodes and codeLookups absorb bursts of guests joining through the
+	// same QR code: lookups are answered from memory, and concurrent misses
+	// share one load
+	hotCodes    *cache.Local[models.Event]
+	codeLookups singleflight.Group
 }
 
-func NewEventService(db *gorm.DB) *EventService {
+func NewEventService(db *gorm.DB, store storage.Storage, c cache.Cache, webhooks *WebhookService, appBaseURL string, codeLength int, codeCharset string) *EventService {
 	return &EventService{
-		db: db,
+		db:          db,
+		storage:     store,
+		cache:       c,
+		hotCodes:    cache.NewLocal[models.Event](hotCodeTTL),
+		webhooks:    webhooks,
+		appBaseURL:  strings.TrimSuffix(appBaseURL, "/"),
+		codeLength:  codeLength,
+		codeCharset: codeCharset,
 	}
 }
 
 type CreateEventRequest struct {
-	Name        string     `json:"name" binding:"required"`
-	Description *string    `json:"description,omitempty"`
-	EventDate   *time.Time `json:"event_date,omitempty"`
-	OwnerEmail  string     `json:"owner_email" binding:"required,email"`
+	Name                string                   `json:"name" binding:"required"`
+	Description         *string                  `json:"description,omitempty"`
+	EventDate           *time.Time               `json:"event_date,omitempty"`
+	Timezone            string                   `json:"timezone,omitempty"`
+	OwnerEmail          string                   `json:"owner_email" binding:"required,email"`
+	UserID              *uuid.UUID               `json:"-"` // the owner's account
+	MaxGuests           *int                     `json:"max_guests,omitempty"`
+	MaxPhotos           *int                     `json:"max_photos,omitempty"`
+	CloseAt             *time.Time               `json:"close_at,omitempty"`
+	RetentionDays       *int                     `json:"retention_days,omitempty"`
+	SessionTTLHours     *int                     `json:"session_ttl_hours,omitempty"`
+	ConsentText         *string                  `json:"consent_text,omitempty"`
+	GuestbookPublic     bool                     `json:"guestbook_public,omitempty"`
+	RequireCaptcha      bool                     `json:"require_captcha,omitempty"`
+	AllowGuestDownloads *bool                    `json:"allow_guest_downloads,omitempty"`
+	GalleryVisibility   models.GalleryVisibility `json:"gallery_visibility,omitempty"`
+	MediaPolicy         models.MediaPolicy       `json:"media_policy,omitempty"`
+	AllowedMimeTypes    []string                 `json:"allowed_mime_types,omitempty"`
+}
+
+type DuplicateEventRequest struct {
+	Name      *string    `json:"name,omitempty"`
+	EventDate *time.Time `json:"event_date,omitempty"`
 }
 
 type UpdateEventRequest struct {
-	Name        *string             `json:"name,omitempty"`
-	Description *string             `json:"description,omitempty"`
-	EventDate   *time.Time          `json:"event_date,omitempty"`
-	Status      *models.EventStatus `json:"status,omitempty"`
+	Name                *string                   `json:"name,omitempty"`
+	Description         *string                   `json:"description,omitempty"`
+	EventDate           *time.Time                `json:"event_date,omitempty"`
+	Timezone            *string                   `json:"timezone,omitempty"`
+	Status              *models.EventStatus       `json:"status,omitempty"`
+	MaxGuests           *int                      `json:"max_guests,omitempty"`
+	MaxPhotos           *int                      `json:"max_photos,omitempty"`
+	CloseAt             *time.Time                `json:"close_at,omitempty"`
+	RetentionDays       *int                      `json:"retention_days,omitempty"`
+	SessionTTLHours     *int                      `json:"session_ttl_hours,omitempty"`
+	ConsentText         *string                   `json:"consent_text,omitempty"`
+	GuestbookPublic     *bool                     `json:"guestbook_public,omitempty"`
+	RequireCaptcha      *bool                     `json:"require_captcha,omitempty"`
+	AllowGuestDownloads *bool                     `json:"allow_guest_downloads,omitempty"`
+	GalleryVisibility   *models.GalleryVisibility `json:"gallery_visibility,omitempty"`
+	MediaPolicy         *models.MediaPolicy       `json:"media_policy,omitempty"`
+	AllowedMimeTypes    []string                  `json:"allowed_mime_types,omitempty"`
 }
 
 // CreateEvent creates a new event with a unique code
 func (s *EventService) CreateEvent(ctx context.Context, req *CreateEventRequest) (*models.Event, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	galleryVisibility := req.GalleryVisibility
+	if galleryVisibility == "" {
+		galleryVisibility = models.GalleryVisibilityGuests
+	}
+
+	mediaPolicy := req.MediaPolicy
+	if mediaPolicy == "" {
+		mediaPolicy = models.MediaPolicyImagesVideos
+	}
+	var allowedMimeTypes *string
+	if mediaPolicy == models.MediaPolicyCustom {
+		if allowedMimeTypes = joinMimeTypes(req.AllowedMimeTypes); allowedMimeTypes == nil {
+			return nil, ErrInvalidMediaPolicy
+		}
+	}
+
 	code, err := s.generateUniqueCode(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate unique code: %w", err)
 	}
 
 	event := &models.Event{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Code:        code,
-		Description: req.Description,
-		EventDate:   req.EventDate,
-		Status:      models.EventStatusActive,
-		OwnerEmail:  req.OwnerEmail,
+		ID:                  uuid.New(),
+		Name:                req.Name,
+		Code:                code,
+		Description:         req.Description,
+		EventDate:           req.EventDate,
+		Timezone:            timezone,
+		Status:              models.EventStatusActive,
+		OwnerEmail:          req.OwnerEmail,
+		UserID:              req.UserID,
+		MaxGuests:           req.MaxGuests,
+		MaxPhotos:           req.MaxPhotos,
+		CloseAt:             req.CloseAt,
+		RetentionDays:       req.RetentionDays,
+		SessionTTLHours:     req.SessionTTLHours,
+		GuestbookPublic:     req.GuestbookPublic,
+		RequireCaptcha:      req.RequireCaptcha,
+		AllowGuestDownloads: req.AllowGuestDownloads == nil || *req.AllowGuestDownloads,
+		GalleryVisibility:   galleryVisibility,
+		MediaPolicy:         mediaPolicy,
+		AllowedMimeTypes:    allowedMimeTypes,
+	}
+	if req.ConsentText != nil && *req.ConsentText != "" {
+		event.ConsentText = req.ConsentText
+		event.ConsentVersion = 1
 	}
 
-	if err := s.db.Create(event).Error; err != nil {
+	if err := s.db.WithContext(ctx).Create(event).Error; err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
 
+	// Create leaves a false bool to the column default, so write it explicitly
+	if !event.AllowGuestDownloads {
+		if err := s.db.WithContext(ctx).Model(event).Update("allow_guest_downloads", false).Error; err != nil {
+			return nil, fmt.Errorf("failed to create event: %w", err)
+		}
+	}
+
+	if event.ConsentText != nil {
+		if err := recordConsentTerms(s.db.WithContext(ctx), event.ID, event.ConsentVersion, *event.ConsentText); err != nil {
+			return nil, err
+		}
+	}
+
 	return event, nil
 }
 
+// DuplicateEvent creates a new event with a fresh code that copies the
+// source event's description, owner and settings. The name defaults to
+// "<source name> (copy)" and the event date is left unset unless given.
+func (s *EventService) DuplicateEvent(ctx context.Context, eventID uuid.UUID, req *DuplicateEventRequest) (*models.Event, error) {
+	source, err := s.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s (copy)", source.Name)
+	if req.Name != nil {
+		name = *req.Name
+	}
+
+	event, err := s.CreateEvent(ctx, &CreateEventRequest{
+		Name:                name,
+		Description:         source.Description,
+		EventDate:           req.EventDate,
+		Timezone:            source.Timezone,
+		OwnerEmail:          source.OwnerEmail,
+		UserID:              source.UserID,
+		MaxGuests:           source.MaxGuests,
+		MaxPhotos:           source.MaxPhotos,
+		RetentionDays:       source.RetentionDays,
+		SessionTTLHours:     source.SessionTTLHours,
+		ConsentText:         source.ConsentText,
+		GuestbookPublic:     source.GuestbookPublic,
+		RequireCaptcha:      source.RequireCaptcha,
+		AllowGuestDownloads: &source.AllowGuestDownloads,
+		GalleryVisibility:   source.GalleryVisibility,
+		MediaPolicy:         source.MediaPolicy,
+		AllowedMimeTypes:    AllowedMimeTypes(source),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The logo is copied under the new event's prefix, so that each event
+	// can replace or purge its own logo without affecting the other
+	event.ThemePrimaryColor = source.ThemePrimaryColor
+	event.ThemeWelcomeText = source.ThemeWelcomeText
+	if source.ThemeLogoKey != nil {
+		logoKey := logoKeyPrefix(event.ID) + path.Base(*source.ThemeLogoKey)
+		contentType := mime.TypeByExtension(path.Ext(logoKey))
+		if err := copyObject(ctx, s.storage, *source.ThemeLogoKey, logoKey, contentType); err != nil {
+			return nil, fmt.Errorf("failed to copy logo: %w", err)
+		}
+		event.ThemeLogoKey = &logoKey
+	}
+	if err := s.db.WithContext(ctx).Model(event).Select("theme_primary_color", "theme_welcome_text", "theme_logo_key").Updates(event).Error; err != nil {
+		return nil, fmt.Errorf("failed to copy theme: %w", err)
+	}
+
+	return event, nil
+}
+
+// ExistsActive reports whether an event exists and is accepting guests,
+// without loading its row
+func (s *EventService) ExistsActive(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var found int
+	err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Select("1").
+		Where("id = ? AND status = ?", eventID, models.EventStatusActive).
+		Limit(1).
+		Scan(&found).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check event: %w", err)
+	}
+	return found == 1, nil
+}
+
 // GetEventByID retrieves an event by its ID
 func (s *EventService) GetEventByID(ctx context.Context, eventID uuid.UUID) (*models.Event, error) {
 	var event models.Event
-	if err := s.db.First(&event, eventID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("event not found")
+			return nil, ErrEventNotFound
 		}
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
@@ -73,37 +272,138 @@ func (s *EventService) GetEventByID(ctx context.Context, eventID uuid.UUID) (*mo
 	return &event, nil
 }
 
-// GetEventByCode retrieves an event by its unique code
+// GetEventByCode retrieves an event by its unique code, ignoring case,
+// spaces and dashes. Lookups are cached briefly since every guest join and
+// landing page hits this.
 func (s *EventService) GetEventByCode(ctx context.Context, code string) (*models.Event, error) {
+	key := eventCodeCacheKey(code)
+	if event, ok := s.hotCodes.Get(key); ok {
+		return &event, nil
+	}
+
+	loaded, err, _ := s.codeLookups.Do(key, func() (any, error) {
+		// The load outlives the caller that started it, as others may be waiting
+		event, err := s.loadEventByCode(context.WithoutCancel(ctx), code)
+		if err != nil {
+			return nil, err
+		}
+		s.hotCodes.Set(key, *event)
+		return event, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers sharing a load each get their own copy
+	event := *loaded.(*models.Event)
+	return &event, nil
+}
+
+// loadEventByCode looks an open event up through the shared cache
+func (s *EventService) loadEventByCode(ctx context.Context, code string) (*models.Event, error) {
 	var event models.Event
-	if err := s.db.Where("code = ? AND status != ?", code, models.EventStatusClosed).First(&event).Error; err != nil {
+	if hit, err := s.cache.Get(ctx, eventCodeCacheKey(code), &event); err != nil {
+		slog.WarnContext(ctx, "event code cache lookup failed", "error", err)
+	} else if hit {
+		if event.Status == models.EventStatusClosed {
+			return nil, ErrEventNotFound
+		}
+		return &event, nil
+	}
+
+	if err := s.db.WithContext(ctx).Where("UPPER(code) = ? AND status != ?", NormalizeEventCode(code), models.EventStatusClosed).First(&event).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("event not found or closed")
+			return nil, ErrEventNotFound
 		}
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
 
+	setCache(ctx, s.cache, eventCodeCacheKey(code), &event)
 	return &event, nil
 }
 
-// GetEventsByOwner retrieves all events owned by a specific email
-func (s *EventService) GetEventsByOwner(ctx context.Context, ownerEmail string) ([]models.Event, error) {
+// EventFilter narrows down and paginates an owner's event listing
+type EventFilter struct {
+	Status models.EventStatus // empty matches every status
+	Limit  int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// EventPage is one page of an owner's events, newest first
+type EventPage struct {
+	Events     []models.Event
+	Total      int64
+	NextCursor string
+}
+
+// eventKeyset orders event listings newest first
+var eventKeyset = pagination.Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+
+// OwnerByEmail returns the owner account registered with email. Without an
+// account, only co-hosted events are found for the returned Owner.
+func (s *EventService) OwnerByEmail(ctx context.Context, email string) (Owner, error) {
+	owner := Owner{Email: strings.ToLower(email)}
+	var userIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", owner.Email).Pluck("id", &userIDs).Error; err != nil {
+		return owner, fmt.Errorf("failed to look up owner: %w", err)
+	}
+	if len(userIDs) > 0 {
+		owner.UserID = userIDs[0]
+	}
+
+	return owner, nil
+}
+
+// GetEventsByOwner retrieves a page of events owned or co-hosted by owner
+func (s *EventService) GetEventsByOwner(ctx context.Context, owner Owner, filter EventFilter) (*EventPage, error) {
+	// Co-hosted events are listed alongside the ones the account owns
+	query := s.db.WithContext(ctx).Model(&models.Event{}).
+		Where("user_id = ? OR id IN (?)", owner.UserID, coHostedEvents(s.db.WithContext(ctx), owner))
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	if filter.Cursor != "" {
+		var createdAt time.Time
+		var id uuid.UUID
+		if err := pagination.Decode(filter.Cursor, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		condition, args := eventKeyset.After(createdAt, id)
+		query = query.Where(condition, args...)
+	}
+
+	// Fetch one extra row to know whether another page follows
 	var events []models.Event
-	if err := s.db.Where("owner_email = ?", ownerEmail).
-		Order("created_at DESC").
+	if err := query.Order(eventKeyset.Order()).
+		Limit(filter.Limit + 1).
 		Find(&events).Error; err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 
-	return events, nil
+	page := &EventPage{Total: total}
+	var more bool
+	page.Events, more = pagination.Trim(events, filter.Limit)
+	if more {
+		last := page.Events[len(page.Events)-1]
+		page.NextCursor = pagination.Encode(last.CreatedAt, last.ID)
+	}
+
+	return page, nil
 }
 
 // UpdateEvent updates an existing event
 func (s *EventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, req *UpdateEventRequest) (*models.Event, error) {
 	var event models.Event
-	if err := s.db.First(&event, eventID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("event not found")
+			return nil, ErrEventNotFound
 		}
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
@@ -119,60 +419,248 @@ func (s *EventService) UpdateEvent(ctx context.Context, eventID uuid.UUID, req *
 	if req.EventDate != nil {
 		updates["event_date"] = *req.EventDate
 	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+		updates["timezone"] = *req.Timezone
+	}
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.MaxGuests != nil {
+		updates["max_guests"] = *req.MaxGuests
+	}
+	if req.MaxPhotos != nil {
+		updates["max_photos"] = *req.MaxPhotos
+	}
+	if req.CloseAt != nil {
+		updates["close_at"] = *req.CloseAt
+	}
+	if req.RetentionDays != nil {
+		updates["retention_days"] = *req.RetentionDays
+	}
+	if req.SessionTTLHours != nil {
+		updates["session_ttl_hours"] = *req.SessionTTLHours
+	}
+	if req.GuestbookPublic != nil {
+		updates["guestbook_public"] = *req.GuestbookPublic
+	}
+	if req.RequireCaptcha != nil {
+		updates["require_captcha"] = *req.RequireCaptcha
+	}
+	if req.AllowGuestDownloads != nil {
+		updates["allow_guest_downloads"] = *req.AllowGuestDownloads
+	}
+	if req.GalleryVisibility != nil {
+		updates["gallery_visibility"] = *req.GalleryVisibility
+	}
+
+	// Changing the consent text starts a new version that guests joining from
+	// now on must accept; an empty text stops asking for consent
+	var newConsentText *string
+	var newConsentVersion int
+	if req.ConsentText != nil {
+		switch {
+		case *req.ConsentText == "":
+			updates["consent_text"] = nil
+		case event.ConsentText == nil || *event.ConsentText != *req.ConsentText:
+			newConsentText = req.ConsentText
+			updates["consent_text"] = *req.ConsentText
+			updates["consent_version"] = event.ConsentVersion + 1
+			newConsentVersion = event.ConsentVersion + 1
+		}
+	}
+
+	// A custom policy needs a MIME list, either given now or already stored
+	mediaPolicy := event.MediaPolicy
+	if req.MediaPolicy != nil {
+		mediaPolicy = *req.MediaPolicy
+		updates["media_policy"] = mediaPolicy
+	}
+	if mediaPolicy == models.MediaPolicyCustom {
+		allowedMimeTypes := event.AllowedMimeTypes
+		if req.AllowedMimeTypes != nil {
+			allowedMimeTypes = joinMimeTypes(req.AllowedMimeTypes)
+		}
+		if allowedMimeTypes == nil {
+			return nil, ErrInvalidMediaPolicy
+		}
+		updates["allowed_mime_types"] = *allowedMimeTypes
+	} else if req.MediaPolicy != nil {
+		updates["allowed_mime_types"] = nil
+	}
 
 	if len(updates) > 0 {
-		if err := s.db.Model(&event).Updates(updates).Error; err != nil {
-			return nil, fmt.Errorf("failed to update event: %w", err)
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&event).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update event: %w", err)
+			}
+			if newConsentText != nil {
+				return recordConsentTerms(tx, event.ID, newConsentVersion, *newConsentText)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		s.invalidateEvent(ctx, &event)
 	}
 
 	return &event, nil
 }
 
-// DeleteEvent soft deletes an event
+// DeleteEvent soft deletes an event. Its files are purged from storage by
+// RetentionService once the deletion grace period is over.
 func (s *EventService) DeleteEvent(ctx context.Context, eventID uuid.UUID) error {
-	if err := s.db.Delete(&models.Event{}, eventID).Error; err != nil {
-		return fmt.Errorf("failed to delete event: %w", err)
+	var event models.Event
+	result := s.db.WithContext(ctx).Clauses(clause.Returning{}).Where("id = ?", eventID).Delete(&event)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete event: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.invalidateEvent(ctx, &event)
 	}
 
 	return nil
 }
 
-// CloseEvent closes an event (sets status to closed)
+// CloseEvent closes an event (sets status to closed) and, if the event has a
+// retention policy, schedules its photos to be purged RetentionDays from now
 func (s *EventService) CloseEvent(ctx context.Context, eventID uuid.UUID) error {
-	if err := s.db.Model(&models.Event{}).
-		Where("id = ?", eventID).
-		Update("status", models.EventStatusClosed).Error; err != nil {
-		return fmt.Errorf("failed to close event: %w", err)
+	var event models.Event
+	result := s.db.WithContext(ctx).Model(&event).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "code"}, {Name: "name"}}}).
+		Where("id = ? AND status != ?", eventID, models.EventStatusClosed).
+		Updates(map[string]any{
+			"status":   models.EventStatusClosed,
+			"purge_at": gorm.Expr("COALESCE(purge_at, NOW() + retention_days * INTERVAL '1 day')"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to close event: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.invalidateEvent(ctx, &event)
+		s.webhooks.Dispatch(ctx, event.ID, models.WebhookEventEventClosed, WebhookEventData{
+			Code:     event.Code,
+			Name:     event.Name,
+			ClosedAt: time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// ReopenEvent makes a closed event active again. The retention schedule set
+// on close is cancelled, and closeAt, if given, replaces the automatic close time.
+func (s *EventService) ReopenEvent(ctx context.Context, eventID uuid.UUID, closeAt *time.Time) (*models.Event, error) {
+	event, err := s.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status != models.EventStatusClosed {
+		return nil, ErrEventNotClosed
+	}
+
+	updates := map[string]any{
+		"status":              models.EventStatusActive,
+		"purge_at":            nil,
+		"retention_warned_at": nil,
+	}
+	if closeAt != nil {
+		updates["close_at"] = *closeAt
+	} else if event.CloseAt != nil && !event.CloseAt.After(time.Now()) {
+		// A close time in the past would close the event again right away
+		updates["close_at"] = nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(event).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to reopen event: %w", err)
+	}
+	s.invalidateEvent(ctx, event)
+
+	return event, nil
+}
+
+// CloseDueEvents closes active events whose close_at has passed
+func (s *EventService) CloseDueEvents(ctx context.Context) error {
+	var eventIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Where("status != ? AND close_at IS NOT NULL AND close_at <= ?", models.EventStatusClosed, time.Now()).
+		Pluck("id", &eventIDs).Error; err != nil {
+		return fmt.Errorf("failed to find events to close: %w", err)
+	}
+
+	for _, eventID := range eventIDs {
+		if err := s.CloseEvent(ctx, eventID); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// generateUniqueCode generates a unique 8-character alphanumeric code
+// invalidateEvent drops the cached lookups of an event, including this
+// instance's copy of its code
+func (s *EventService) invalidateEvent(ctx context.Context, event *models.Event) {
+	invalidateEvent(ctx, s.cache, event)
+	s.hotCodes.Delete(eventCodeCacheKey(event.Code))
+}
+
+// WatchInvalidations drops event codes invalidated by any instance from
+// memory until ctx is done
+func (s *EventService) WatchInvalidations(ctx context.Context) {
+	s.cache.Subscribe(ctx, func(keys []string) {
+		s.hotCodes.Delete(keys...)
+	})
+}
+
+// JoinURL returns the guest landing page URL for an event code
+func (s *EventService) JoinURL(code string) string {
+	return s.AppURL("/e/" + code)
+}
+
+// AppURL returns an absolute frontend URL for path
+func (s *EventService) AppURL(path string) string {
+	return s.appBaseURL + path
+}
+
+// EventLocation returns the event's timezone, falling back to UTC for
+// zones the server cannot load
+func EventLocation(event *models.Event) *time.Location {
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NormalizeEventCode uppercases a code typed by a guest and drops the spaces
+// and dashes people add when copying it from a screen
+func NormalizeEventCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// generateUniqueCode generates a unique code of the configured length and charset
 func (s *EventService) generateUniqueCode(_ context.Context) (string, error) {
-	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	const codeLength = 8
 	maxAttempts := 10
 
 	for range maxAttempts {
-		code := make([]byte, codeLength)
+		code := make([]byte, s.codeLength)
 		for i := range code {
-			num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+			num, err := rand.Int(rand.Reader, big.NewInt(int64(len(s.codeCharset))))
 			if err != nil {
 				return "", fmt.Errorf("failed to generate random number: %w", err)
 			}
-			code[i] = charset[num.Int64()]
+			code[i] = s.codeCharset[num.Int64()]
 		}
 
 		codeStr := string(code)
 
 		// Check if code already exists
 		var count int64
-		if err := s.db.Model(&models.Event{}).Where("code = ?", codeStr).Count(&count).Error; err != nil {
+		if err := s.db.Model(&models.Event{}).Where("UPPER(code) = ?", codeStr).Count(&count).Error; err != nil {
 			return "", fmt.Errorf("failed to check code uniqueness: %w", err)
 		}
 
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"snapShare/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// adjustEventCounters moves an event's photo_count and total_bytes by the
+// confirmed photos added or removed. It must run in the transaction changing
+// the photos, and leaves updated_at alone since the event itself is unchanged.
+func adjustEventCounters(tx *gorm.DB, eventID uuid.UUID, photos, bytes int64) error {
+	if photos == 0 && bytes == 0 {
+		return nil
+	}
+
+	if err := tx.Unscoped().Model(&models.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumns(map[string]any{
+			"photo_count": gorm.Expr("photo_count + ?", photos),
+			"total_bytes": gorm.Expr("total_bytes + ?", bytes),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update event counters: %w", err)
+	}
+	return nil
+}
+
+// deletePhotos soft deletes the photos matching conds in tx, takes the
+// confirmed ones off their events' counters and queues their objects for
+// deletion. It returns the deleted photos.
+func deletePhotos(tx *gorm.DB, conds ...any) ([]models.Photo, error) {
+	var deleted []models.Photo
+	if err := tx.Clauses(clause.Returning{}).Delete(&deleted, conds...).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete photo records: %w", err)
+	}
+
+	type counters struct{ photos, bytes int64 }
+	byEvent := map[uuid.UUID]*counters{}
+	keys := make([]string, 0, len(deleted))
+	for _, photo := range deleted {
+		keys = append(keys, photo.ObjectKey)
+		if photo.ConfirmedAt == nil {
+			continue
+		}
+		c, ok := byEvent[photo.EventID]
+		if !ok {
+			c = &counters{}
+			byEvent[photo.EventID] = c
+		}
+		c.photos++
+		c.bytes += photo.Size
+	}
+	for eventID, c := range byEvent {
+		if err := adjustEventCounters(tx, eventID, -c.photos, -c.bytes); err != nil {
+			return nil, err
+		}
+	}
+	if err := queueObjectDeletions(tx, keys...); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
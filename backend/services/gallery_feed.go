@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"snapShare/infra/cache"
+	"snapShare/models"
+)
+
+// Gallery update types pushed to live gallery clients
+const (
+	GalleryUpdatePhotoUploaded = "photo.uploaded"
+	GalleryUpdatePhotoDeleted  = "photo.deleted"
+)
+
+// galleryFeedBuffer is how many updates a subscriber may fall behind before
+// further updates are dropped for it
+const galleryFeedBuffer = 32
+
+// galleryFeedTopic is the bus topic carrying gallery updates between instances
+const galleryFeedTopic = "gallery"
+
+// GalleryUpdate is a change to an event's gallery. For deletions only the
+// photo's ID and EventID are set.
+type GalleryUpdate struct {
+	Type  string       `json:"type"`
+	Photo models.Photo `json:"photo"`
+}
+
+// GalleryFeed is a pub/sub of gallery changes per event. Subscribers are
+// served in process; updates reach the subscribers on other API instances
+// through the bus.
+type GalleryFeed struct {
+	bus         cache.Bus
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan GalleryUpdate]struct{}
+}
+
+func NewGalleryFeed(bus cache.Bus) *GalleryFeed {
+	return &GalleryFeed{
+		bus:         bus,
+		subscribers: make(map[uuid.UUID]map[chan GalleryUpdate]struct{}),
+	}
+}
+
+// Subscribe returns a channel of the event's gallery updates and a function
+// that ends the subscription
+func (f *GalleryFeed) Subscribe(eventID uuid.UUID) (<-chan GalleryUpdate, func()) {
+	ch := make(chan GalleryUpdate, galleryFeedBuffer)
+
+	f.mu.Lock()
+	if f.subscribers[eventID] == nil {
+		f.subscribers[eventID] = make(map[chan GalleryUpdate]struct{})
+	}
+	f.subscribers[eventID][ch] = struct{}{}
+	f.mu.Unlock()
+
+	unsubscribe := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subscribers[eventID], ch)
+		if len(f.subscribers[eventID]) == 0 {
+			delete(f.subscribers, eventID)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends an update to every subscriber of the photo's event, on this
+// instance and the others. Subscribers that are too slow miss it and can
+// catch up through the slideshow endpoint.
+func (f *GalleryFeed) Publish(ctx context.Context, update GalleryUpdate) {
+	f.deliver(update)
+
+	if err := f.bus.Broadcast(ctx, galleryFeedTopic, update); err != nil {
+		slog.ErrorContext(ctx, "failed to broadcast gallery update", "photo_id", update.Photo.ID, "error", err)
+	}
+}
+
+// Watch delivers the updates published on other instances to this
+// instance's subscribers until ctx is done
+func (f *GalleryFeed) Watch(ctx context.Context) {
+	f.bus.Listen(ctx, galleryFeedTopic, func(message []byte) {
+		var update GalleryUpdate
+		if err := json.Unmarshal(message, &update); err != nil {
+			slog.ErrorContext(ctx, "failed to decode gallery update", "error", err)
+			return
+		}
+		f.deliver(update)
+	})
+}
+
+// deliver sends an update to this instance's subscribers without blocking
+func (f *GalleryFeed) deliver(update GalleryUpdate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subscribers[update.Photo.EventID] {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
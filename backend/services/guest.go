@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"snapShare/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// findOrCreateGuest returns the guest a joining device belongs to. A device
+// that joined under the same name before rejoins its guest, and a device
+// holding a session of a guest (linkedGuestID) joins that guest. Anyone else
+// becomes a new guest, even under a name already taken, so that picking a
+// name grants no rights over another guest's uploads or sessions.
+func findOrCreateGuest(tx *gorm.DB, eventID uuid.UUID, displayName string, fingerprint *string, linkedGuestID *uuid.UUID) (*models.Guest, error) {
+	var guest models.Guest
+	if linkedGuestID != nil {
+		if err := tx.Where("id = ? AND event_id = ?", *linkedGuestID, eventID).First(&guest).Error; err != nil {
+			return nil, fmt.Errorf("failed to get guest: %w", err)
+		}
+		return &guest, nil
+	}
+
+	if fingerprint != nil {
+		if err := tx.Where("event_id = ? AND display_name = ? AND client_fingerprint = ?", eventID, displayName, *fingerprint).
+			Limit(1).
+			Find(&guest).Error; err != nil {
+			return nil, fmt.Errorf("failed to get guest: %w", err)
+		}
+		if guest.ID != uuid.Nil {
+			return &guest, nil
+		}
+	}
+
+	guest = models.Guest{
+		ID:                uuid.New(),
+		EventID:           eventID,
+		DisplayName:       displayName,
+		ClientFingerprint: fingerprint,
+	}
+	if err := tx.Create(&guest).Error; err != nil {
+		return nil, fmt.Errorf("failed to create guest: %w", err)
+	}
+
+	return &guest, nil
+}
+
+// guestIDByName returns the ID of the event's first guest with the given
+// display name, or nil when nobody joined under that name
+func guestIDByName(tx *gorm.DB, eventID uuid.UUID, displayName string) (*uuid.UUID, error) {
+	var guest models.Guest
+	err := tx.Select("id").Where("event_id = ? AND display_name = ?", eventID, displayName).Order("created_at").Limit(1).Find(&guest).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guest: %w", err)
+	}
+
+	if guest.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &guest.ID, nil
+}
+
+// IsOwnUpload reports whether a photo was uploaded by the session's guest.
+// Uploads from before guests were tracked fall back to the uploader name.
+func IsOwnUpload(session *models.Session, photo *models.Photo) bool {
+	if session.GuestID != nil && photo.GuestID != nil {
+		return *session.GuestID == *photo.GuestID
+	}
+	return session.GuestName == photo.UploaderName
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"snapShare/models"
+	"snapShare/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GuestbookService struct {
+	db *gorm.DB
+}
+
+func NewGuestbookService(db *gorm.DB) *GuestbookService {
+	return &GuestbookService{
+		db: db,
+	}
+}
+
+// CreateEntry leaves a guestbook message on behalf of a session holder
+func (s *GuestbookService) CreateEntry(ctx context.Context, session *models.Session, message string) (*models.GuestbookEntry, error) {
+	entry := &models.GuestbookEntry{
+		ID:        uuid.New(),
+		EventID:   session.EventID,
+		SessionID: session.ID,
+		GuestName: session.GuestName,
+		Message:   message,
+	}
+
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to create guestbook entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GuestbookFilter narrows down and paginates a guestbook listing
+type GuestbookFilter struct {
+	// GuestName, when set, returns only that guest's own entries
+	GuestName *string
+	Limit     int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// GuestbookPage is one page of an event's guestbook, newest first
+type GuestbookPage struct {
+	Entries    []models.GuestbookEntry
+	NextCursor string
+}
+
+// guestbookKeyset orders guestbook listings newest first
+var guestbookKeyset = pagination.Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+
+// GetEntriesByEvent lists a page of an event's guestbook, newest first
+func (s *GuestbookService) GetEntriesByEvent(ctx context.Context, eventID uuid.UUID, filter GuestbookFilter) (*GuestbookPage, error) {
+	query := s.db.WithContext(ctx).Where("event_id = ?", eventID)
+	if filter.GuestName != nil {
+		query = query.Where("guest_name = ?", *filter.GuestName)
+	}
+	if filter.Cursor != "" {
+		var createdAt time.Time
+		var id uuid.UUID
+		if err := pagination.Decode(filter.Cursor, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		condition, args := guestbookKeyset.After(createdAt, id)
+		query = query.Where(condition, args...)
+	}
+
+	var entries []models.GuestbookEntry
+	if err := query.Order(guestbookKeyset.Order()).Limit(filter.Limit + 1).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get guestbook entries: %w", err)
+	}
+
+	page := &GuestbookPage{}
+	var more bool
+	page.Entries, more = pagination.Trim(entries, filter.Limit)
+	if more {
+		last := page.Entries[len(page.Entries)-1]
+		page.NextCursor = pagination.Encode(last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+// IsPublic reports whether guests may read the whole guestbook of an event
+func (s *GuestbookService) IsPublic(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var event models.Event
+	if err := s.db.WithContext(ctx).Select("guestbook_public").First(&event, eventID).Error; err != nil {
+		return false, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return event.GuestbookPublic, nil
+}
+
+// DeleteEntry removes a guestbook entry of an event
+func (s *GuestbookService) DeleteEntry(ctx context.Context, eventID, entryID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", entryID, eventID).Delete(&models.GuestbookEntry{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete guestbook entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("guestbook entry not found")
+	}
+
+	return nil
+}
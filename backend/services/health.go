@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"snapShare/infra/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// healthCheckTimeout bounds each dependency check so a hanging dependency
+// fails the probe instead of stalling it
+const healthCheckTimeout = 3 * time.Second
+
+type HealthService struct {
+	db      *gorm.DB
+	storage storage.Storage
+}
+
+func NewHealthService(db *gorm.DB, store storage.Storage) *HealthService {
+	return &HealthService{
+		db:      db,
+		storage: store,
+	}
+}
+
+// DependencyStatus is the outcome of checking one dependency
+type DependencyStatus struct {
+	Name    string
+	Latency time.Duration
+	Err     error
+}
+
+// CheckDependencies pings the database and the R2 bucket
+func (s *HealthService) CheckDependencies(ctx context.Context) []DependencyStatus {
+	return []DependencyStatus{
+		runHealthCheck(ctx, "database", s.pingDatabase),
+		runHealthCheck(ctx, "storage", s.storage.Ping),
+	}
+}
+
+func (s *HealthService) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.db.WithContext(ctx).DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func runHealthCheck(ctx context.Context, name string, check func(context.Context) error) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	return DependencyStatus{Name: name, Latency: time.Since(start), Err: err}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"snapShare/infra/webhooksig"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrUnknownWebhookSource is returned for callbacks from a source without secrets
+	ErrUnknownWebhookSource = errors.New("unknown webhook source")
+	// ErrWebhookReplayed is returned for a callback that was already received
+	ErrWebhookReplayed = errors.New("webhook was already received")
+)
+
+// inboundWebhookRetention keeps receipts well past the signature tolerance,
+// after which a replay is rejected by its timestamp alone
+const inboundWebhookRetention = 24 * time.Hour
+
+// InboundWebhookHandler processes a verified callback. Returning an error
+// rolls the receipt back so that the sender's retry is accepted.
+type InboundWebhookHandler func(ctx context.Context, webhook *models.InboundWebhook) error
+
+// InboundWebhookService is the single ingress of signed callbacks from
+// storage notifications, payment providers and other integrations
+type InboundWebhookService struct {
+	db        *gorm.DB
+	verifiers map[string]*webhooksig.Verifier
+	handlers  map[string]InboundWebhookHandler
+}
+
+func NewInboundWebhookService(db *gorm.DB, secrets map[string][]string) *InboundWebhookService {
+	verifiers := make(map[string]*webhooksig.Verifier, len(secrets))
+	for source, sourceSecrets := range secrets {
+		verifiers[source] = webhooksig.NewVerifier(webhooksig.DefaultTolerance, sourceSecrets...)
+	}
+
+	return &InboundWebhookService{
+		db:        db,
+		verifiers: verifiers,
+		handlers:  map[string]InboundWebhookHandler{},
+	}
+}
+
+// Handle registers the handler of a source's callbacks. Callbacks of sources
+// without a handler are verified and recorded only.
+func (s *InboundWebhookService) Handle(source string, handler InboundWebhookHandler) {
+	s.handlers[source] = handler
+}
+
+// Receive verifies a callback against the source's secrets, rejects replays
+// and passes it to the source's handler
+func (s *InboundWebhookService) Receive(ctx context.Context, source string, header http.Header, body []byte) (*models.InboundWebhook, error) {
+	verifier, ok := s.verifiers[source]
+	if !ok {
+		return nil, ErrUnknownWebhookSource
+	}
+
+	signedAt, err := verifier.Verify(header, body, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Senders without delivery IDs are deduplicated by their signature,
+	// which is unique per timestamp and body
+	deliveryID := header.Get(webhooksig.HeaderDelivery)
+	if deliveryID == "" {
+		deliveryID = header.Get(webhooksig.HeaderSignature)
+	}
+
+	webhook := &models.InboundWebhook{
+		ID:         uuid.New(),
+		Source:     source,
+		DeliveryID: deliveryID,
+		Payload:    string(body),
+		SignedAt:   signedAt,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(webhook)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record webhook: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookReplayed
+		}
+
+		if handler, ok := s.handlers[source]; ok {
+			if err := handler(ctx, webhook); err != nil {
+				return fmt.Errorf("failed to handle %s webhook: %w", source, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Run deletes expired receipts every interval until ctx is done
+func (s *InboundWebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "inbound_webhooks", s.PurgeReceipts); err != nil {
+			slog.ErrorContext(ctx, "inbound webhook purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeReceipts deletes receipts too old to be needed for replay protection
+func (s *InboundWebhookService) PurgeReceipts(ctx context.Context) error {
+	if err := s.db.WithContext(ctx).
+		Where("created_at < ?", time.Now().Add(-inboundWebhookRetention)).
+		Delete(&models.InboundWebhook{}).Error; err != nil {
+		return fmt.Errorf("failed to purge webhook receipts: %w", err)
+	}
+	return nil
+}
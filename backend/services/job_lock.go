@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// runExclusive runs a tick of the background job name unless another
+// instance is running one, so that every instance can start the same loops.
+// The job holds a session-level advisory lock, which Postgres releases if
+// the instance dies, on a connection of its own while the tick runs.
+func runExclusive(ctx context.Context, db *gorm.DB, name string, tick func(context.Context) error) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", "snapshare:"+name).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", name, err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext($1))", "snapshare:"+name); err != nil {
+			slog.ErrorContext(ctx, "failed to unlock background job", "job", name, "error", err)
+		}
+	}()
+
+	return tick(ctx)
+}
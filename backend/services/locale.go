@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"snapShare/i18n"
+	"snapShare/models"
+
+	"gorm.io/gorm"
+)
+
+// emailLang is the language of emails to address: the locale of the owner
+// account registered with it, else the language of the request being served,
+// else Japanese, the language of most users
+func emailLang(ctx context.Context, db *gorm.DB, address string) i18n.Lang {
+	var user models.User
+	err := db.WithContext(ctx).Select("locale").Where("email = LOWER(?)", address).Take(&user).Error
+	if err == nil {
+		if lang, ok := i18n.Parse(user.Locale); ok {
+			return lang
+		}
+	}
+	return i18n.FromContext(ctx, i18n.Japanese)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"mime"
+	"snapShare/models"
+	"strings"
+)
+
+var (
+	// ErrMediaTypeNotAllowed is returned when an upload's content type is not
+	// permitted by the event's media policy
+	ErrMediaTypeNotAllowed = errors.New("media type is not allowed for this event")
+	// ErrInvalidMediaPolicy is returned when a custom media policy has no MIME types
+	ErrInvalidMediaPolicy = errors.New("custom media policy requires allowed_mime_types")
+)
+
+// AllowedMimeTypes returns the custom MIME list of an event
+func AllowedMimeTypes(event *models.Event) []string {
+	if event.AllowedMimeTypes == nil || *event.AllowedMimeTypes == "" {
+		return nil
+	}
+	return strings.Split(*event.AllowedMimeTypes, ",")
+}
+
+// checkMediaType verifies contentType is accepted by the event's media policy.
+// Custom lists may contain exact types or wildcards such as "video/*".
+func checkMediaType(event *models.Event, contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ErrMediaTypeNotAllowed
+	}
+
+	var allowed []string
+	switch event.MediaPolicy {
+	case models.MediaPolicyImages:
+		allowed = []string{"image/*"}
+	case models.MediaPolicyCustom:
+		allowed = AllowedMimeTypes(event)
+	default:
+		allowed = []string{"image/*", "video/*"}
+	}
+
+	for _, pattern := range allowed {
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return nil
+			}
+		} else if mediaType == pattern {
+			return nil
+		}
+	}
+
+	return ErrMediaTypeNotAllowed
+}
+
+// joinMimeTypes normalizes a custom MIME list for storage
+func joinMimeTypes(mimeTypes []string) *string {
+	normalized := make([]string, 0, len(mimeTypes))
+	for _, t := range mimeTypes {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			normalized = append(normalized, t)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	joined := strings.Join(normalized, ",")
+	return &joined
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+)
+
+var (
+	// ErrMediaBusy is returned when too many media jobs are already waiting
+	ErrMediaBusy = errors.New("media processing is busy, try again shortly")
+	// ErrImageTooLarge is returned for images whose decoded pixels would not
+	// fit in the memory allowed per job
+	ErrImageTooLarge = errors.New("image is too large to process")
+)
+
+// decodedBytesPerPixel is what one pixel costs once decoded to RGBA, the
+// largest in-memory form images take
+const decodedBytesPerPixel = 4
+
+// MediaPoolConfig sizes the MediaPool. MaxConcurrent jobs run at once and up
+// to QueueDepth more wait for a worker; jobs beyond that are turned away.
+// MaxJobBytes caps the decoded size of an image a job may load.
+type MediaPoolConfig struct {
+	MaxConcurrent int
+	QueueDepth    int
+	MaxJobBytes   int64
+}
+
+// MediaPool bounds the CPU and memory spent decoding and rendering images,
+// so that a burst of gallery views cannot run the server out of memory
+type MediaPool struct {
+	// queue holds a token for every job admitted, running or waiting
+	queue       chan struct{}
+	workers     chan struct{}
+	maxJobBytes int64
+}
+
+// NewMediaPool returns a pool sized by cfg
+func NewMediaPool(cfg MediaPoolConfig) *MediaPool {
+	return &MediaPool{
+		queue:       make(chan struct{}, cfg.MaxConcurrent+cfg.QueueDepth),
+		workers:     make(chan struct{}, cfg.MaxConcurrent),
+		maxJobBytes: cfg.MaxJobBytes,
+	}
+}
+
+// Do runs job once a worker is free. It returns ErrMediaBusy at once when the
+// queue is full, and ctx's error if ctx ends while waiting.
+func (p *MediaPool) Do(ctx context.Context, job func() error) error {
+	select {
+	case p.queue <- struct{}{}:
+	default:
+		return ErrMediaBusy
+	}
+	defer func() { <-p.queue }()
+
+	select {
+	case p.workers <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.workers }()
+
+	return job()
+}
+
+// Decode decodes an image after checking from its header that it fits in
+// the memory allowed per job
+func (p *MediaPool) Decode(r io.Reader) (image.Image, error) {
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if int64(cfg.Width)*int64(cfg.Height)*decodedBytesPerPixel > p.maxJobBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, r))
+	return img, err
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MetricsService struct {
+	db *gorm.DB
+}
+
+func NewMetricsService(db *gorm.DB) *MetricsService {
+	return &MetricsService{
+		db: db,
+	}
+}
+
+// EventStorageMetric is the storage footprint of a single event
+type EventStorageMetric struct {
+	EventID       uuid.UUID
+	StorageBytes  int64
+	PhotoCount    int64
+	PendingPhotos int64
+}
+
+// StorageTotals is the storage footprint of every event together
+type StorageTotals struct {
+	StorageBytes int64
+	PhotoCount   int64
+}
+
+// CollectEventStorage reads the confirmed bytes and photos of active events
+// from their counters, with the uploads in flight to them. Events drop out
+// once they stop taking uploads, so the series do not grow with every event
+// ever held; CollectStorageTotals still counts them.
+func (s *MetricsService) CollectEventStorage(ctx context.Context) ([]EventStorageMetric, error) {
+	var metrics []EventStorageMetric
+	err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Select(`id AS event_id, total_bytes AS storage_bytes, photo_count,
+			(SELECT COUNT(*) FROM photos
+				WHERE photos.event_id = events.id AND photos.confirmed_at IS NULL
+					AND photos.deleted_at IS NULL AND photos.created_at > ?) AS pending_photos`,
+			time.Now().Add(-uploadURLExpiry)).
+		Where("status = ?", models.EventStatusActive).
+		Order("id").
+		Scan(&metrics).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect storage metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// CollectStorageTotals sums the counters of every event
+func (s *MetricsService) CollectStorageTotals(ctx context.Context) (*StorageTotals, error) {
+	var totals StorageTotals
+	err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Select("COALESCE(SUM(total_bytes), 0) AS storage_bytes, COALESCE(SUM(photo_count), 0) AS photo_count").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect storage totals: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// QueueMetrics is the backlog of the background delivery queues
+type QueueMetrics struct {
+	// PendingWebhookDeliveries are queued or being retried
+	PendingWebhookDeliveries int64
+	// RetryingWebhookDeliveries have failed at least once and are due for another attempt
+	RetryingWebhookDeliveries int64
+	// FailedWebhookDeliveries ran out of attempts
+	FailedWebhookDeliveries int64
+	// PendingObjectDeletions are storage objects still to be removed
+	PendingObjectDeletions int64
+}
+
+// CollectQueues counts the webhook deliveries and object deletions still queued
+func (s *MetricsService) CollectQueues(ctx context.Context) (*QueueMetrics, error) {
+	var metrics QueueMetrics
+	if err := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Select(`COUNT(*) FILTER (WHERE status = ?) AS pending_webhook_deliveries,
+			COUNT(*) FILTER (WHERE status = ? AND attempts > 0) AS retrying_webhook_deliveries,
+			COUNT(*) FILTER (WHERE status = ?) AS failed_webhook_deliveries`,
+			models.WebhookDeliveryPending, models.WebhookDeliveryPending, models.WebhookDeliveryFailed).
+		Scan(&metrics).Error; err != nil {
+		return nil, fmt.Errorf("failed to collect webhook metrics: %w", err)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.ObjectDeletion{}).
+		Count(&metrics.PendingObjectDeletions).Error; err != nil {
+		return nil, fmt.Errorf("failed to collect object deletion metrics: %w", err)
+	}
+
+	return &metrics, nil
+}
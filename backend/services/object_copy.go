@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"snapShare/infra/storage"
+)
+
+// copyObject copies an object to dstKey through this server, since not every
+// storage provider can copy server-side. It is used when a record moves to
+// or is duplicated into another event, so that the object lives under the
+// event it belongs to and is purged with it.
+func copyObject(ctx context.Context, store storage.Storage, srcKey, dstKey, contentType string) error {
+	body, err := store.GetObject(ctx, srcKey)
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+	defer body.Close()
+
+	if err := store.UploadMultipart(ctx, dstKey, contentType, body); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"snapShare/infra/storage"
+	"snapShare/models"
+)
+
+const (
+	// objectDeletionBatchSize is how many queued objects a tick deletes
+	objectDeletionBatchSize = 5000
+	// objectDeletionLease keeps claimed entries from other workers while
+	// their objects are deleted, and delays the retry when that fails
+	objectDeletionLease = 5 * time.Minute
+)
+
+// ObjectDeletionService removes the storage objects of deleted photos. The
+// objects are queued in the transaction deleting the records, so a failed
+// delete never loses track of them, and Run drains the queue.
+type ObjectDeletionService struct {
+	db      *gorm.DB
+	storage storage.Storage
+}
+
+func NewObjectDeletionService(db *gorm.DB, store storage.Storage) *ObjectDeletionService {
+	return &ObjectDeletionService{
+		db:      db,
+		storage: store,
+	}
+}
+
+// queueObjectDeletions queues objects for deletion in tx
+func queueObjectDeletions(tx *gorm.DB, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	deletions := make([]models.ObjectDeletion, len(keys))
+	for i, key := range keys {
+		deletions[i] = models.ObjectDeletion{ObjectKey: key, NextAttemptAt: now}
+	}
+	if err := tx.Create(&deletions).Error; err != nil {
+		return fmt.Errorf("failed to queue object deletions: %w", err)
+	}
+	return nil
+}
+
+// Run deletes queued objects every interval until ctx is cancelled
+func (s *ObjectDeletionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "object_deletion", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "object deletion run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick claims a batch of queued objects, deletes them from storage and
+// drops them from the queue. Entries of a failed batch are retried once
+// their lease runs out.
+func (s *ObjectDeletionService) Tick(ctx context.Context) error {
+	now := time.Now()
+	due := s.db.WithContext(ctx).Model(&models.ObjectDeletion{}).
+		Select("id").
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(objectDeletionBatchSize).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var deletions []models.ObjectDeletion
+	if err := s.db.WithContext(ctx).Model(&deletions).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("next_attempt_at", now.Add(objectDeletionLease)).Error; err != nil {
+		return fmt.Errorf("failed to claim object deletions: %w", err)
+	}
+	if len(deletions) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(deletions))
+	for i, deletion := range deletions {
+		keys[i] = deletion.ObjectKey
+	}
+	if err := s.storage.DeleteObjects(ctx, keys); err != nil {
+		return fmt.Errorf("failed to delete objects: %w", err)
+	}
+
+	if err := s.db.WithContext(ctx).Delete(&deletions).Error; err != nil {
+		return fmt.Errorf("failed to dequeue object deletions: %w", err)
+	}
+	return nil
+}
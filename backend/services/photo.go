@@ -2,28 +2,64 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"snapShare/infra/r2"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log/slog"
+	"snapShare/infra/storage"
 	"snapShare/models"
+	"snapShare/pagination"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrPhotoNotFound is returned when a photo does not exist
+var ErrPhotoNotFound = errors.New("photo not found")
+
+// ErrGuestDownloadsDisabled is returned when a guest asks for original files
+// of an event whose owner turned guest downloads off
+var ErrGuestDownloadsDisabled = errors.New("guest downloads are disabled for this event")
+
 type PhotoService struct {
-	db        *gorm.DB
-	r2Service *r2.R2Service
+	db       *gorm.DB
+	storage  storage.Storage
+	feed     *GalleryFeed
+	webhooks *WebhookService
+	media    *MediaPool
 }
 
-func NewPhotoService(db *gorm.DB, r2Service *r2.R2Service) *PhotoService {
+func NewPhotoService(db *gorm.DB, store storage.Storage, feed *GalleryFeed, webhooks *WebhookService, media *MediaPool) *PhotoService {
 	return &PhotoService{
-		db:        db,
-		r2Service: r2Service,
+		db:       db,
+		storage:  store,
+		feed:     feed,
+		webhooks: webhooks,
+		media:    media,
 	}
 }
 
+// uploadURLExpiry is how long a presigned upload URL stays valid. Photos that
+// are still unconfirmed within this window count as in flight.
+const uploadURLExpiry = 15 * time.Minute
+
+// presignConcurrency bounds the presign requests of a bulk upload in flight
+const presignConcurrency = 10
+
+// dimensionConcurrency bounds the photos of a bulk confirm whose dimensions
+// are read at once; the media pool bounds the work across all requests
+const dimensionConcurrency = 4
+
 // Service layer data structures (internal use only)
 type UploadInfo struct {
 	UploadURL string
@@ -34,6 +70,7 @@ type UploadInfo struct {
 type FileSpec struct {
 	ContentType string
 	Size        int64
+	Caption     *string
 }
 
 type BulkUploadResult struct {
@@ -41,25 +78,48 @@ type BulkUploadResult struct {
 	BatchID string
 }
 
-type DownloadInfo struct {
-	DownloadURL string
-	ExpiresAt   time.Time
-	PhotoCount  int
+// PhotoFilter narrows down, orders and paginates a gallery listing
+type PhotoFilter struct {
+	// Query matches case-insensitively against caption and uploader name
+	Query string
+	// Sort is one of created_at, taken_at, size or uploader (default created_at)
+	Sort string
+	// Order is asc or desc (default desc)
+	Order string
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
 }
 
-func (s *PhotoService) GenerateUploadURL(ctx context.Context, eventID uuid.UUID, uploaderName, contentType string) (*UploadInfo, error) {
-	var event models.Event
-	if err := s.db.First(&event, eventID).Error; err != nil {
-		return nil, fmt.Errorf("event not found: %w", err)
+// PhotoPage is one page of a gallery listing
+type PhotoPage struct {
+	Photos     []models.Photo
+	NextCursor string
+}
+
+// photoSortColumns maps public sort keys to photo columns
+var photoSortColumns = map[string]string{
+	"created_at": "created_at",
+	"taken_at":   "taken_at",
+	"size":       "size",
+	"uploader":   "uploader_name",
+}
+
+func (s *PhotoService) GenerateUploadURL(ctx context.Context, event *models.Event, uploaderName string, guestID *uuid.UUID, contentType string, caption *string) (*UploadInfo, error) {
+	eventID := event.ID
+	if err := checkMediaType(event, contentType); err != nil {
+		return nil, err
 	}
 
 	// Generate photo ID and object key
 	photoID := uuid.New()
-	ext := getExtensionFromContentType(contentType)
-	objectKey := fmt.Sprintf("events/%s/photos/%s%s", eventID, photoID, ext)
+	objectKey, err := photoObjectKey(eventID, photoID, getExtensionFromContentType(contentType))
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate presigned URL (15 minutes expiry)
-	uploadURL, err := s.r2Service.GeneratePresignedUploadURL(ctx, objectKey, contentType, 15*time.Minute)
+	// Generate presigned URL
+	uploadURL, err := s.storage.GeneratePresignedUploadURL(ctx, objectKey, contentType, uploadURLExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate upload URL: %w", err)
 	}
@@ -69,13 +129,29 @@ func (s *PhotoService) GenerateUploadURL(ctx context.Context, eventID uuid.UUID,
 		ID:           photoID,
 		EventID:      eventID,
 		UploaderName: uploaderName,
+		GuestID:      guestID,
 		ObjectKey:    objectKey,
 		MimeType:     contentType,
+		Caption:      caption,
 		Size:         0, // Will be updated after upload
 	}
 
-	if err := s.db.Create(&photo).Error; err != nil {
-		return nil, fmt.Errorf("failed to create photo record: %w", err)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Quotas are checked against the locked row rather than the caller's copy
+		locked := models.Event{ID: eventID}
+		if err := lockEvent(tx, &locked); err != nil {
+			return err
+		}
+		if err := checkPhotoQuota(tx, &locked, 1); err != nil {
+			return err
+		}
+		if err := tx.Create(&photo).Error; err != nil {
+			return fmt.Errorf("failed to create photo record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &UploadInfo{
@@ -85,31 +161,305 @@ func (s *PhotoService) GenerateUploadURL(ctx context.Context, eventID uuid.UUID,
 	}, nil
 }
 
-func (s *PhotoService) ConfirmUpload(ctx context.Context, photoID uuid.UUID, fileSize int64) error {
-	return s.db.Model(&models.Photo{}).
-		Where("id = ?", photoID).
-		Update("size", fileSize).Error
+// ConfirmUpload records the uploaded file size and extracts image metadata
+// for a photo of the event
+func (s *PhotoService) ConfirmUpload(ctx context.Context, eventID, photoID uuid.UUID, fileSize int64, takenAt *time.Time) error {
+	db := s.db.WithContext(ctx)
+
+	var photo models.Photo
+	if err := db.Where("id = ? AND event_id = ?", photoID, eventID).First(&photo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPhotoNotFound
+		}
+		return fmt.Errorf("failed to get photo: %w", err)
+	}
+
+	updates := map[string]any{"size": fileSize, "confirmed_at": time.Now()}
+	if takenAt != nil {
+		updates["taken_at"] = *takenAt
+	}
+
+	// Dimensions are best effort; formats like HEIC can't be decoded here, and
+	// a busy media pool leaves them out rather than failing the confirm
+	if width, height, err := s.pooledImageDimensions(ctx, photo.ObjectKey); err != nil {
+		slog.WarnContext(ctx, "failed to read photo dimensions", "photo_id", photoID, "error", err)
+	} else {
+		updates["width"] = width
+		updates["height"] = height
+	}
+
+	// Only the first confirm announces the photo; repeats only correct its size
+	var firstConfirm bool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Lock the photo so that a repeated confirm only corrects its size
+		var current models.Photo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "confirmed_at", "size").
+			First(&current, photoID).Error; err != nil {
+			return fmt.Errorf("photo not found: %w", err)
+		}
+		if err := tx.Model(&photo).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to confirm upload: %w", err)
+		}
+
+		firstConfirm = current.ConfirmedAt == nil
+		photos, bytes := int64(0), fileSize-current.Size
+		if firstConfirm {
+			photos, bytes = 1, fileSize
+		}
+		if err := adjustEventCounters(tx, photo.EventID, photos, bytes); err != nil {
+			return err
+		}
+		return InvalidateEventArchive(tx, photo.EventID)
+	})
+	if err != nil {
+		return err
+	}
+	if !firstConfirm {
+		return nil
+	}
+
+	if photo.HiddenAt == nil {
+		s.feed.Publish(ctx, GalleryUpdate{Type: GalleryUpdatePhotoUploaded, Photo: photo})
+	}
+	s.webhooks.Dispatch(ctx, photo.EventID, models.WebhookEventPhotoUploaded, webhookPhotoData(&photo))
+	return nil
+}
+
+// CountInFlightUploads counts photos of an event whose upload URL was issued
+// but not yet confirmed while the URL is still valid
+func (s *PhotoService) CountInFlightUploads(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("event_id = ? AND confirmed_at IS NULL AND created_at > ?", eventID, time.Now().Add(-uploadURLExpiry)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count in-flight uploads: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountInFlightUploadsByEvent counts the in-flight uploads of several
+// events, keyed by event ID. Events without any are left out.
+func (s *PhotoService) CountInFlightUploadsByEvent(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		EventID uuid.UUID
+		Count   int64
+	}
+	err := s.db.WithContext(ctx).Model(&models.Photo{}).
+		Select("event_id, COUNT(*) AS count").
+		Where("event_id IN ? AND confirmed_at IS NULL AND created_at > ?", eventIDs, time.Now().Add(-uploadURLExpiry)).
+		Group("event_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count in-flight uploads: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetPhotoByID retrieves a single photo's metadata
+func (s *PhotoService) GetPhotoByID(ctx context.Context, photoID uuid.UUID) (*models.Photo, error) {
+	var photo models.Photo
+	if err := s.db.WithContext(ctx).First(&photo, photoID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("failed to get photo: %w", err)
+	}
+
+	return &photo, nil
+}
+
+// GetPhotosByIDs returns the photos of an event among photoIDs, in no
+// particular order. IDs of other events or of no photo are skipped.
+func (s *PhotoService) GetPhotosByIDs(ctx context.Context, eventID uuid.UUID, photoIDs []uuid.UUID) ([]models.Photo, error) {
+	var photos []models.Photo
+	if err := s.db.WithContext(ctx).Where("event_id = ? AND id IN ?", eventID, photoIDs).Find(&photos).Error; err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
+	}
+
+	return photos, nil
+}
+
+// GuestDownloadsAllowed reports whether guests may fetch original files of an event
+func (s *PhotoService) GuestDownloadsAllowed(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	return guestDownloadsAllowed(s.db.WithContext(ctx), eventID)
+}
+
+func guestDownloadsAllowed(db *gorm.DB, eventID uuid.UUID) (bool, error) {
+	var event models.Event
+	if err := db.Select("allow_guest_downloads").First(&event, eventID).Error; err != nil {
+		return false, fmt.Errorf("event not found: %w", err)
+	}
+
+	return event.AllowGuestDownloads, nil
+}
+
+// PublicURL returns the public URL for an object key. It is derived from the
+// key alone, so callers compute it where they render a photo instead of the
+// service rewriting ObjectKey.
+func (s *PhotoService) PublicURL(objectKey string) string {
+	return s.storage.GetPublicURL(objectKey)
 }
 
-func (s *PhotoService) GetPhotosByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Photo, error) {
+// GetPhotosByEvent returns a page of an event's visible photos
+func (s *PhotoService) GetPhotosByEvent(ctx context.Context, eventID uuid.UUID, filter PhotoFilter) (*PhotoPage, error) {
+	query, order, err := s.photoListQuery(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	var photos []models.Photo
-	err := s.db.Where("event_id = ?", eventID).
-		Order("created_at DESC").
+	err = query.Where("event_id = ?", eventID).
+		Order(order.keyset.Order()).
+		Limit(filter.Limit + 1).
 		Find(&photos).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get photos: %w", err)
 	}
 
-	for i := range photos {
-		photos[i].ObjectKey = s.r2Service.GetPublicURL(photos[i].ObjectKey)
+	return order.page(photos, filter.Limit), nil
+}
+
+// GetPhotosByEvents returns the first page of each event's gallery for the
+// same filter in one query, keyed by event ID. Events without matching
+// photos get an empty page.
+func (s *PhotoService) GetPhotosByEvents(ctx context.Context, eventIDs []uuid.UUID, filter PhotoFilter) (map[uuid.UUID]*PhotoPage, error) {
+	query, order, err := s.photoListQuery(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	return photos, nil
+	ranked := query.Where("event_id IN ?", eventIDs).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY " + order.keyset.Order() + ") AS photo_rank")
+	var photos []models.Photo
+	err = s.db.WithContext(ctx).Table("(?) AS photos", ranked).
+		Where("photo_rank <= ?", filter.Limit+1).
+		Order("event_id, photo_rank").
+		Find(&photos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
+	}
+
+	byEvent := make(map[uuid.UUID][]models.Photo, len(eventIDs))
+	for _, photo := range photos {
+		byEvent[photo.EventID] = append(byEvent[photo.EventID], photo)
+	}
+	pages := make(map[uuid.UUID]*PhotoPage, len(eventIDs))
+	for _, eventID := range eventIDs {
+		pages[eventID] = order.page(byEvent[eventID], filter.Limit)
+	}
+
+	return pages, nil
+}
+
+// photoListQuery selects the visible photos matching filter, from the
+// filter's cursor on, and returns the order to list them in
+func (s *PhotoService) photoListQuery(ctx context.Context, filter PhotoFilter) (*gorm.DB, *photoSort, error) {
+	query := s.db.WithContext(ctx).Model(&models.Photo{}).Where("hidden_at IS NULL")
+
+	// Search is served by the trigram indexes created in database.Migrate
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := "%" + escapeLikePattern(q) + "%"
+		query = query.Where("(caption ILIKE ? OR uploader_name ILIKE ?)", pattern, pattern)
+	}
+
+	order, err := newPhotoSort(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter.Cursor != "" {
+		condition, args, err := order.after(filter.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	return query, order, nil
+}
+
+// GalleryVersion returns a cheap token that changes whenever a photo of the
+// event is added, changed or removed, or the event itself is updated
+func (s *PhotoService) GalleryVersion(ctx context.Context, eventID uuid.UUID) (string, error) {
+	var version struct {
+		Count           int64
+		PhotosUpdatedAt *time.Time
+		EventUpdatedAt  *time.Time
+	}
+	err := s.db.WithContext(ctx).Model(&models.Photo{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS photos_updated_at, (SELECT updated_at FROM events WHERE id = ?) AS event_updated_at", eventID).
+		Where("event_id = ?", eventID).
+		Scan(&version).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to get gallery version: %w", err)
+	}
+
+	var photosUpdatedAt, eventUpdatedAt int64
+	if version.PhotosUpdatedAt != nil {
+		photosUpdatedAt = version.PhotosUpdatedAt.UnixMicro()
+	}
+	if version.EventUpdatedAt != nil {
+		eventUpdatedAt = version.EventUpdatedAt.UnixMicro()
+	}
+	return fmt.Sprintf("%d-%d-%d", version.Count, photosUpdatedAt, eventUpdatedAt), nil
+}
+
+// slideshowKeyset follows confirmation order. A bulk upload confirms all of
+// its photos at the same instant, so the ID keeps a cursor from skipping the
+// rest of a batch that a page ended in.
+var slideshowKeyset = pagination.Keyset{Columns: []string{"confirmed_at", "id"}}
+
+// SlideshowPage is a batch of slideshow photos. NextCursor follows its last
+// photo, or is the requested cursor when no photos were added since.
+type SlideshowPage struct {
+	Photos     []models.Photo
+	NextCursor string
+}
+
+// GetSlideshowPhotos returns confirmed photos in the order they were confirmed,
+// at most limit photos. They follow cursor when it is set, or else start
+// after since when that is set; since is only for a first request, as a page
+// ending inside a bulk upload would lose the rest of it.
+func (s *PhotoService) GetSlideshowPhotos(ctx context.Context, eventID uuid.UUID, since *time.Time, cursor string, limit int) (*SlideshowPage, error) {
+	query := s.db.WithContext(ctx).Where("event_id = ? AND confirmed_at IS NOT NULL AND hidden_at IS NULL", eventID)
+	switch {
+	case cursor != "":
+		var confirmedAt time.Time
+		var id uuid.UUID
+		if err := pagination.Decode(cursor, &confirmedAt, &id); err != nil {
+			return nil, err
+		}
+		condition, args := slideshowKeyset.After(confirmedAt, id)
+		query = query.Where(condition, args...)
+	case since != nil:
+		query = query.Where("confirmed_at > ?", *since)
+	}
+
+	page := &SlideshowPage{NextCursor: cursor}
+	if err := query.Order(slideshowKeyset.Order()).
+		Limit(limit).
+		Find(&page.Photos).Error; err != nil {
+		return nil, fmt.Errorf("failed to get slideshow photos: %w", err)
+	}
+
+	if len(page.Photos) > 0 {
+		last := page.Photos[len(page.Photos)-1]
+		page.NextCursor = pagination.Encode(*last.ConfirmedAt, last.ID)
+	}
+
+	return page, nil
 }
 
 func (s *PhotoService) DeletePhoto(ctx context.Context, photoID uuid.UUID, userCanDelete bool) error {
 	var photo models.Photo
-	if err := s.db.First(&photo, photoID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&photo, photoID).Error; err != nil {
 		return fmt.Errorf("photo not found: %w", err)
 	}
 
@@ -117,87 +467,118 @@ func (s *PhotoService) DeletePhoto(ctx context.Context, photoID uuid.UUID, userC
 		return fmt.Errorf("unauthorized to delete photo")
 	}
 
-	// Generate presigned delete URL
-	deleteURL, err := s.r2Service.GeneratePresignedDeleteURL(ctx, photo.ObjectKey, 5*time.Minute)
+	// Soft delete from database; the object is deleted from storage once
+	// this commits
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := deletePhotos(tx, "id = ?", photo.ID); err != nil {
+			return err
+		}
+		return InvalidateEventArchive(tx, photo.EventID)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to generate delete URL: %w", err)
-	}
-
-	// Soft delete from database
-	if err := s.db.Delete(&photo).Error; err != nil {
-		return fmt.Errorf("failed to delete photo record: %w", err)
+		return err
 	}
 
-	// Note: In a real implementation, you might want to queue the actual R2 deletion
-	// or handle it asynchronously to ensure the database operation succeeds first
-	_ = deleteURL // For now, just acknowledge we have the URL
+	s.feed.Publish(ctx, GalleryUpdate{Type: GalleryUpdatePhotoDeleted, Photo: photo})
+	s.webhooks.Dispatch(ctx, photo.EventID, models.WebhookEventPhotoDeleted, webhookPhotoData(&photo))
 
 	return nil
 }
 
 // GenerateBulkUploadURLs generates multiple presigned upload URLs for bulk photo upload
-func (s *PhotoService) GenerateBulkUploadURLs(ctx context.Context, eventID uuid.UUID, uploaderName string, files []FileSpec) (*BulkUploadResult, error) {
-	// Validate event exists
-	var event models.Event
-	if err := s.db.First(&event, eventID).Error; err != nil {
-		return nil, fmt.Errorf("event not found: %w", err)
-	}
+func (s *PhotoService) GenerateBulkUploadURLs(ctx context.Context, event *models.Event, uploaderName string, guestID *uuid.UUID, files []FileSpec) (*BulkUploadResult, error) {
+	eventID := event.ID
 
 	// Generate batch ID for tracking
-	batchID := uuid.New().String()
+	batchID := uuid.New()
 
 	// Limit bulk upload size (e.g., max 50 files per batch)
 	if len(files) > 50 {
 		return nil, fmt.Errorf("too many files: maximum 50 files per batch")
 	}
 
-	uploads := make([]UploadInfo, 0, len(files))
-	photoRecords := make([]models.Photo, 0, len(files))
-
-	// Generate URLs and create photo records
 	for _, fileSpec := range files {
-		photoID := uuid.New()
-		ext := getExtensionFromContentType(fileSpec.ContentType)
-		objectKey := fmt.Sprintf("events/%s/photos/%s%s", eventID, photoID, ext)
+		if err := checkMediaType(event, fileSpec.ContentType); err != nil {
+			return nil, err
+		}
+	}
 
-		// Generate presigned URL
-		uploadURL, err := s.r2Service.GeneratePresignedUploadURL(ctx, objectKey, fileSpec.ContentType, 15*time.Minute)
+	uploads := make([]UploadInfo, len(files))
+	photoRecords := make([]models.Photo, len(files))
+	for i, fileSpec := range files {
+		photoID := uuid.New()
+		objectKey, err := photoObjectKey(eventID, photoID, getExtensionFromContentType(fileSpec.ContentType))
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate upload URL for file: %w", err)
+			return nil, err
 		}
 
-		uploads = append(uploads, UploadInfo{
-			UploadURL: uploadURL,
+		uploads[i] = UploadInfo{
 			ObjectKey: objectKey,
 			PhotoID:   photoID,
-		})
-
-		// Prepare photo record
-		photoRecords = append(photoRecords, models.Photo{
+		}
+		photoRecords[i] = models.Photo{
 			ID:           photoID,
 			EventID:      eventID,
 			UploaderName: uploaderName,
+			GuestID:      guestID,
 			ObjectKey:    objectKey,
 			MimeType:     fileSpec.ContentType,
+			Caption:      fileSpec.Caption,
 			Size:         fileSpec.Size,
+			BatchID:      &batchID,
+		}
+	}
+
+	// Presigning is a round trip per file, so sign a few files at a time
+	// instead of one after another
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(presignConcurrency)
+	for i, fileSpec := range files {
+		g.Go(func() error {
+			uploadURL, err := s.storage.GeneratePresignedUploadURL(gctx, uploads[i].ObjectKey, fileSpec.ContentType, uploadURLExpiry)
+			if err != nil {
+				return fmt.Errorf("failed to generate upload URL for file: %w", err)
+			}
+			uploads[i].UploadURL = uploadURL
+			return nil
 		})
 	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
 
-	// Batch insert photo records
-	if err := s.db.Create(&photoRecords).Error; err != nil {
-		return nil, fmt.Errorf("failed to create photo records: %w", err)
+	// Insert all photo records in one statement
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Quotas are checked against the locked row rather than the caller's copy
+		locked := models.Event{ID: eventID}
+		if err := lockEvent(tx, &locked); err != nil {
+			return err
+		}
+		if err := checkPhotoQuota(tx, &locked, len(photoRecords)); err != nil {
+			return err
+		}
+		// Inserted in batches of the connection's CreateBatchSize
+		if err := tx.Create(&photoRecords).Error; err != nil {
+			return fmt.Errorf("failed to create photo records: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &BulkUploadResult{
 		Uploads: uploads,
-		BatchID: batchID,
+		BatchID: batchID.String(),
 	}, nil
 }
 
-// ConfirmBulkUpload confirms multiple photo uploads with their actual file sizes
-func (s *PhotoService) ConfirmBulkUpload(ctx context.Context, confirmations map[string]int64) error {
+// ConfirmBulkUpload confirms multiple photo uploads of the event with their
+// actual file sizes. It returns the IDs that are not pending photos of the
+// event, which are left untouched.
+func (s *PhotoService) ConfirmBulkUpload(ctx context.Context, eventID uuid.UUID, confirmations map[string]int64) ([]string, error) {
 	if len(confirmations) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Convert photoID strings to UUIDs for batch update
@@ -205,59 +586,136 @@ func (s *PhotoService) ConfirmBulkUpload(ctx context.Context, confirmations map[
 	for photoIDStr := range confirmations {
 		photoID, err := uuid.Parse(photoIDStr)
 		if err != nil {
-			return fmt.Errorf("invalid photo ID: %s", photoIDStr)
+			return nil, fmt.Errorf("invalid photo ID: %s", photoIDStr)
 		}
 		photoIDs = append(photoIDs, photoID)
 	}
 
-	// Update sizes in batch - Note: GORM doesn't support batch updates with different values easily
-	// So we'll do individual updates in a transaction
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		for photoIDStr, size := range confirmations {
-			photoID, _ := uuid.Parse(photoIDStr) // Already validated above
-			if err := tx.Model(&models.Photo{}).
-				Where("id = ?", photoID).
-				Update("size", size).Error; err != nil {
-				return fmt.Errorf("failed to update photo %s size: %w", photoIDStr, err)
-			}
+	dimensions, err := s.readBulkDimensions(ctx, eventID, photoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update all sizes, dimensions and the events' counters in one statement,
+	// joining the new values as a VALUES list
+	var notFound []string
+	rows := make([]string, 0, len(confirmations))
+	args := make([]any, 0, 4*len(confirmations)+2)
+	for photoIDStr, size := range confirmations {
+		d, ok := dimensions[uuid.MustParse(photoIDStr)]
+		if !ok {
+			notFound = append(notFound, photoIDStr)
+			continue
 		}
-		return nil
+		var width, height *int
+		if d != nil {
+			width, height = &d.width, &d.height
+		}
+		rows = append(rows, "(?::uuid, ?::bigint, ?::integer, ?::integer)")
+		args = append(args, photoIDStr, size, width, height)
+	}
+	if len(rows) == 0 {
+		return notFound, nil
+	}
+	args = append(args, eventID, time.Now())
+	// newIDs are the photos this call confirmed first; only those are announced
+	var newIDs []uuid.UUID
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// old locks the photos and reads their latest state, so that photos
+		// confirmed before, or concurrently, only correct their size
+		if err := tx.Raw(`WITH v (id, size, width, height) AS (
+				VALUES `+strings.Join(rows, ", ")+`
+			), old AS (
+				SELECT id, confirmed_at, size FROM photos
+				WHERE id IN (SELECT id FROM v) AND event_id = ? AND deleted_at IS NULL
+				ORDER BY id
+				FOR UPDATE
+			), confirmed AS (
+				UPDATE photos
+				SET size = v.size, width = COALESCE(v.width, photos.width), height = COALESCE(v.height, photos.height),
+					confirmed_at = ?, updated_at = NOW()
+				FROM v JOIN old ON old.id = v.id
+				WHERE photos.id = v.id
+				RETURNING photos.id, photos.event_id,
+					CASE WHEN old.confirmed_at IS NULL THEN 1 ELSE 0 END AS photos,
+					v.size - CASE WHEN old.confirmed_at IS NULL THEN 0 ELSE old.size END AS bytes
+			), counted AS (
+				UPDATE events
+				SET photo_count = events.photo_count + c.photos, total_bytes = events.total_bytes + c.bytes
+				FROM (SELECT event_id, SUM(photos) AS photos, SUM(bytes) AS bytes FROM confirmed GROUP BY event_id) AS c
+				WHERE events.id = c.event_id
+			)
+			SELECT id FROM confirmed WHERE photos = 1`, args...).
+			Scan(&newIDs).Error; err != nil {
+			return fmt.Errorf("failed to confirm photos: %w", err)
+		}
+		return InvalidateEventArchive(tx, eventID)
 	})
-}
-
-// GenerateBulkDownloadURL creates a zip archive of all photos in an event and returns download URL
-func (s *PhotoService) GenerateBulkDownloadURL(ctx context.Context, eventID uuid.UUID) (*DownloadInfo, error) {
-	// Get all photos for the event
-	photos, err := s.GetPhotosByEvent(ctx, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get photos: %w", err)
+		return nil, err
+	}
+	if len(newIDs) == 0 {
+		return notFound, nil
 	}
 
-	if len(photos) == 0 {
-		return nil, fmt.Errorf("no photos found for event")
+	// The uploads are confirmed at this point, so a failed lookup only costs
+	// live viewers and webhooks an update
+	var photos []models.Photo
+	if err := s.db.WithContext(ctx).Where("id IN ?", newIDs).Find(&photos).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to load confirmed photos for notifications", "error", err)
+		return notFound, nil
+	}
+	for i := range photos {
+		photo := &photos[i]
+		if photo.HiddenAt == nil {
+			s.feed.Publish(ctx, GalleryUpdate{Type: GalleryUpdatePhotoUploaded, Photo: *photo})
+		}
+		s.webhooks.Dispatch(ctx, photo.EventID, models.WebhookEventPhotoUploaded, webhookPhotoData(photo))
 	}
 
-	// Generate unique archive name
-	archiveKey := fmt.Sprintf("events/%s/archives/%s.zip", eventID, uuid.New())
+	return notFound, nil
+}
 
-	// Note: In a real implementation, you would:
-	// 1. Create a background job to generate the zip file
-	// 2. Stream photos from R2 and create zip archive
-	// 3. Upload the zip to R2
-	// 4. Return presigned download URL
+type imageDimensions struct {
+	width, height int
+}
 
-	// For now, we'll generate a presigned URL that expires in 1 hour
-	// This assumes the zip creation process is handled elsewhere
-	downloadURL, err := s.r2Service.GeneratePresignedDownloadURL(ctx, archiveKey, 1*time.Hour)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate download URL: %w", err)
+// readBulkDimensions reads the dimensions of the event's photos by ID. Every
+// photo found gets an entry; like in ConfirmUpload the dimensions are best
+// effort, and are nil for photos that cannot be decoded or that the media
+// pool turns away.
+func (s *PhotoService) readBulkDimensions(ctx context.Context, eventID uuid.UUID, photoIDs []uuid.UUID) (map[uuid.UUID]*imageDimensions, error) {
+	var photos []models.Photo
+	if err := s.db.WithContext(ctx).Select("id", "object_key").
+		Where("id IN ? AND event_id = ?", photoIDs, eventID).
+		Find(&photos).Error; err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
 	}
 
-	return &DownloadInfo{
-		DownloadURL: downloadURL,
-		ExpiresAt:   time.Now().Add(1 * time.Hour),
-		PhotoCount:  len(photos),
-	}, nil
+	var mu sync.Mutex
+	dimensions := make(map[uuid.UUID]*imageDimensions, len(photos))
+	for _, photo := range photos {
+		dimensions[photo.ID] = nil
+	}
+	var g errgroup.Group
+	g.SetLimit(dimensionConcurrency)
+	for _, photo := range photos {
+		g.Go(func() error {
+			width, height, err := s.pooledImageDimensions(ctx, photo.ObjectKey)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to read photo dimensions", "photo_id", photo.ID, "error", err)
+				return nil
+			}
+
+			mu.Lock()
+			dimensions[photo.ID] = &imageDimensions{width: width, height: height}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return dimensions, nil
 }
 
 // DeleteBulkPhotos deletes multiple photos at once
@@ -268,37 +726,80 @@ func (s *PhotoService) DeleteBulkPhotos(ctx context.Context, photoIDs []uuid.UUI
 
 	// Verify all photos belong to the event
 	var count int64
-	if err := s.db.Model(&models.Photo{}).
+	if err := s.db.WithContext(ctx).Model(&models.Photo{}).
 		Where("id IN ? AND event_id = ?", photoIDs, eventID).
 		Count(&count).Error; err != nil {
 		return fmt.Errorf("failed to verify photos: %w", err)
 	}
 
 	if count != int64(len(photoIDs)) {
-		return fmt.Errorf("some photos not found or don't belong to this event")
+		return ErrPhotoNotFound
 	}
 
-	// Get photo object keys for R2 deletion
+	// Soft delete from database; the objects are deleted from storage once
+	// this commits
 	var photos []models.Photo
-	if err := s.db.Select("object_key").
-		Where("id IN ?", photoIDs).
-		Find(&photos).Error; err != nil {
-		return fmt.Errorf("failed to get photo object keys: %w", err)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted, err := deletePhotos(tx, "id IN ?", photoIDs)
+		if err != nil {
+			return err
+		}
+		photos = deleted
+		return InvalidateEventArchive(tx, eventID)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Delete from database first (soft delete)
-	if err := s.db.Where("id IN ?", photoIDs).Delete(&models.Photo{}).Error; err != nil {
-		return fmt.Errorf("failed to delete photo records: %w", err)
+	for i := range photos {
+		s.feed.Publish(ctx, GalleryUpdate{Type: GalleryUpdatePhotoDeleted, Photo: photos[i]})
+		s.webhooks.Dispatch(ctx, eventID, models.WebhookEventPhotoDeleted, webhookPhotoData(&photos[i]))
 	}
 
-	// Note: In a real implementation, you would queue R2 deletions
-	// or handle them asynchronously to ensure database consistency
-	for _, photo := range photos {
-		_, _ = s.r2Service.GeneratePresignedDeleteURL(ctx, photo.ObjectKey, 5*time.Minute)
-		// Queue actual deletion or handle asynchronously
+	return nil
+}
+
+// imageHeaderBytes is how much of an object is fetched to decode its dimensions.
+// JPEG frame headers can sit behind large EXIF blocks, so this is generous.
+const imageHeaderBytes = 256 * 1024
+
+// pooledImageDimensions reads the dimensions of an object on the media pool,
+// so that decoding competes for the same workers as every other media job
+func (s *PhotoService) pooledImageDimensions(ctx context.Context, objectKey string) (width, height int, err error) {
+	err = s.media.Do(ctx, func() error {
+		var err error
+		width, height, err = s.readImageDimensions(ctx, objectKey)
+		return err
+	})
+	return width, height, err
+}
+
+// readImageDimensions decodes the width and height from the start of an object
+func (s *PhotoService) readImageDimensions(ctx context.Context, objectKey string) (int, int, error) {
+	body, err := s.storage.GetObjectRange(ctx, objectKey, 0, imageHeaderBytes-1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read object: %w", err)
 	}
+	defer body.Close()
 
-	return nil
+	cfg, _, err := image.DecodeConfig(body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
+
+// photoObjectKey names the original of a photo. Buckets may serve objects
+// publicly, and event and photo IDs are visible to anyone with the event
+// code, so the random suffix keeps originals unguessable; it only reaches
+// callers allowed to download.
+func photoObjectKey(eventID, photoID uuid.UUID, ext string) (string, error) {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate object key: %w", err)
+	}
+	return fmt.Sprintf("events/%s/photos/%s-%s%s", eventID, photoID, hex.EncodeToString(suffix), ext), nil
 }
 
 func getExtensionFromContentType(contentType string) string {
@@ -315,7 +816,133 @@ func getExtensionFromContentType(contentType string) string {
 		return ".heic"
 	case strings.HasPrefix(contentType, "image/heif"):
 		return ".heif"
+	case strings.HasPrefix(contentType, "video/mp4"):
+		return ".mp4"
+	case strings.HasPrefix(contentType, "video/quicktime"):
+		return ".mov"
+	case strings.HasPrefix(contentType, "video/webm"):
+		return ".webm"
 	default:
 		return ".jpg"
 	}
 }
+
+// photoSort is the order of a gallery listing. Photos without the sort value
+// (e.g. no EXIF capture time) are placed last, and created_at/id keep the
+// order stable between requests.
+type photoSort struct {
+	key    string
+	desc   bool
+	keyset pagination.Keyset
+}
+
+func newPhotoSort(filter PhotoFilter) (*photoSort, error) {
+	sortKey := filter.Sort
+	if sortKey == "" {
+		sortKey = "created_at"
+	}
+	column, ok := photoSortColumns[sortKey]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+
+	desc := true
+	switch strings.ToLower(filter.Order) {
+	case "", "desc":
+	case "asc":
+		desc = false
+	default:
+		return nil, fmt.Errorf("invalid order: %s", filter.Order)
+	}
+
+	columns := []string{"created_at", "id"}
+	if column != "created_at" {
+		columns = append([]string{column}, columns...)
+	}
+	return &photoSort{
+		key:    sortKey,
+		desc:   desc,
+		keyset: pagination.Keyset{Columns: columns, Desc: desc, NullableFirst: column == "taken_at"},
+	}, nil
+}
+
+// page trims photos fetched with one extra row to limit and sets the cursor
+// of the next page when there is one
+func (p *photoSort) page(photos []models.Photo, limit int) *PhotoPage {
+	page := &PhotoPage{}
+	var more bool
+	page.Photos, more = pagination.Trim(photos, limit)
+	if more {
+		page.NextCursor = p.cursor(page.Photos[len(page.Photos)-1])
+	}
+	return page
+}
+
+// cursor encodes the position after photo. The sort is part of the cursor so
+// that it cannot be replayed against a different order.
+func (p *photoSort) cursor(photo models.Photo) string {
+	values := []any{p.key, p.desc}
+	switch p.key {
+	case "taken_at":
+		values = append(values, photo.TakenAt)
+	case "size":
+		values = append(values, photo.Size)
+	case "uploader":
+		values = append(values, photo.UploaderName)
+	}
+	return pagination.Encode(append(values, photo.CreatedAt, photo.ID)...)
+}
+
+// after returns the condition selecting the photos that follow cursor
+func (p *photoSort) after(cursor string) (string, []any, error) {
+	var (
+		key       string
+		desc      bool
+		takenAt   *time.Time
+		size      int64
+		uploader  string
+		createdAt time.Time
+		id        uuid.UUID
+	)
+	dst := []any{&key, &desc}
+	switch p.key {
+	case "taken_at":
+		dst = append(dst, &takenAt)
+	case "size":
+		dst = append(dst, &size)
+	case "uploader":
+		dst = append(dst, &uploader)
+	}
+	if err := pagination.Decode(cursor, append(dst, &createdAt, &id)...); err != nil {
+		return "", nil, err
+	}
+	if key != p.key || desc != p.desc {
+		return "", nil, ErrInvalidCursor
+	}
+
+	var values []any
+	switch p.key {
+	case "taken_at":
+		values = append(values, takenAt)
+	case "size":
+		values = append(values, size)
+	case "uploader":
+		values = append(values, uploader)
+	}
+	condition, args := p.keyset.After(append(values, createdAt, id)...)
+	return condition, args, nil
+}
+
+// lockEvent re-reads an event with a row lock so concurrent quota checks are serialized
+func lockEvent(tx *gorm.DB, event *models.Event) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(event, event.ID).Error; err != nil {
+		return fmt.Errorf("event not found: %w", err)
+	}
+	return nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input is matched literally
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"snapShare/models"
+	"snapShare/pagination"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrGalleryNotPublic is returned for events that do not share a public gallery
+	ErrGalleryNotPublic = errors.New("gallery is not public")
+	// ErrGalleryPrivate is returned when a guest lists the gallery of a private event
+	ErrGalleryPrivate = errors.New("gallery is private for this event")
+	// ErrImageNotRenderable is returned for photos that cannot be decoded for watermarking
+	ErrImageNotRenderable = errors.New("photo cannot be rendered")
+)
+
+const (
+	// publicImageMaxEdge caps the long edge of public gallery images
+	publicImageMaxEdge = 1600
+	// watermarkStripeSpacing and watermarkStripeWidth shape the diagonal
+	// stripes drawn over public images
+	watermarkStripeSpacing = 96
+	watermarkStripeWidth   = 8
+
+	// publicGalleryChunkSize is how many photos EachPublicPhoto loads per query
+	publicGalleryChunkSize = 500
+)
+
+// publicGalleryKeyset orders public galleries newest first
+var publicGalleryKeyset = pagination.Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+
+// GetGalleryVisibility returns who may browse an event's gallery
+func (s *PhotoService) GetGalleryVisibility(ctx context.Context, eventID uuid.UUID) (models.GalleryVisibility, error) {
+	var event models.Event
+	if err := s.db.WithContext(ctx).Select("gallery_visibility").First(&event, eventID).Error; err != nil {
+		return "", fmt.Errorf("event not found: %w", err)
+	}
+
+	return event.GalleryVisibility, nil
+}
+
+// GetPublicGallery returns an event with a public gallery. Its photos are
+// listed with EachPublicPhoto.
+func (s *PhotoService) GetPublicGallery(ctx context.Context, code string) (*models.Event, error) {
+	return s.publicEvent(code)
+}
+
+// EachPublicPhoto calls fn with the visible, confirmed photos of an event,
+// newest first. Photos are loaded a chunk at a time, paging by keyset, so a
+// gallery of thousands of photos never sits in memory as a whole.
+func (s *PhotoService) EachPublicPhoto(ctx context.Context, eventID uuid.UUID, fn func(photo *models.Photo) error) error {
+	var after *models.Photo
+	for {
+		query := s.db.WithContext(ctx).Where("event_id = ? AND confirmed_at IS NOT NULL AND hidden_at IS NULL", eventID)
+		if after != nil {
+			condition, args := publicGalleryKeyset.After(after.CreatedAt, after.ID)
+			query = query.Where(condition, args...)
+		}
+
+		var photos []models.Photo
+		if err := query.Order(publicGalleryKeyset.Order()).
+			Limit(publicGalleryChunkSize).
+			Find(&photos).Error; err != nil {
+			return fmt.Errorf("failed to get photos: %w", err)
+		}
+
+		for i := range photos {
+			if err := fn(&photos[i]); err != nil {
+				return err
+			}
+		}
+		if len(photos) < publicGalleryChunkSize {
+			return nil
+		}
+		after = &photos[len(photos)-1]
+	}
+}
+
+// GetPublicGalleryModifiedAt returns when the public gallery of an event last
+// changed: the event itself, or any photo being added, edited or deleted
+func (s *PhotoService) GetPublicGalleryModifiedAt(ctx context.Context, code string) (time.Time, error) {
+	event, err := s.publicEvent(code)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var photos struct {
+		UpdatedAt *time.Time
+		DeletedAt *time.Time
+	}
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Photo{}).
+		Select("MAX(updated_at) AS updated_at, MAX(deleted_at) AS deleted_at").
+		Where("event_id = ?", event.ID).
+		Scan(&photos).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to get gallery modification time: %w", err)
+	}
+
+	modifiedAt := event.UpdatedAt
+	for _, t := range []*time.Time{photos.UpdatedAt, photos.DeletedAt} {
+		if t != nil && t.After(modifiedAt) {
+			modifiedAt = *t
+		}
+	}
+	return modifiedAt, nil
+}
+
+// GetWatermarkedPhoto renders a downscaled, watermarked JPEG of a photo in a
+// public gallery. The original object is never exposed.
+func (s *PhotoService) GetWatermarkedPhoto(ctx context.Context, code string, photoID uuid.UUID) ([]byte, error) {
+	event, err := s.publicEvent(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var photo models.Photo
+	if err := s.db.WithContext(ctx).Where("id = ? AND event_id = ? AND confirmed_at IS NOT NULL AND hidden_at IS NULL", photoID, event.ID).
+		First(&photo).Error; err != nil {
+		return nil, fmt.Errorf("photo not found: %w", err)
+	}
+
+	// The object is only fetched once a worker is free, so waiting jobs
+	// hold no memory
+	var buf bytes.Buffer
+	err = s.media.Do(ctx, func() error {
+		body, err := s.storage.GetObject(ctx, photo.ObjectKey)
+		if err != nil {
+			return fmt.Errorf("failed to read object: %w", err)
+		}
+		defer body.Close()
+
+		src, err := s.media.Decode(body)
+		if errors.Is(err, ErrImageTooLarge) {
+			return err
+		}
+		if err != nil {
+			return ErrImageNotRenderable
+		}
+
+		if err := jpeg.Encode(&buf, watermark(src), &jpeg.Options{Quality: 80}); err != nil {
+			return fmt.Errorf("failed to encode image: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *PhotoService) publicEvent(code string) (*models.Event, error) {
+	var event models.Event
+	if err := s.db.Where("UPPER(code) = ?", NormalizeEventCode(code)).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGalleryNotPublic
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event.GalleryVisibility != models.GalleryVisibilityPublic {
+		return nil, ErrGalleryNotPublic
+	}
+
+	return &event, nil
+}
+
+// watermark downscales src to publicImageMaxEdge (nearest neighbour) and
+// blends translucent white diagonal stripes over it
+func watermark(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if long := max(width, height); long > publicImageMaxEdge {
+		width = width * publicImageMaxEdge / long
+		height = height * publicImageMaxEdge / long
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			c := color.RGBAModel.Convert(src.At(sx, sy)).(color.RGBA)
+			if (x+y)%watermarkStripeSpacing < watermarkStripeWidth {
+				c.R = blendWhite(c.R)
+				c.G = blendWhite(c.G)
+				c.B = blendWhite(c.B)
+			}
+			dst.SetRGBA(x, y, c)
+		}
+	}
+
+	return dst
+}
+
+// blendWhite mixes a channel with 40% white
+func blendWhite(v uint8) uint8 {
+	return uint8((int(v)*60 + 255*40) / 100)
+}
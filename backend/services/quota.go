@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"snapShare/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrGuestLimitReached is returned when an event already has MaxGuests guests
+	ErrGuestLimitReached = errors.New("guest limit reached for this event")
+	// ErrPhotoLimitReached is returned when an event already has MaxPhotos photos
+	ErrPhotoLimitReached = errors.New("photo limit reached for this event")
+	// ErrPhotoQuotaExceeded is returned when a batch is larger than the remaining photo quota
+	ErrPhotoQuotaExceeded = errors.New("upload exceeds the remaining photo quota for this event")
+)
+
+// checkGuestQuota verifies a new guest may join. Guests rejoining under a name
+// that already has a session do not count against the limit.
+func checkGuestQuota(tx *gorm.DB, event *models.Event, guestName string) error {
+	if event.MaxGuests == nil {
+		return nil
+	}
+
+	var existing int64
+	if err := tx.Model(&models.Session{}).
+		Where("event_id = ? AND guest_name = ?", event.ID, guestName).
+		Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to check guest quota: %w", err)
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var guests int64
+	if err := tx.Model(&models.Session{}).
+		Where("event_id = ?", event.ID).
+		Distinct("guest_name").
+		Count(&guests).Error; err != nil {
+		return fmt.Errorf("failed to check guest quota: %w", err)
+	}
+
+	if guests >= int64(*event.MaxGuests) {
+		return ErrGuestLimitReached
+	}
+
+	return nil
+}
+
+// checkPhotoQuota verifies n more photos fit into the event, which must be
+// freshly locked so that its photo count is current. Unconfirmed photos hold
+// a slot while their upload URL is valid, so that abandoned uploads do not
+// use up the quota for good.
+func checkPhotoQuota(tx *gorm.DB, event *models.Event, n int) error {
+	if event.MaxPhotos == nil {
+		return nil
+	}
+
+	var pending int64
+	if err := tx.Model(&models.Photo{}).
+		Where("event_id = ? AND confirmed_at IS NULL AND created_at > ?", event.ID, time.Now().Add(-uploadURLExpiry)).
+		Count(&pending).Error; err != nil {
+		return fmt.Errorf("failed to check photo quota: %w", err)
+	}
+	photos := event.PhotoCount + pending
+
+	if photos >= int64(*event.MaxPhotos) {
+		return ErrPhotoLimitReached
+	}
+	if photos+int64(n) > int64(*event.MaxPhotos) {
+		return ErrPhotoQuotaExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"snapShare/i18n"
+	"snapShare/infra/mail"
+	"snapShare/infra/storage"
+	"snapShare/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// retentionWarningPeriod is how long before the purge the owner is warned
+const retentionWarningPeriod = 7 * 24 * time.Hour
+
+// RetentionService purges the photos of events whose retention window has
+// passed. The purge date is set when the event closes (see CloseEvent), and
+// the owner is emailed a warning before anything is deleted.
+// It also clears the storage of deleted events once their grace period is over.
+type RetentionService struct {
+	db            *gorm.DB
+	storage       storage.Storage
+	mailer        mail.Mailer
+	deletionGrace time.Duration
+}
+
+func NewRetentionService(db *gorm.DB, store storage.Storage, mailer mail.Mailer, deletionGraceDays int) *RetentionService {
+	return &RetentionService{
+		db:            db,
+		storage:       store,
+		mailer:        mailer,
+		deletionGrace: time.Duration(deletionGraceDays) * 24 * time.Hour,
+	}
+}
+
+// Run warns owners and purges expired events every interval until ctx is cancelled
+func (s *RetentionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "retention", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "retention run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends pending warnings, purges events that are due and were warned,
+// then purges deleted events past their grace period
+func (s *RetentionService) Tick(ctx context.Context) error {
+	now := time.Now()
+
+	var toWarn []models.Event
+	if err := s.db.WithContext(ctx).Where("purge_at IS NOT NULL AND purge_at <= ?", now.Add(retentionWarningPeriod)).
+		Where("retention_warned_at IS NULL AND purged_at IS NULL").
+		Find(&toWarn).Error; err != nil {
+		return fmt.Errorf("failed to find events to warn: %w", err)
+	}
+
+	for i := range toWarn {
+		if err := s.warnOwner(ctx, &toWarn[i]); err != nil {
+			slog.ErrorContext(ctx, "failed to send retention warning", "event_id", toWarn[i].ID, "error", err)
+		}
+	}
+
+	// Events are only purged a full warning period after the owner has been
+	// warned, so a failing mailer delays the purge instead of deleting photos
+	// without notice, or right after it
+	var toPurge []uuid.UUID
+	if err := s.db.WithContext(ctx).Model(&models.Event{}).
+		Where("purge_at IS NOT NULL AND purge_at <= ?", now).
+		Where("retention_warned_at IS NOT NULL AND retention_warned_at <= ?", now.Add(-retentionWarningPeriod)).
+		Where("purged_at IS NULL").
+		Pluck("id", &toPurge).Error; err != nil {
+		return fmt.Errorf("failed to find events to purge: %w", err)
+	}
+
+	for _, eventID := range toPurge {
+		if err := s.PurgeEvent(ctx, eventID); err != nil {
+			slog.ErrorContext(ctx, "failed to purge event", "event_id", eventID, "error", err)
+		}
+	}
+
+	var deleted []uuid.UUID
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Event{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", now.Add(-s.deletionGrace)).
+		Where("purged_at IS NULL").
+		Pluck("id", &deleted).Error; err != nil {
+		return fmt.Errorf("failed to find deleted events to purge: %w", err)
+	}
+
+	for _, eventID := range deleted {
+		if err := s.PurgeDeletedEvent(ctx, eventID); err != nil {
+			slog.ErrorContext(ctx, "failed to purge deleted event", "event_id", eventID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeEvent permanently deletes an event's photos and archives from R2 and the database.
+// The event row itself is kept so the owner can still see what happened to it.
+func (s *RetentionService) PurgeEvent(ctx context.Context, eventID uuid.UUID) error {
+	var photoKeys []string
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Photo{}).
+		Where("event_id = ?", eventID).
+		Pluck("object_key", &photoKeys).Error; err != nil {
+		return fmt.Errorf("failed to get photo object keys: %w", err)
+	}
+
+	var archiveKeys []string
+	if err := s.db.WithContext(ctx).Model(&models.ArchiveJob{}).
+		Where("event_id = ? AND status = ?", eventID, models.ArchiveJobStatusCompleted).
+		Pluck("object_key", &archiveKeys).Error; err != nil {
+		return fmt.Errorf("failed to get archive object keys: %w", err)
+	}
+
+	// Objects go first: if this fails the rows are still there for the next run
+	if err := s.storage.DeleteObjects(ctx, append(photoKeys, archiveKeys...)); err != nil {
+		return fmt.Errorf("failed to delete objects: %w", err)
+	}
+
+	return s.purgeRecords(eventID)
+}
+
+// PurgeDeletedEvent permanently deletes every object under a deleted event's
+// storage prefix, including unconfirmed uploads and theme logos, its archives,
+// and its photo and archive records. The soft-deleted event row is kept.
+func (s *RetentionService) PurgeDeletedEvent(ctx context.Context, eventID uuid.UUID) error {
+	keys, err := s.storage.ListObjectKeys(ctx, fmt.Sprintf("events/%s/", eventID))
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	archiveKeys, err := s.storage.ListObjectKeys(ctx, fmt.Sprintf("%s%s/", ArchivePrefix, eventID))
+	if err != nil {
+		return fmt.Errorf("failed to list archives: %w", err)
+	}
+	keys = append(keys, archiveKeys...)
+
+	if err := s.storage.DeleteObjects(ctx, keys); err != nil {
+		return fmt.Errorf("failed to delete objects: %w", err)
+	}
+
+	return s.purgeRecords(eventID)
+}
+
+func (s *RetentionService) purgeRecords(eventID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("event_id = ?", eventID).Delete(&models.Photo{}).Error; err != nil {
+			return fmt.Errorf("failed to delete photo records: %w", err)
+		}
+
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.ArchiveJob{}).Error; err != nil {
+			return fmt.Errorf("failed to delete archive jobs: %w", err)
+		}
+
+		if err := tx.Unscoped().Model(&models.Event{}).Where("id = ?", eventID).Updates(map[string]any{
+			"purged_at":              time.Now(),
+			"archive_job_id":         nil,
+			"archive_photo_set_hash": nil,
+			"photo_count":            0,
+			"total_bytes":            0,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark event purged: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// warnOwner emails the purge date to the owner. A purge due sooner than
+// retentionWarningPeriod, e.g. for short retention or after the mailer failed
+// for a while, is postponed so the owner always gets the full period.
+func (s *RetentionService) warnOwner(ctx context.Context, event *models.Event) error {
+	warnedAt := time.Now()
+	purgeAt := *event.PurgeAt
+	if earliest := warnedAt.Add(retentionWarningPeriod); purgeAt.Before(earliest) {
+		purgeAt = earliest
+	}
+	event.PurgeAt = &purgeAt
+
+	lang := emailLang(ctx, s.db, event.OwnerEmail)
+	body := i18n.Sprintf(lang, "The photos of \"%s\" will be permanently deleted after %s when their retention period ends.\nPlease download the photos you need before then.\n",
+		event.Name, event.PurgeAt.Format("2006-01-02 15:04"))
+
+	if err := s.mailer.Send(ctx, mail.Message{
+		To:      event.OwnerEmail,
+		Subject: i18n.Sprintf(lang, "[SnapShare] Photos of %s will be deleted", event.Name),
+		Body:    body,
+	}); err != nil {
+		return fmt.Errorf("failed to send retention warning: %w", err)
+	}
+
+	if err := s.db.WithContext(ctx).Model(event).Updates(map[string]any{
+		"retention_warned_at": warnedAt,
+		"purge_at":            purgeAt,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to record retention warning: %w", err)
+	}
+
+	return nil
+}
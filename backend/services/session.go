@@ -3,29 +3,144 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"snapShare/infra/cache"
 	"snapShare/models"
+	"snapShare/pagination"
 )
 
+// validatedSessionTTL bounds how long an instance trusts a session it has
+// validated when it misses an invalidation published by another instance.
+const validatedSessionTTL = 5 * time.Second
+
 type SessionService struct {
-	db *gorm.DB
+	db  *gorm.DB
+	ttl time.Duration
+	// slidingExpiry extends a session whenever it is used, see TouchSession
+	slidingExpiry bool
+	cache         cache.Cache
+	webhooks      *WebhookService
+
+	// validated holds sessions that passed ValidateSession, with their
+	// event, so that a guest paging through the gallery is not looked up
+	// on every request
+	validated *cache.Local[models.Session]
 }
 
-func NewSessionService(db *gorm.DB) *SessionService {
-	return &SessionService{db: db}
+func NewSessionService(db *gorm.DB, c cache.Cache, webhooks *WebhookService, ttl time.Duration, slidingExpiry bool) *SessionService {
+	return &SessionService{
+		db:            db,
+		cache:         c,
+		webhooks:      webhooks,
+		validated:     cache.NewLocal[models.Session](validatedSessionTTL),
+		ttl:           ttl,
+		slidingExpiry: slidingExpiry,
+	}
 }
 
-func (s *SessionService) CreateSession(ctx context.Context, eventID uuid.UUID, guestName string) (*models.Session, error) {
-	// Validate event exists and is active
-	var event models.Event
-	if err := s.db.Where("id = ? AND status = ?", eventID, models.EventStatusActive).First(&event).Error; err != nil {
-		return nil, fmt.Errorf("event not found or inactive: %w", err)
+// Session listing status filters
+const (
+	SessionStatusActive  = "active"
+	SessionStatusExpired = "expired"
+	SessionStatusAll     = "all"
+)
+
+// SessionFilter narrows down and paginates a session listing
+type SessionFilter struct {
+	Status string // active (default), expired or all
+	Limit  int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// SessionPage is one page of an event's sessions, newest first
+type SessionPage struct {
+	Sessions   []models.Session
+	Total      int64
+	NextCursor string
+}
+
+// sessionKeyset orders session listings newest first
+var sessionKeyset = pagination.Keyset{Columns: []string{"created_at", "id"}, Desc: true}
+
+// GuestSummary aggregates all sessions opened under the same guest name
+type GuestSummary struct {
+	GuestName      string
+	SessionCount   int64
+	ActiveSessions int64
+	FirstJoinedAt  time.Time
+	LastExpiresAt  time.Time
+}
+
+// ErrSessionNotFound is returned when a session does not belong to the event
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrSessionExpired is returned when a session token is unknown, revoked or expired
+var ErrSessionExpired = errors.New("session not found or expired")
+
+// SessionClient describes the device a guest joins from
+type SessionClient struct {
+	// Fingerprint, when set, is recorded so that a ban also blocks the client
+	// under a different name
+	Fingerprint *string
+	UserAgent   string
+	IPAddress   string
+	// ConsentVersion is the version of the event's consent text the guest accepted
+	ConsentVersion *int
+	// GuestID joins the device to an existing guest. It must be taken from a
+	// session of that guest, which proves the device belongs to them.
+	GuestID *uuid.UUID
+}
+
+// maxUserAgentLength matches the size of Session.UserAgent
+const maxUserAgentLength = 512
+
+// ErrInvalidScope is returned for an unknown session scope
+var ErrInvalidScope = errors.New("invalid session scope")
+
+// CreateSession opens a session for a guest on the given client
+func (s *SessionService) CreateSession(ctx context.Context, eventID uuid.UUID, guestName string, client SessionClient) (*models.Session, error) {
+	return s.createSession(ctx, eventID, guestName, client, models.DefaultSessionScopes, true)
+}
+
+// IssueSession opens a session with limited scopes on behalf of the owner,
+// e.g. a view-only link for relatives or an upload-only photo booth token.
+// No consent is recorded since the owner, not a guest, opens it.
+func (s *SessionService) IssueSession(ctx context.Context, eventID uuid.UUID, guestName string, scopes []string) (*models.Session, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		switch scope {
+		case models.SessionScopeUpload, models.SessionScopeView, models.SessionScopeDownload, models.SessionScopeModerate:
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	return s.createSession(ctx, eventID, guestName, SessionClient{}, strings.Join(scopes, ","), false)
+}
+
+// HasScope reports whether the session grants scope
+func HasScope(session *models.Session, scope string) bool {
+	return slices.Contains(strings.Split(session.Scopes, ","), scope)
+}
+
+func (s *SessionService) createSession(ctx context.Context, eventID uuid.UUID, guestName string, client SessionClient, scopes string, requireConsent bool) (*models.Session, error) {
+	if len(client.UserAgent) > maxUserAgentLength {
+		client.UserAgent = client.UserAgent[:maxUserAgentLength]
 	}
 
 	// Generate session token
@@ -34,39 +149,118 @@ func (s *SessionService) CreateSession(ctx context.Context, eventID uuid.UUID, g
 		return nil, fmt.Errorf("failed to generate session token: %w", err)
 	}
 
-	// Create session with 24 hour expiry
+	// The expiry is set once the event, which may override the TTL, is loaded
 	session := models.Session{
 		ID:           uuid.New(),
 		EventID:      eventID,
 		GuestName:    guestName,
 		SessionToken: token,
-		ExpiresAt:    time.Now().Add(24 * time.Hour),
+		Scopes:       scopes,
+
+		ClientFingerprint: client.Fingerprint,
+		UserAgent:         client.UserAgent,
+		IPAddress:         client.IPAddress,
 	}
 
-	if err := s.db.Create(&session).Error; err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+	var event models.Event
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Validate event exists and is active; the row lock serializes quota checks
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", eventID, models.EventStatusActive).
+			First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotActive
+			}
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+
+		// Guests must accept the current version of the event's consent text
+		if requireConsent && event.ConsentText != nil {
+			if client.ConsentVersion == nil || *client.ConsentVersion != event.ConsentVersion {
+				return ErrConsentRequired
+			}
+			now := time.Now()
+			session.ConsentVersion = &event.ConsentVersion
+			session.ConsentAcceptedAt = &now
+		}
+
+		if err := checkGuestBan(tx, event.ID, guestName, client.Fingerprint); err != nil {
+			return err
+		}
+
+		if err := checkGuestQuota(tx, &event, guestName); err != nil {
+			return err
+		}
+
+		guest, err := findOrCreateGuest(tx, event.ID, guestName, client.Fingerprint, client.GuestID)
+		if err != nil {
+			return err
+		}
+		session.GuestID = &guest.ID
+
+		session.ExpiresAt = time.Now().Add(s.sessionTTL(&event))
+		if err := tx.Create(&session).Error; err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Load the event relation
 	session.Event = event
 
+	s.webhooks.Dispatch(ctx, session.EventID, models.WebhookEventSessionCreated, WebhookSessionData{
+		SessionID: session.ID,
+		GuestID:   session.GuestID,
+		GuestName: session.GuestName,
+		ExpiresAt: session.ExpiresAt,
+	})
+
 	return &session, nil
 }
 
+// ValidateSession looks up a live session along with its event. The session
+// and the event are cached separately so that an event change invalidates
+// every session of the event at once.
 func (s *SessionService) ValidateSession(ctx context.Context, token string) (*models.Session, error) {
+	key := sessionCacheKey(token)
+	if session, ok := s.validated.Get(key); ok && session.ExpiresAt.After(time.Now()) {
+		return &session, nil
+	}
+
 	var session models.Session
-	err := s.db.Preload("Event").
-		Where("session_token = ? AND expires_at > ?", token, time.Now()).
-		First(&session).Error
+	hit, err := s.cache.Get(ctx, key, &session)
+	if err != nil {
+		slog.WarnContext(ctx, "session cache lookup failed", "error", err)
+	}
+
+	if !hit {
+		err := s.db.WithContext(ctx).Where("session_token = ? AND expires_at > ?", token, time.Now()).
+			First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionExpired
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get session: %w", err)
+		}
+		setCache(ctx, s.cache, key, &session)
+	} else if !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrSessionExpired
+	}
 
+	event, err := cachedEventByID(ctx, s.db.WithContext(ctx), s.cache, session.EventID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found or expired: %w", err)
+		return nil, err
 	}
+	session.Event = *event
 
 	// Check if event is still active
 	if session.Event.Status != models.EventStatusActive {
-		return nil, fmt.Errorf("event is no longer active")
+		return nil, ErrEventNotActive
 	}
+	s.validated.Set(key, session)
 
 	return &session, nil
 }
@@ -77,47 +271,249 @@ func (s *SessionService) RefreshSession(ctx context.Context, token string) (*mod
 		return nil, err
 	}
 
-	// Extend expiry by 24 hours
-	newExpiresAt := time.Now().Add(24 * time.Hour)
-	if err := s.db.Model(&session).Update("expires_at", newExpiresAt).Error; err != nil {
-		return nil, fmt.Errorf("failed to refresh session: %w", err)
+	if err := s.extendSession(ctx, session); err != nil {
+		return nil, err
 	}
 
-	session.ExpiresAt = newExpiresAt
 	return session, nil
 }
 
-func (s *SessionService) RevokeSession(ctx context.Context, token string) error {
-	result := s.db.Where("session_token = ?", token).Delete(&models.Session{})
+// TouchSession implements sliding expiry: when enabled, a session used after
+// half of its lifetime has passed is extended by a full TTL. It reports
+// whether the expiry changed.
+func (s *SessionService) TouchSession(ctx context.Context, session *models.Session) (bool, error) {
+	if !s.slidingExpiry {
+		return false, nil
+	}
+
+	if time.Until(session.ExpiresAt) > s.sessionTTL(&session.Event)/2 {
+		return false, nil
+	}
+
+	if err := s.extendSession(ctx, session); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// extendSession resets the session's expiry to a full TTL from now
+func (s *SessionService) extendSession(ctx context.Context, session *models.Session) error {
+	newExpiresAt := time.Now().Add(s.sessionTTL(&session.Event))
+	if err := s.db.WithContext(ctx).Model(session).Update("expires_at", newExpiresAt).Error; err != nil {
+		return fmt.Errorf("failed to refresh session: %w", err)
+	}
+	s.invalidateSessions(ctx, *session)
+
+	session.ExpiresAt = newExpiresAt
+	return nil
+}
+
+// sessionTTL returns the event's session lifetime, falling back to the deployment default
+func (s *SessionService) sessionTTL(event *models.Event) time.Duration {
+	if event.SessionTTLHours != nil {
+		return time.Duration(*event.SessionTTLHours) * time.Hour
+	}
+	return s.ttl
+}
+
+// RevokeSession invalidates a session token and returns the revoked session
+func (s *SessionService) RevokeSession(ctx context.Context, token string) (*models.Session, error) {
+	var session models.Session
+	result := s.db.WithContext(ctx).Clauses(clause.Returning{}).Where("session_token = ?", token).Delete(&session)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrSessionNotFound
+	}
+	s.invalidateSessions(ctx, session)
+
+	return &session, nil
+}
+
+// RevokeEventSession revokes a single session of an event
+func (s *SessionService) RevokeEventSession(ctx context.Context, eventID, sessionID uuid.UUID) error {
+	var session models.Session
+	result := s.db.WithContext(ctx).Clauses(clause.Returning{}).Where("id = ? AND event_id = ?", sessionID, eventID).Delete(&session)
 	if result.Error != nil {
 		return fmt.Errorf("failed to revoke session: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("session not found")
+		return ErrSessionNotFound
 	}
+	s.invalidateSessions(ctx, session)
 
 	return nil
 }
 
-func (s *SessionService) GetSessionsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Session, error) {
+// RevokeDevice revokes every session of an event opened from the same device
+// as sessionID, whatever guest name was used. Sessions without a device ID
+// only revoke themselves.
+func (s *SessionService) RevokeDevice(ctx context.Context, eventID, sessionID uuid.UUID) (int64, error) {
+	var session models.Session
+	if err := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", sessionID, eventID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrSessionNotFound
+		}
+		return 0, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	query := s.db.WithContext(ctx).Where("event_id = ?", eventID)
+	if session.ClientFingerprint != nil {
+		query = query.Where("client_fingerprint = ?", *session.ClientFingerprint)
+	} else {
+		query = query.Where("id = ?", session.ID)
+	}
+
+	var revoked []models.Session
+	result := query.Clauses(clause.Returning{}).Delete(&revoked)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke device sessions: %w", result.Error)
+	}
+	s.invalidateSessions(ctx, revoked...)
+
+	return result.RowsAffected, nil
+}
+
+// RevokeGuestSessions revokes every session of the session's guest on all
+// devices, including the session itself. It returns the number revoked.
+func (s *SessionService) RevokeGuestSessions(ctx context.Context, session *models.Session) (int64, error) {
+	query := s.db.WithContext(ctx).Where("event_id = ?", session.EventID)
+	if session.GuestID != nil {
+		query = query.Where("guest_id = ?", *session.GuestID)
+	} else {
+		query = query.Where("guest_name = ?", session.GuestName)
+	}
+
+	var revoked []models.Session
+	result := query.Clauses(clause.Returning{}).Delete(&revoked)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke guest sessions: %w", result.Error)
+	}
+	s.invalidateSessions(ctx, revoked...)
+
+	return result.RowsAffected, nil
+}
+
+// invalidateSessions drops the cached lookups of sessions, both shared and
+// validated on this instance
+func (s *SessionService) invalidateSessions(ctx context.Context, sessions ...models.Session) {
+	keys := make([]string, len(sessions))
+	for i := range sessions {
+		keys[i] = sessionCacheKey(sessions[i].SessionToken)
+	}
+	s.validated.Delete(keys...)
+	invalidateCache(ctx, s.cache, keys...)
+}
+
+// WatchInvalidations drops sessions invalidated by any instance from memory
+// until ctx is done, along with every session of an invalidated event, since
+// a validated session carries its event
+func (s *SessionService) WatchInvalidations(ctx context.Context) {
+	s.cache.Subscribe(ctx, func(keys []string) {
+		s.validated.Delete(keys...)
+
+		events := map[string]bool{}
+		for _, key := range keys {
+			if eventID, ok := strings.CutPrefix(key, eventIDCacheKeyPrefix); ok {
+				events[eventID] = true
+			}
+		}
+		if len(events) > 0 {
+			s.validated.DeleteFunc(func(_ string, session models.Session) bool {
+				return events[session.EventID.String()]
+			})
+		}
+	})
+}
+
+// GetSessionsByEvent returns one page of sessions for an event along with
+// the total number of sessions matching the filter
+func (s *SessionService) GetSessionsByEvent(ctx context.Context, eventID uuid.UUID, filter SessionFilter) (*SessionPage, error) {
+	query := s.db.WithContext(ctx).Model(&models.Session{}).Where("event_id = ?", eventID)
+
+	now := time.Now()
+	switch filter.Status {
+	case "", SessionStatusActive:
+		query = query.Where("expires_at > ?", now)
+	case SessionStatusExpired:
+		query = query.Where("expires_at <= ?", now)
+	case SessionStatusAll:
+	default:
+		return nil, fmt.Errorf("invalid session status: %s", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	if filter.Cursor != "" {
+		var createdAt time.Time
+		var id uuid.UUID
+		if err := pagination.Decode(filter.Cursor, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		condition, args := sessionKeyset.After(createdAt, id)
+		query = query.Where(condition, args...)
+	}
+
 	var sessions []models.Session
-	err := s.db.Where("event_id = ? AND expires_at > ?", eventID, time.Now()).
-		Order("created_at DESC").
+	err := query.Order(sessionKeyset.Order()).
+		Limit(filter.Limit + 1).
 		Find(&sessions).Error
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sessions: %w", err)
 	}
 
-	return sessions, nil
+	page := &SessionPage{Total: total}
+	var more bool
+	page.Sessions, more = pagination.Trim(sessions, filter.Limit)
+	if more {
+		last := page.Sessions[len(page.Sessions)-1]
+		page.NextCursor = pagination.Encode(last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+// GetGuestSummariesByEvent aggregates sessions per guest name for an event
+func (s *SessionService) GetGuestSummariesByEvent(ctx context.Context, eventID uuid.UUID) ([]GuestSummary, error) {
+	var summaries []GuestSummary
+	err := s.db.WithContext(ctx).Model(&models.Session{}).
+		Select(`guest_name,
+			COUNT(*) AS session_count,
+			COUNT(*) FILTER (WHERE expires_at > ?) AS active_sessions,
+			MIN(created_at) AS first_joined_at,
+			MAX(expires_at) AS last_expires_at`, time.Now()).
+		Where("event_id = ?", eventID).
+		Group("guest_name").
+		Order("first_joined_at ASC").
+		Scan(&summaries).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guest summaries: %w", err)
+	}
+
+	return summaries, nil
 }
 
 func (s *SessionService) CleanupExpiredSessions(ctx context.Context) error {
-	result := s.db.Where("expires_at <= ?", time.Now()).Delete(&models.Session{})
+	result := s.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&models.Session{})
 	return result.Error
 }
 
+// TokenFingerprint returns a short, non-reversible identifier for a session
+// token so it can be shown to owners without exposing the bearer secret
+func TokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:6])
+}
+
 func (s *SessionService) generateSessionToken() (string, error) {
 	bytes := make([]byte, 32) // 256 bits
 	if _, err := rand.Read(bytes); err != nil {
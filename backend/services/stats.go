@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"snapShare/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StatsService struct {
+	db           *gorm.DB
+	photoService *PhotoService
+}
+
+func NewStatsService(db *gorm.DB, photoService *PhotoService) *StatsService {
+	return &StatsService{
+		db:           db,
+		photoService: photoService,
+	}
+}
+
+// EventStats summarises upload activity of a single event
+type EventStats struct {
+	PhotoCount      int64
+	TotalBytes      int64
+	Uploaders       int64
+	InFlightUploads int64
+	LastActivityAt  *time.Time
+	Location        *time.Location
+	UploadsPerHour  []HourlyUploads
+	UploadsPerDay   []DailyUploads
+}
+
+// HourlyUploads is the number of photos confirmed within an hour of the event's timezone
+type HourlyUploads struct {
+	Hour  time.Time
+	Count int64
+}
+
+// DailyUploads is the number of photos confirmed on a calendar day of the event's timezone
+type DailyUploads struct {
+	Day   time.Time
+	Count int64
+}
+
+// GetEventStats computes an event's totals and hourly and daily upload
+// histograms, bucketed in the event's timezone.
+// Only confirmed photos are counted; last activity also considers guests joining.
+func (s *StatsService) GetEventStats(ctx context.Context, eventID uuid.UUID) (*EventStats, error) {
+	stats, err := s.GetEventsStats(ctx, []uuid.UUID{eventID})
+	if err != nil {
+		return nil, err
+	}
+	if stats[eventID] == nil {
+		return nil, fmt.Errorf("event not found: %w", gorm.ErrRecordNotFound)
+	}
+
+	return stats[eventID], nil
+}
+
+// GetEventsStats computes the stats of several events with one query per
+// figure, keyed by event ID. Events that do not exist are left out.
+func (s *StatsService) GetEventsStats(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID]*EventStats, error) {
+	var events []models.Event
+	if err := s.db.WithContext(ctx).Select("id", "timezone", "photo_count", "total_bytes").
+		Where("id IN ?", eventIDs).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	if len(events) == 0 {
+		return map[uuid.UUID]*EventStats{}, nil
+	}
+
+	stats := make(map[uuid.UUID]*EventStats, len(events))
+	ids := make([]uuid.UUID, len(events))
+	// Buckets are counted in each event's own timezone, joined as a VALUES list
+	zones := make([]string, len(events))
+	zoneArgs := make([]any, 0, 2*len(events))
+	for i := range events {
+		event := &events[i]
+		location := EventLocation(event)
+		stats[event.ID] = &EventStats{
+			PhotoCount:     event.PhotoCount,
+			TotalBytes:     event.TotalBytes,
+			Location:       location,
+			UploadsPerHour: []HourlyUploads{},
+			UploadsPerDay:  []DailyUploads{},
+		}
+		ids[i] = event.ID
+		zones[i] = "(?::uuid, ?::text)"
+		zoneArgs = append(zoneArgs, event.ID, location.String())
+	}
+	confirmed := s.db.WithContext(ctx).Model(&models.Photo{}).
+		Where("photos.event_id IN ? AND photos.confirmed_at IS NOT NULL", ids)
+
+	var uploaders []struct {
+		EventID uuid.UUID
+		Count   int64
+	}
+	if err := confirmed.Session(&gorm.Session{}).
+		Select("event_id, COUNT(DISTINCT uploader_name) AS count").
+		Group("event_id").
+		Scan(&uploaders).Error; err != nil {
+		return nil, fmt.Errorf("failed to count uploaders: %w", err)
+	}
+	for _, row := range uploaders {
+		stats[row.EventID].Uploaders = row.Count
+	}
+
+	var buckets []struct {
+		EventID uuid.UUID
+		Unit    string
+		Start   time.Time
+		Count   int64
+	}
+	if err := confirmed.Session(&gorm.Session{}).
+		Joins("JOIN (VALUES "+strings.Join(zones, ", ")+") AS zones (event_id, name) ON zones.event_id = photos.event_id", zoneArgs...).
+		Select(`photos.event_id, units.unit, date_trunc(units.unit, photos.confirmed_at AT TIME ZONE zones.name) AS start, COUNT(*) AS count`).
+		Joins("CROSS JOIN (VALUES ('hour'), ('day')) AS units (unit)").
+		Group("photos.event_id, units.unit, start").
+		Order("start").
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate uploads: %w", err)
+	}
+	// The buckets are local wall-clock times; attach the event's location to them
+	for _, bucket := range buckets {
+		event := stats[bucket.EventID]
+		start := inLocation(bucket.Start, event.Location)
+		if bucket.Unit == "hour" {
+			event.UploadsPerHour = append(event.UploadsPerHour, HourlyUploads{Hour: start, Count: bucket.Count})
+		} else {
+			event.UploadsPerDay = append(event.UploadsPerDay, DailyUploads{Day: start, Count: bucket.Count})
+		}
+	}
+
+	// GREATEST ignores NULLs, so either side may be empty
+	var activity []struct {
+		EventID        uuid.UUID
+		LastActivityAt *time.Time
+	}
+	if err := s.db.WithContext(ctx).Raw(`SELECT events.id AS event_id, GREATEST(
+			(SELECT MAX(created_at) FROM photos WHERE event_id = events.id AND deleted_at IS NULL),
+			(SELECT MAX(created_at) FROM sessions WHERE event_id = events.id AND deleted_at IS NULL)
+		) AS last_activity_at
+		FROM events WHERE events.id IN ?`, ids).
+		Scan(&activity).Error; err != nil {
+		return nil, fmt.Errorf("failed to get last activity: %w", err)
+	}
+	for _, row := range activity {
+		stats[row.EventID].LastActivityAt = row.LastActivityAt
+	}
+
+	inFlight, err := s.photoService.CountInFlightUploadsByEvent(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for eventID, count := range inFlight {
+		stats[eventID].InFlightUploads = count
+	}
+
+	return stats, nil
+}
+
+// UploaderStats is how much a single guest contributed to an event
+type UploaderStats struct {
+	UploaderName  string
+	PhotoCount    int64
+	TotalBytes    int64
+	FirstUploadAt time.Time
+	LastUploadAt  time.Time
+}
+
+// GetUploaderStats returns the confirmed photos of an event per uploader,
+// most active first
+func (s *StatsService) GetUploaderStats(ctx context.Context, eventID uuid.UUID) ([]UploaderStats, error) {
+	var uploaders []UploaderStats
+	err := s.db.WithContext(ctx).Model(&models.Photo{}).
+		Select(`uploader_name,
+			COUNT(*) AS photo_count,
+			COALESCE(SUM(size), 0) AS total_bytes,
+			MIN(confirmed_at) AS first_upload_at,
+			MAX(confirmed_at) AS last_upload_at`).
+		Where("event_id = ? AND confirmed_at IS NOT NULL", eventID).
+		Group("uploader_name").
+		Order("photo_count DESC, uploader_name ASC").
+		Scan(&uploaders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate uploader stats: %w", err)
+	}
+
+	return uploaders, nil
+}
+
+// inLocation reinterprets the wall-clock time of t in loc
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"snapShare/infra/storage"
+	"snapShare/models"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidLogoKey = errors.New("logo object key does not belong to this event")
+
+// logoContentTypes are the image types accepted for event logos
+var logoContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+// ThemeService manages per-event branding of the guest landing page
+type ThemeService struct {
+	db      *gorm.DB
+	storage storage.Storage
+}
+
+func NewThemeService(db *gorm.DB, store storage.Storage) *ThemeService {
+	return &ThemeService{
+		db:      db,
+		storage: store,
+	}
+}
+
+// UpdateThemeRequest changes the fields that are set; an empty string clears a field
+type UpdateThemeRequest struct {
+	PrimaryColor *string
+	WelcomeText  *string
+	LogoKey      *string
+}
+
+// UpdateTheme updates an event's theme
+func (s *ThemeService) UpdateTheme(ctx context.Context, eventID uuid.UUID, req *UpdateThemeRequest) (*models.Event, error) {
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if req.LogoKey != nil && *req.LogoKey != "" && !strings.HasPrefix(*req.LogoKey, logoKeyPrefix(eventID)) {
+		return nil, ErrInvalidLogoKey
+	}
+
+	updates := map[string]any{}
+	for column, value := range map[string]*string{
+		"theme_primary_color": req.PrimaryColor,
+		"theme_welcome_text":  req.WelcomeText,
+		"theme_logo_key":      req.LogoKey,
+	} {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			updates[column] = nil
+		} else {
+			updates[column] = *value
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.WithContext(ctx).Model(&event).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update theme: %w", err)
+		}
+	}
+
+	return &event, nil
+}
+
+// GenerateLogoUploadURL issues a presigned URL for uploading a logo. The
+// returned key is applied with UpdateTheme once the upload has finished.
+func (s *ThemeService) GenerateLogoUploadURL(ctx context.Context, eventID uuid.UUID, contentType string) (*UploadInfo, error) {
+	if !logoContentTypes[contentType] {
+		return nil, fmt.Errorf("unsupported logo content type: %s", contentType)
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+
+	objectKey := fmt.Sprintf("%s%s%s", logoKeyPrefix(eventID), uuid.New(), getExtensionFromContentType(contentType))
+
+	uploadURL, err := s.storage.GeneratePresignedUploadURL(ctx, objectKey, contentType, uploadURLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload URL: %w", err)
+	}
+
+	return &UploadInfo{
+		UploadURL: uploadURL,
+		ObjectKey: objectKey,
+	}, nil
+}
+
+// LogoURL returns the public URL of an event's logo, or nil if it has none
+func (s *ThemeService) LogoURL(event *models.Event) *string {
+	if event.ThemeLogoKey == nil {
+		return nil
+	}
+	url := s.storage.GetPublicURL(*event.ThemeLogoKey)
+	return &url
+}
+
+func logoKeyPrefix(eventID uuid.UUID) string {
+	return fmt.Sprintf("events/%s/theme/", eventID)
+}
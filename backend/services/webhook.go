@@ -0,0 +1,426 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"snapShare/infra/webhooksig"
+	"snapShare/models"
+)
+
+var (
+	ErrInvalidWebhookEventType = errors.New("invalid webhook event type")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must use https and a public host")
+)
+
+// webhookBlockedPrefixes are ranges outside the private, loopback and
+// link-local ones that still do not reach the public internet
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// WebhookEventTypes lists every event type a webhook can subscribe to
+var WebhookEventTypes = []string{
+	models.WebhookEventPhotoUploaded,
+	models.WebhookEventPhotoDeleted,
+	models.WebhookEventEventClosed,
+	models.WebhookEventSessionCreated,
+}
+
+const (
+	webhookTimeout     = 10 * time.Second
+	webhookBatchSize   = 50
+	webhookMaxAttempts = 8
+	// webhookLease keeps a claimed delivery from being picked up by another
+	// worker while it is in flight. A batch is posted one delivery after
+	// another, so the lease outlasts the whole batch timing out.
+	webhookLease = 2 * webhookBatchSize * webhookTimeout
+	// webhookBaseBackoff doubles after every failed attempt
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxErrorBody caps how much of a failed response is kept
+	webhookMaxErrorBody = 512
+)
+
+// WebhookService lets integrators react to what happens in an event: every
+// change is queued per subscribed webhook and delivered by Run with retries
+type WebhookService struct {
+	db     *gorm.DB
+	client *http.Client
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{
+		db:     db,
+		client: newWebhookClient(),
+	}
+}
+
+// newWebhookClient posts to owner-supplied URLs, so it only connects to
+// public addresses and does not follow redirects. The check runs on the
+// address actually dialled, after DNS resolution, so a public name resolving
+// to an internal address is refused too.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicAddr reports whether webhooks may be delivered to addr
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateWebhookURL requires an https URL. Hosts given as IP addresses
+// must be public; names are checked when deliveries connect.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !isPublicAddr(addr) {
+		return ErrInvalidWebhookURL
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") || strings.HasSuffix(strings.ToLower(u.Hostname()), ".localhost") {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body posted to webhooks. ID stays the same
+// across retries so receivers can drop duplicates.
+type WebhookPayload struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	EventID   uuid.UUID `json:"event_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookPhotoData struct {
+	PhotoID      uuid.UUID  `json:"photo_id"`
+	UploaderName string     `json:"uploader_name"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	Size         int64      `json:"file_size,omitempty"`
+	Caption      *string    `json:"caption,omitempty"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+}
+
+type WebhookEventData struct {
+	Code     string    `json:"code"`
+	Name     string    `json:"name"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+type WebhookSessionData struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	GuestID   *uuid.UUID `json:"guest_id,omitempty"`
+	GuestName string     `json:"guest_name"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// CreateWebhook registers a webhook for an event with a newly generated secret
+func (s *WebhookService) CreateWebhook(ctx context.Context, eventID uuid.UUID, url string, eventTypes []string) (*models.Webhook, error) {
+	if err := validateWebhookURL(url); err != nil {
+		return nil, err
+	}
+	for _, eventType := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWebhookEventType, eventType)
+		}
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		ID:         uuid.New(),
+		EventID:    eventID,
+		URL:        url,
+		Secret:     secret,
+		EventTypes: strings.Join(slices.Compact(slices.Sorted(slices.Values(eventTypes))), ","),
+	}
+	if err := s.db.WithContext(ctx).Create(webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooksByEvent lists the webhooks registered for an event
+func (s *WebhookService) GetWebhooksByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook along with its pending deliveries
+func (s *WebhookService) DeleteWebhook(ctx context.Context, eventID, webhookID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND event_id = ?", webhookID, eventID).Delete(&models.Webhook{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Dispatch queues a delivery of data to every webhook of the event subscribed
+// to eventType. Failures are logged rather than returned so that webhooks
+// never fail the change that triggered them.
+func (s *WebhookService) Dispatch(ctx context.Context, eventID uuid.UUID, eventType string, data any) {
+	if err := s.enqueue(ctx, eventID, eventType, data); err != nil {
+		slog.ErrorContext(ctx, "failed to queue webhook deliveries", "event_id", eventID, "webhook_event", eventType, "error", err)
+	}
+}
+
+func (s *WebhookService) enqueue(ctx context.Context, eventID uuid.UUID, eventType string, data any) error {
+	var webhooks []models.Webhook
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&webhooks).Error; err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !slices.Contains(strings.Split(webhook.EventTypes, ","), eventType) {
+			continue
+		}
+
+		deliveryID := uuid.New()
+		payload, err := json.Marshal(WebhookPayload{
+			ID:        deliveryID,
+			Type:      eventType,
+			EventID:   eventID,
+			CreatedAt: now,
+			Data:      data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode payload: %w", err)
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            deliveryID,
+			WebhookID:     webhook.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := s.db.WithContext(ctx).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to queue deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// Run delivers due webhook payloads every interval until ctx is cancelled
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "webhooks", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "webhook run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick claims a batch of due deliveries and attempts each of them once
+func (s *WebhookService) Tick(ctx context.Context) error {
+	now := time.Now()
+	due := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(webhookBatchSize).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var deliveries []models.WebhookDelivery
+	if err := s.db.WithContext(ctx).Model(&deliveries).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("next_attempt_at", now.Add(webhookLease)).Error; err != nil {
+		return fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	webhookIDs := make([]uuid.UUID, len(deliveries))
+	for i, delivery := range deliveries {
+		webhookIDs[i] = delivery.WebhookID
+	}
+	var webhooks []models.Webhook
+	if err := s.db.WithContext(ctx).Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	webhooksByID := make(map[uuid.UUID]*models.Webhook, len(webhooks))
+	for i := range webhooks {
+		webhooksByID[webhooks[i].ID] = &webhooks[i]
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		webhook, ok := webhooksByID[delivery.WebhookID]
+		if !ok {
+			// Deleted since the delivery was claimed; the cascade removes it
+			continue
+		}
+		s.attempt(ctx, webhook, delivery)
+	}
+
+	return nil
+}
+
+// attempt posts one delivery and records the outcome, scheduling a retry
+// with exponential backoff on failure
+func (s *WebhookService) attempt(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	attempts := delivery.Attempts + 1
+	updates := map[string]any{"attempts": attempts}
+
+	if err := s.post(ctx, webhook, delivery); err != nil {
+		errMsg := err.Error()
+		updates["last_error"] = errMsg
+		if attempts >= webhookMaxAttempts {
+			updates["status"] = models.WebhookDeliveryFailed
+		} else {
+			updates["next_attempt_at"] = time.Now().Add(webhookBaseBackoff << (attempts - 1))
+		}
+	} else {
+		updates["status"] = models.WebhookDeliveryDelivered
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(delivery).Updates(updates).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook attempt", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// post sends the payload signed with the webhook's secret. Receivers verify
+// X-SnapShare-Signature, the hex HMAC-SHA256 of "<timestamp>.<body>", against
+// X-SnapShare-Timestamp.
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SnapShare-Webhook/1.0")
+	req.Header.Set("X-SnapShare-Event", delivery.EventType)
+	req.Header.Set(webhooksig.HeaderDelivery, delivery.ID.String())
+	webhooksig.SetHeaders(req.Header, webhook.Secret, time.Now(), []byte(delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorBody))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
+
+func webhookPhotoData(photo *models.Photo) WebhookPhotoData {
+	return WebhookPhotoData{
+		PhotoID:      photo.ID,
+		UploaderName: photo.UploaderName,
+		MimeType:     photo.MimeType,
+		Size:         photo.Size,
+		Caption:      photo.Caption,
+		ConfirmedAt:  photo.ConfirmedAt,
+	}
+}
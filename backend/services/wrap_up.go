@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"snapShare/i18n"
+	"snapShare/infra/mail"
+	"snapShare/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWrapUpStarted is returned when an event already has a wrap-up job
+var ErrWrapUpStarted = errors.New("wrap-up has already been started for this event")
+
+const (
+	// maxWrapUpAttempts is how many times a failing step is retried before the job gives up
+	maxWrapUpAttempts = 5
+	// wrapUpLease keeps a claimed job from other instances while it is advanced
+	wrapUpLease = 5 * time.Minute
+	// wrapUpArchiveTimeout is how long build_archive waits for its archive,
+	// which may have been lost with the instance building it
+	wrapUpArchiveTimeout = 6 * time.Hour
+)
+
+// WrapUpService runs the post-event workflow: stop uploads, sweep moderation,
+// build the archive, email the owner and schedule retention. Progress is
+// persisted per step so a restart resumes where the job left off.
+type WrapUpService struct {
+	db             *gorm.DB
+	eventService   *EventService
+	archiveService *ArchiveService
+	mailer         mail.Mailer
+	delay          time.Duration
+}
+
+func NewWrapUpService(db *gorm.DB, eventService *EventService, archiveService *ArchiveService, mailer mail.Mailer, delayDays int) *WrapUpService {
+	return &WrapUpService{
+		db:             db,
+		eventService:   eventService,
+		archiveService: archiveService,
+		mailer:         mailer,
+		delay:          time.Duration(delayDays) * 24 * time.Hour,
+	}
+}
+
+// Run schedules and advances wrap-up jobs every interval until ctx is cancelled
+func (s *WrapUpService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runExclusive(ctx, s.db, "wrap_up", s.Tick); err != nil {
+			slog.ErrorContext(ctx, "wrap-up run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick closes events past their close_at, creates jobs for events that became
+// due and advances the running jobs no other instance has claimed
+func (s *WrapUpService) Tick(ctx context.Context) error {
+	if err := s.eventService.CloseDueEvents(ctx); err != nil {
+		return err
+	}
+
+	if err := s.scheduleDue(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := s.db.WithContext(ctx).Model(&models.WrapUpJob{}).
+		Select("id").
+		Where("status = ? AND (locked_until IS NULL OR locked_until <= ?)", models.WrapUpStatusInProgress, now).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var jobs []models.WrapUpJob
+	if err := s.db.WithContext(ctx).Model(&jobs).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("locked_until", now.Add(wrapUpLease)).Error; err != nil {
+		return fmt.Errorf("failed to claim wrap-up jobs: %w", err)
+	}
+
+	for i := range jobs {
+		s.advance(ctx, &jobs[i])
+		s.release(ctx, &jobs[i])
+	}
+
+	return nil
+}
+
+// StartWrapUp starts the workflow for an event immediately. An event is
+// wrapped up once; ErrWrapUpStarted is returned if a job already exists.
+func (s *WrapUpService) StartWrapUp(ctx context.Context, eventID uuid.UUID) (*models.WrapUpJob, error) {
+	if _, err := s.eventService.GetEventByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	lockedUntil := time.Now().Add(wrapUpLease)
+	job, err := s.createJob(eventID, &lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.advance(ctx, job)
+	s.release(ctx, job)
+	return job, nil
+}
+
+// GetWrapUp returns the wrap-up job of an event
+func (s *WrapUpService) GetWrapUp(ctx context.Context, eventID uuid.UUID) (*models.WrapUpJob, error) {
+	var job models.WrapUpJob
+	if err := s.db.WithContext(ctx).Where("event_id = ?", eventID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("wrap-up not started")
+		}
+		return nil, fmt.Errorf("failed to get wrap-up job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (s *WrapUpService) scheduleDue(ctx context.Context) error {
+	var eventIDs []uuid.UUID
+	err := s.db.WithContext(ctx).Model(&models.Event{}).
+		// event_date starts at midnight in the event's own timezone
+		Where("event_date IS NOT NULL AND (event_date::timestamp AT TIME ZONE timezone) <= ?", time.Now().Add(-s.delay)).
+		Where("id NOT IN (?)", s.db.WithContext(ctx).Model(&models.WrapUpJob{}).Select("event_id")).
+		Pluck("id", &eventIDs).Error
+	if err != nil {
+		return fmt.Errorf("failed to find due events: %w", err)
+	}
+
+	for _, eventID := range eventIDs {
+		// Another instance or an owner may have started the job since the query
+		if _, err := s.createJob(eventID, nil); err != nil && !errors.Is(err, ErrWrapUpStarted) {
+			slog.ErrorContext(ctx, "failed to schedule wrap-up", "event_id", eventID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// createJob inserts the job of an event, claimed until lockedUntil when set
+func (s *WrapUpService) createJob(eventID uuid.UUID, lockedUntil *time.Time) (*models.WrapUpJob, error) {
+	job := &models.WrapUpJob{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		Step:        models.WrapUpStepCloseUploads,
+		Status:      models.WrapUpStatusInProgress,
+		LockedUntil: lockedUntil,
+	}
+
+	// event_id is unique, so a concurrent start inserts nothing
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create wrap-up job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWrapUpStarted
+	}
+
+	return job, nil
+}
+
+// advance runs steps until the job completes, has to wait, or a step fails
+func (s *WrapUpService) advance(ctx context.Context, job *models.WrapUpJob) {
+	for job.Step != models.WrapUpStepDone {
+		next, done, err := s.runStep(ctx, job)
+		if err != nil {
+			s.recordFailure(ctx, job, err)
+			return
+		}
+		if !done {
+			return
+		}
+
+		job.Step = next
+		job.Attempts = 0
+		job.Error = nil
+		if err := s.db.WithContext(ctx).Model(job).Updates(map[string]any{"step": next, "attempts": 0, "error": nil}).Error; err != nil {
+			slog.ErrorContext(ctx, "failed to save wrap-up step", "job_id", job.ID, "error", err)
+			return
+		}
+	}
+
+	now := time.Now()
+	job.Status = models.WrapUpStatusCompleted
+	job.CompletedAt = &now
+	if err := s.db.WithContext(ctx).Model(job).Updates(map[string]any{"status": job.Status, "completed_at": now}).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to complete wrap-up", "job_id", job.ID, "error", err)
+	}
+}
+
+// release hands a claimed job back, so the next tick on any instance may advance it
+func (s *WrapUpService) release(ctx context.Context, job *models.WrapUpJob) {
+	job.LockedUntil = nil
+	if err := s.db.WithContext(ctx).Model(job).Update("locked_until", nil).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to release wrap-up job", "job_id", job.ID, "error", err)
+	}
+}
+
+// runStep executes the current step and returns the next one. done is false
+// when the step is still waiting on background work.
+func (s *WrapUpService) runStep(ctx context.Context, job *models.WrapUpJob) (next models.WrapUpStep, done bool, err error) {
+	switch job.Step {
+	case models.WrapUpStepCloseUploads:
+		if err := s.eventService.CloseEvent(ctx, job.EventID); err != nil {
+			return "", false, err
+		}
+		return models.WrapUpStepModerationSweep, true, nil
+
+	case models.WrapUpStepModerationSweep:
+		// Nothing to sweep until photo moderation exists
+		return models.WrapUpStepBuildArchive, true, nil
+
+	case models.WrapUpStepBuildArchive:
+		return s.runBuildArchive(ctx, job)
+
+	case models.WrapUpStepNotifyOwner:
+		if err := s.notifyOwner(ctx, job); err != nil {
+			return "", false, err
+		}
+		return models.WrapUpStepScheduleRetention, true, nil
+
+	case models.WrapUpStepScheduleRetention:
+		// close_uploads already set purge_at from the event's RetentionDays;
+		// RetentionService warns the owner and purges when it is due
+		return models.WrapUpStepDone, true, nil
+	}
+
+	return "", false, fmt.Errorf("unknown wrap-up step: %s", job.Step)
+}
+
+func (s *WrapUpService) runBuildArchive(ctx context.Context, job *models.WrapUpJob) (models.WrapUpStep, bool, error) {
+	if job.ArchiveJobID == nil {
+		archive, err := s.archiveService.StartArchive(ctx, job.EventID, nil)
+		if errors.Is(err, ErrNoPhotos) {
+			return models.WrapUpStepNotifyOwner, true, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+
+		job.ArchiveJobID = &archive.ID
+		if err := s.db.WithContext(ctx).Model(job).Update("archive_job_id", archive.ID).Error; err != nil {
+			return "", false, fmt.Errorf("failed to save archive job: %w", err)
+		}
+		return "", false, nil
+	}
+
+	archive, err := s.archiveService.GetJob(ctx, *job.ArchiveJobID)
+	if err != nil {
+		return "", false, err
+	}
+
+	var stepErr error
+	switch archive.Status {
+	case models.ArchiveJobStatusCompleted:
+		return models.WrapUpStepNotifyOwner, true, nil
+	case models.ArchiveJobStatusFailed:
+		stepErr = fmt.Errorf("archive job %s failed", archive.ID)
+	default:
+		if time.Since(archive.CreatedAt) < wrapUpArchiveTimeout {
+			return "", false, nil
+		}
+		// Fail the archive so it is not reused as the event's cached archive
+		stepErr = fmt.Errorf("archive job %s timed out", archive.ID)
+		s.archiveService.updateJob(ctx, archive, map[string]any{
+			"status": models.ArchiveJobStatusFailed,
+			"error":  stepErr.Error(),
+		})
+	}
+
+	// Start a fresh archive on the next attempt
+	job.ArchiveJobID = nil
+	if err := s.db.WithContext(ctx).Model(job).Update("archive_job_id", nil).Error; err != nil {
+		return "", false, fmt.Errorf("failed to reset archive job: %w", err)
+	}
+	return "", false, stepErr
+}
+
+// notifyOwner emails the owner once. The notified_at claim keeps a retried
+// step, or another instance, from sending it again; it is dropped again when
+// the email fails, so the retry sends it.
+func (s *WrapUpService) notifyOwner(ctx context.Context, job *models.WrapUpJob) error {
+	event, err := s.eventService.GetEventByID(ctx, job.EventID)
+	if err != nil {
+		return err
+	}
+
+	lang := emailLang(ctx, s.db, event.OwnerEmail)
+
+	var body strings.Builder
+	body.WriteString(i18n.Sprintf(lang, "Photo uploads to \"%s\" have closed.\n\n", event.Name))
+
+	if job.ArchiveJobID != nil {
+		archive, err := s.archiveService.GetJob(ctx, *job.ArchiveJobID)
+		if err != nil {
+			return err
+		}
+		// The link must not outlive the archive, nor the photos it was built from
+		expiry := min(emailedDownloadExpiry, s.archiveService.retention)
+		if event.PurgeAt != nil {
+			expiry = min(expiry, time.Until(*event.PurgeAt))
+		}
+		downloadURL, expiresAt, err := s.archiveService.DownloadURL(ctx, archive, expiry)
+		if err != nil {
+			return err
+		}
+		body.WriteString(i18n.Sprintf(lang, "An archive of %d photos can be downloaded until %s:\n%s\n",
+			archive.PhotoCount, expiresAt.Format("2006-01-02 15:04"), downloadURL))
+	} else {
+		body.WriteString(i18n.T(lang, "No photos were uploaded to this event.\n"))
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.WrapUpJob{}).
+		Where("id = ? AND notified_at IS NULL", job.ID).
+		Update("notified_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to claim wrap-up notification: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	err = s.mailer.Send(ctx, mail.Message{
+		To:      event.OwnerEmail,
+		Subject: i18n.Sprintf(lang, "[SnapShare] Photo archive of %s", event.Name),
+		Body:    body.String(),
+	})
+	if err != nil {
+		if err := s.db.WithContext(ctx).Model(job).Update("notified_at", nil).Error; err != nil {
+			slog.ErrorContext(ctx, "failed to release wrap-up notification", "job_id", job.ID, "error", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *WrapUpService) recordFailure(ctx context.Context, job *models.WrapUpJob, stepErr error) {
+	slog.ErrorContext(ctx, "wrap-up step failed", "job_id", job.ID, "step", job.Step, "error", stepErr)
+
+	job.Attempts++
+	errMsg := stepErr.Error()
+	job.Error = &errMsg
+	updates := map[string]any{"attempts": job.Attempts, "error": errMsg}
+	if job.Attempts >= maxWrapUpAttempts {
+		job.Status = models.WrapUpStatusFailed
+		updates["status"] = job.Status
+	}
+
+	if err := s.db.WithContext(ctx).Model(job).Updates(updates).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to record wrap-up failure", "job_id", job.ID, "error", err)
+	}
+}
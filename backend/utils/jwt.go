@@ -57,3 +57,75 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// ownerAudience marks access tokens issued to event owners
+const ownerAudience = "owner"
+
+type OwnerClaims struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+func GenerateOwnerJWT(userID uuid.UUID, email string, expiresAt time.Time) (string, error) {
+	claims := OwnerClaims{
+		UserID: userID.String(),
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{ownerAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+func ValidateOwnerJWT(tokenString string) (*OwnerClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &OwnerClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtSecret, nil
+	}, jwt.WithAudience(ownerAudience), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*OwnerClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// oauthStateAudience marks the signed state parameter of an OAuth sign-in
+const oauthStateAudience = "oauth_state"
+
+// GenerateOAuthState signs the state parameter for an OAuth sign-in with provider
+func GenerateOAuthState(provider string, expiresAt time.Time) (string, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   provider,
+		Audience:  jwt.ClaimStrings{oauthStateAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ID:        uuid.NewString(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// ValidateOAuthState checks a state parameter was issued by GenerateOAuthState for provider
+func ValidateOAuthState(state, provider string) error {
+	_, err := jwt.ParseWithClaims(state, &jwt.RegisteredClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtSecret, nil
+	}, jwt.WithAudience(oauthStateAudience), jwt.WithSubject(provider), jwt.WithExpirationRequired())
+
+	return err
+}
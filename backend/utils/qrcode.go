@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QRRecoveryLevel maps an error-correction letter (L, M, Q, H) to a recovery level
+func QRRecoveryLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrcode.Low, nil
+	case "", "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	default:
+		return 0, fmt.Errorf("invalid error correction level: %s", level)
+	}
+}
+
+// GenerateQRCodePNG renders content as a size x size PNG
+func GenerateQRCodePNG(content string, size int, level qrcode.RecoveryLevel) ([]byte, error) {
+	return qrcode.Encode(content, level, size)
+}
+
+// GenerateQRCodeSVG renders content as a size x size SVG
+func GenerateQRCodeSVG(content string, size int, level qrcode.RecoveryLevel) ([]byte, error) {
+	q, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	b.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,67 @@
+// Package version describes the running build. GitSHA and BuildTime are set
+// at build time:
+//
+//	go build -ldflags "-X snapShare/version.GitSHA=$(git rev-parse HEAD) \
+//		-X snapShare/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags -X; builds without them fall back to the VCS details
+// the go command stamps into binaries built inside a git checkout
+var (
+	GitSHA    = ""
+	BuildTime = ""
+)
+
+// Info identifies a build
+type Info struct {
+	GitSHA    string
+	BuildTime string
+	GoVersion string
+	// Modified is set when the binary was built from uncommitted changes
+	Modified bool
+}
+
+// Get returns the details of the running build. Unknown values are "unknown".
+func Get() Info {
+	info := Info{GitSHA: GitSHA, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitSHA == "" {
+					info.GitSHA = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.GitSHA == "" {
+		info.GitSHA = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
+
+// Short is the abbreviated commit the build came from, for health payloads
+// and logs
+func Short() string {
+	sha := Get().GitSHA
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
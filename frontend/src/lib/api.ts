@@ -54,7 +54,11 @@ class APIClient {
       let errorMessage = `HTTP ${response.status}`
       try {
         const error: APIError = await response.json()
-        errorMessage = error.message || errorMessage
+        errorMessage = error.detail || error.title || errorMessage
+        // Lets users quote the request when contacting support
+        if (error.request_id) {
+          errorMessage += `（お問い合わせ番号: ${error.request_id}）`
+        }
       } catch {
         errorMessage = response.statusText || errorMessage
       }
@@ -66,31 +70,31 @@ class APIClient {
 
   // Health check
   async healthCheck(): Promise<{ status: string }> {
-    return this.request("/health")
+    return this.request("/healthz")
   }
 
   // Event endpoints
   async getEventByCode(code: string): Promise<Event> {
-    return this.request(`/api/events/${code}`)
+    return this.request(`/api/v1/events/${code}`)
   }
 
   // Session endpoints
   async createSession(data: CreateSessionRequest): Promise<Session> {
-    return this.request("/api/sessions", {
+    return this.request("/api/v1/sessions", {
       method: "POST",
       body: JSON.stringify(data),
     })
   }
 
   async refreshSession(data: RefreshSessionRequest): Promise<Session> {
-    return this.request("/api/sessions/refresh", {
+    return this.request("/api/v1/sessions/refresh", {
       method: "POST",
       body: JSON.stringify(data),
     })
   }
 
   async revokeSession(data: RevokeSessionRequest): Promise<{ message: string }> {
-    return this.request("/api/sessions", {
+    return this.request("/api/v1/sessions", {
       method: "DELETE",
       body: JSON.stringify(data),
     })
@@ -98,7 +102,7 @@ class APIClient {
 
   // Photo endpoints
   async getUploadURL(data: UploadURLRequest): Promise<UploadURLResponse> {
-    return this.request("/api/photos/upload-url", {
+    return this.request("/api/v1/photos/upload-url", {
       method: "POST",
       body: JSON.stringify(data),
     })
@@ -108,7 +112,7 @@ class APIClient {
     photoId: string,
     data: ConfirmUploadRequest,
   ): Promise<{ message: string }> {
-    return this.request(`/api/photos/confirm/${photoId}`, {
+    return this.request(`/api/v1/photos/confirm/${photoId}`, {
       method: "POST",
       body: JSON.stringify(data),
     })
@@ -120,12 +124,18 @@ class APIClient {
     console.log("Upload URL:", uploadURL)
 
     try {
+      const headers: Record<string, string> = {
+        "Content-Type": file.type,
+      }
+      // Azure Blob SAS URLs (signed version "sv") need the blob type on Put Blob
+      if (new URL(uploadURL).searchParams.has("sv")) {
+        headers["x-ms-blob-type"] = "BlockBlob"
+      }
+
       const response = await fetch(uploadURL, {
         method: "PUT",
         body: file,
-        headers: {
-          "Content-Type": file.type,
-        },
+        headers,
       })
 
       console.log("Upload response status:", response.status)
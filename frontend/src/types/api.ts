@@ -61,8 +61,14 @@ export interface ConfirmUploadRequest {
   file_size: number
 }
 
-// Error Response
+// Error Response (RFC 7807 problem details)
 export interface APIError {
-  message: string
-  error?: string
+  type: string
+  title: string
+  status: number
+  code: string
+  detail?: string
+  instance?: string
+  request_id?: string
+  errors?: { field: string; rule: string; param?: string; message: string }[]
 }
\ No newline at end of file